@@ -0,0 +1,338 @@
+// Command devtoken mints JWTs for local development and can run as a tiny
+// OIDC issuer (discovery + JWKS) so the JWKS verification path can be tested
+// offline. Never use its keys or tokens outside development.
+//
+//	devtoken mint -alg HS256 -secret devsecret -sub 1 -roles admin
+//	devtoken mint -alg HS256 -secret "$(cat hs-2026-10.key)" -kid 2026-10
+//	devtoken serve -addr localhost:9999   # then AUTH_JWKS_URL=http://localhost:9999/.well-known/jwks.json
+//	devtoken mint -alg ES256 -sub 1 -aud api
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+)
+
+const defaultIssuer = "http://localhost:9999"
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "mint":
+		err = runMint(os.Args[2:])
+	case "serve":
+		err = runServe(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "devtoken:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: devtoken mint [flags] | devtoken serve [flags] (-h for flags)")
+	os.Exit(2)
+}
+
+// tokenSpec holds the claims chosen on the command line or /token query.
+type tokenSpec struct {
+	alg    string
+	sub    string
+	email  string
+	roles  string
+	aud    string
+	iss    string
+	acr    string
+	amr    string
+	exp    time.Duration
+	claims string
+}
+
+func (s *tokenSpec) bind(fs *flag.FlagSet) {
+	fs.StringVar(&s.alg, "alg", "HS256", "HS256 | RS256 | ES256")
+	fs.StringVar(&s.sub, "sub", "1", "sub claim")
+	fs.StringVar(&s.email, "email", "dev@example.com", "email claim (empty to omit)")
+	fs.StringVar(&s.roles, "roles", "user", "comma separated roles claim")
+	fs.StringVar(&s.aud, "aud", "", "aud claim (empty to omit)")
+	fs.StringVar(&s.iss, "iss", "", "iss claim (default: the serve issuer for RS256/ES256, omitted for HS256)")
+	fs.StringVar(&s.acr, "acr", "", "acr claim for step-up (empty to omit)")
+	fs.StringVar(&s.amr, "amr", "", "comma separated amr claim for step-up, e.g. pwd,mfa")
+	fs.DurationVar(&s.exp, "exp", time.Hour, "lifetime; negative values mint an expired token")
+	fs.StringVar(&s.claims, "claims", "", `extra claims as JSON, e.g. '{"tenant_id":"t1"}'`)
+}
+
+func (s tokenSpec) mapClaims(defaultIss string) (jwt.MapClaims, error) {
+	now := time.Now()
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return nil, err
+	}
+	c := jwt.MapClaims{
+		"sub": s.sub,
+		"iat": now.Unix(),
+		"exp": now.Add(s.exp).Unix(),
+		"jti": base64.RawURLEncoding.EncodeToString(jti),
+		// A minted token stands for a fresh login.
+		"auth_time": now.Unix(),
+	}
+	if s.email != "" {
+		c["email"] = s.email
+	}
+	if roles := splitComma(s.roles); len(roles) > 0 {
+		c["roles"] = roles
+	}
+	if s.aud != "" {
+		c["aud"] = s.aud
+	}
+	if s.acr != "" {
+		c["acr"] = s.acr
+	}
+	if amr := splitComma(s.amr); len(amr) > 0 {
+		c["amr"] = amr
+	}
+	if iss := s.iss; iss != "" {
+		c["iss"] = iss
+	} else if s.alg != "HS256" && defaultIss != "" {
+		c["iss"] = defaultIss
+	}
+	// Extra claims win, so any of the above can be overridden (e.g. iat, nbf, auth_time).
+	if s.claims != "" {
+		var extra map[string]any
+		if err := json.Unmarshal([]byte(s.claims), &extra); err != nil {
+			return nil, fmt.Errorf("parse claims: %w", err)
+		}
+		for k, v := range extra {
+			c[k] = v
+		}
+	}
+	return c, nil
+}
+
+func runMint(args []string) error {
+	fs := flag.NewFlagSet("mint", flag.ExitOnError)
+	var spec tokenSpec
+	spec.bind(fs)
+	secret := fs.String("secret", os.Getenv("AUTH_HS256_SECRET"), "HS256 secret (default $AUTH_HS256_SECRET)")
+	kid := fs.String("kid", "", "HS256 kid header, selecting a key of the AUTH_HS256_KEYS keyring")
+	keyDir := fs.String("keys", ".devtoken", "directory holding the RS256/ES256 signing keys (created on first use)")
+	_ = fs.Parse(args)
+
+	claims, err := spec.mapClaims(defaultIssuer)
+	if err != nil {
+		return err
+	}
+	var tok string
+	if spec.alg == "HS256" {
+		if *secret == "" {
+			return errors.New("HS256 needs -secret or AUTH_HS256_SECRET")
+		}
+		t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		if *kid != "" {
+			t.Header["kid"] = *kid
+		}
+		tok, err = t.SignedString([]byte(*secret))
+	} else {
+		var ks *keySet
+		if ks, err = loadKeySet(*keyDir); err == nil {
+			tok, err = ks.sign(spec.alg, claims)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println(tok)
+	return nil
+}
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	// /token is unauthenticated and mints tokens with any roles, so listen on
+	// loopback unless another address is given explicitly.
+	addr := fs.String("addr", "localhost:9999", "listen address")
+	issuer := fs.String("issuer", defaultIssuer, "issuer URL as seen by the API (iss claim and discovery)")
+	keyDir := fs.String("keys", ".devtoken", "directory holding the RS256/ES256 signing keys (created on first use)")
+	_ = fs.Parse(args)
+
+	ks, err := loadKeySet(*keyDir)
+	if err != nil {
+		return err
+	}
+	iss := strings.TrimSuffix(*issuer, "/")
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"issuer":                                iss,
+			"jwks_uri":                              iss + "/.well-known/jwks.json",
+			"token_endpoint":                        iss + "/token",
+			"id_token_signing_alg_values_supported": []string{"RS256", "ES256"},
+			"subject_types_supported":               []string{"public"},
+			"response_types_supported":              []string{"id_token"},
+		})
+	})
+	mux.HandleFunc("GET /.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"keys": ks.jwks})
+	})
+	// /token mints a token from query parameters named like the mint flags
+	// (alg defaults to RS256 here), e.g. /token?sub=2&roles=admin&aud=api.
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		spec := tokenSpec{alg: "RS256", sub: "1", email: "dev@example.com", roles: "user", exp: time.Hour}
+		for key, dst := range map[string]*string{"alg": &spec.alg, "sub": &spec.sub, "email": &spec.email, "roles": &spec.roles, "aud": &spec.aud, "iss": &spec.iss, "acr": &spec.acr, "amr": &spec.amr, "claims": &spec.claims} {
+			if q.Has(key) {
+				*dst = q.Get(key)
+			}
+		}
+		if s := q.Get("exp"); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				http.Error(w, "invalid exp", http.StatusBadRequest)
+				return
+			}
+			spec.exp = d
+		}
+		claims, err := spec.mapClaims(iss)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		tok, err := ks.sign(spec.alg, claims)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]any{"access_token": tok, "token_type": "Bearer", "expires_in": int64(spec.exp.Seconds())})
+	})
+
+	log.Printf("devtoken issuer %s listening on %s (DEVELOPMENT ONLY)", iss, *addr)
+	log.Printf(`configure the API with AUTH_ISSUERS='[{"name":"devtoken","issuer":%q,"jwks_url":%q}]'`, iss, iss+"/.well-known/jwks.json")
+	log.Printf("get a token: curl -s '%s/token?sub=1&roles=admin'", iss)
+	return http.ListenAndServe(*addr, mux)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// keySet is the RS256 and ES256 signing keys persisted in a directory, so
+// that tokens minted by `mint` verify against the JWKS served by `serve`.
+type keySet struct {
+	keys map[string]signingKey
+	jwks []auth.JWK
+}
+
+type signingKey struct {
+	kid    string
+	method jwt.SigningMethod
+	key    crypto.Signer
+}
+
+func loadKeySet(dir string) (*keySet, error) {
+	ks := &keySet{keys: map[string]signingKey{}}
+	for _, alg := range []string{"RS256", "ES256"} {
+		key, err := loadOrCreateKey(filepath.Join(dir, strings.ToLower(alg)+".pem"), alg)
+		if err != nil {
+			return nil, err
+		}
+		der, err := x509.MarshalPKIXPublicKey(key.Public())
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(der)
+		kid := strings.ToLower(alg) + "-" + base64.RawURLEncoding.EncodeToString(sum[:8])
+		jwk, err := auth.EncodeJWK(kid, alg, key.Public())
+		if err != nil {
+			return nil, err
+		}
+		ks.keys[alg] = signingKey{kid: kid, method: jwt.GetSigningMethod(alg), key: key}
+		ks.jwks = append(ks.jwks, jwk)
+	}
+	return ks, nil
+}
+
+func (ks *keySet) sign(alg string, claims jwt.MapClaims) (string, error) {
+	k, ok := ks.keys[alg]
+	if !ok {
+		return "", fmt.Errorf("unsupported alg %q (RS256 | ES256)", alg)
+	}
+	tok := jwt.NewWithClaims(k.method, claims)
+	tok.Header["kid"] = k.kid
+	return tok.SignedString(k.key)
+}
+
+func loadOrCreateKey(path, alg string) (crypto.Signer, error) {
+	if b, err := os.ReadFile(path); err == nil {
+		block, _ := pem.Decode(b)
+		if block == nil {
+			return nil, fmt.Errorf("%s: no PEM block", path)
+		}
+		k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		signer, ok := k.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("%s: not a signing key", path)
+		}
+		return signer, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	var key crypto.Signer
+	var err error
+	if alg == "RS256" {
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	} else {
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func splitComma(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
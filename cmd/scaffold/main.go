@@ -21,6 +21,8 @@ type Field struct {
 	DBName    string
 	IsID      bool
 	IsTS      bool
+	// Constraint is the buf.validate field option (including brackets) for request messages.
+	Constraint string
 }
 
 type Model struct {
@@ -33,15 +35,68 @@ type Model struct {
 	GoPackagePath string
 	GoPkgName     string
 	Fields        []Field
+	// Authz maps an RPC kind (Create/Get/List/Update/Delete) to its (auth.authz) rule.
+	Authz map[string]*AuthzRule
+	// Public lists RPC kinds marked (auth.public); PublicService marks the whole service.
+	Public        []string
+	PublicService bool
+}
+
+// AuthzRule is the (auth.authz) method option emitted into the proto.
+type AuthzRule struct {
+	Roles       []string
+	Permissions []string
+}
+
+var rpcKinds = []string{"Create", "Get", "List", "Update", "Delete"}
+
+// HasAuthz reports whether any RPC carries an (auth.authz) option.
+func (m Model) HasAuthz() bool { return len(m.Authz) > 0 }
+
+// HasAuthOptions reports whether the proto needs to import auth/options.proto.
+func (m Model) HasAuthOptions() bool { return m.HasAuthz() || len(m.Public) > 0 || m.PublicService }
+
+// RPCBody renders the body of an rpc declaration, including its (auth.public) / (auth.authz) options.
+func (m Model) RPCBody(kind string) string {
+	var opts []string
+	if containsString(m.Public, kind) {
+		opts = append(opts, "option (auth.public) = true;")
+	}
+	if r, ok := m.Authz[kind]; ok {
+		var parts []string
+		if len(r.Roles) > 0 {
+			parts = append(parts, "roles: "+protoStrings(r.Roles))
+		}
+		if len(r.Permissions) > 0 {
+			parts = append(parts, "permissions: "+protoStrings(r.Permissions))
+		}
+		opts = append(opts, "option (auth.authz) = { "+strings.Join(parts, ", ")+" };")
+	}
+	if len(opts) == 0 {
+		return "{}"
+	}
+	return "{\n    " + strings.Join(opts, "\n    ") + "\n  }"
+}
+
+func protoStrings(ss []string) string {
+	q := make([]string, len(ss))
+	for i, s := range ss {
+		q[i] = fmt.Sprintf("%q", s)
+	}
+	return "[" + strings.Join(q, ", ") + "]"
 }
 
 func main() {
 	var name string
 	var fields string
 	var withMemory bool
+	var roles, perms, public string
 	flag.StringVar(&name, "name", "", "Entity name in PascalCase, e.g. User")
 	flag.StringVar(&fields, "fields", "", `Fields, e.g. "name:string email:string age:int"`)
 	flag.BoolVar(&withMemory, "with-memory", false, "also generate in-memory repository implementation")
+	flag.StringVar(&roles, "roles", "", `Required roles per RPC (any of), e.g. "Create,Update=admin,editor Delete=admin" ("*" = all RPCs)`)
+	flag.StringVar(&perms, "perms", "", `Required permissions per RPC (all of), e.g. "Delete=article.delete"`)
+	flag.StringVar(&public, "public", "", `RPCs that skip authentication, e.g. "Get,List" ("*" = whole service)`)
 	flag.Parse()
 
 	if strings.TrimSpace(name) == "" {
@@ -60,6 +115,12 @@ func main() {
 	if err != nil {
 		exitErr(err)
 	}
+	if m.Authz, err = parseAuthz(roles, perms); err != nil {
+		exitErr(err)
+	}
+	if m.Public, m.PublicService, err = parsePublic(public); err != nil {
+		exitErr(err)
+	}
 
 	if err := writeFromTemplate("proto", filepath.Join("proto", m.NameLower, "v1", m.NameLower+".proto"), protoTmpl, m); err != nil {
 		exitErr(err)
@@ -177,17 +238,80 @@ func parseFields(s string) ([]Field, error) {
 			return nil, fmt.Errorf("field %s: %q is a reserved SQL identifier; choose a different name (e.g., %s_col)", name, dbname, dbname)
 		}
 		out = append(out, Field{
-			Name:      name,
-			GoName:    toPascal(name),
-			ProtoType: protoType,
-			SQLType:   sqlType,
-			JSONName:  toSnake(name),
-			DBName:    dbname,
+			Name:       name,
+			GoName:     toPascal(name),
+			ProtoType:  protoType,
+			SQLType:    sqlType,
+			JSONName:   toSnake(name),
+			DBName:     dbname,
+			Constraint: constraintFor(typ),
 		})
 	}
 	return out, nil
 }
 
+// parseAuthz parses -roles / -perms specs of the form "Kind[,Kind]=v1,v2 ...".
+func parseAuthz(roles, perms string) (map[string]*AuthzRule, error) {
+	out := map[string]*AuthzRule{}
+	apply := func(spec string, set func(r *AuthzRule, vals []string)) error {
+		for _, p := range strings.Fields(spec) {
+			kv := strings.SplitN(p, "=", 2)
+			if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+				return fmt.Errorf("invalid authz spec: %q (expected Kind=v1,v2)", p)
+			}
+			vals := strings.Split(kv[1], ",")
+			for _, kind := range strings.Split(kv[0], ",") {
+				kinds := []string{kind}
+				if kind == "*" {
+					kinds = rpcKinds
+				} else if !containsString(rpcKinds, kind) {
+					return fmt.Errorf("unknown RPC kind %q (supported: %s, *)", kind, strings.Join(rpcKinds, ","))
+				}
+				for _, k := range kinds {
+					if out[k] == nil {
+						out[k] = &AuthzRule{}
+					}
+					set(out[k], vals)
+				}
+			}
+		}
+		return nil
+	}
+	if err := apply(roles, func(r *AuthzRule, v []string) { r.Roles = append(r.Roles, v...) }); err != nil {
+		return nil, err
+	}
+	if err := apply(perms, func(r *AuthzRule, v []string) { r.Permissions = append(r.Permissions, v...) }); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// parsePublic parses the -public spec "Kind[,Kind]" ("*" marks the whole service).
+func parsePublic(spec string) ([]string, bool, error) {
+	var kinds []string
+	for _, k := range strings.Split(strings.ReplaceAll(spec, " ", ","), ",") {
+		switch {
+		case k == "":
+		case k == "*":
+			return nil, true, nil
+		case !containsString(rpcKinds, k):
+			return nil, false, fmt.Errorf("unknown RPC kind %q (supported: %s, *)", k, strings.Join(rpcKinds, ","))
+		default:
+			kinds = append(kinds, k)
+		}
+	}
+	return kinds, false, nil
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 func mapType(t string) (protoType, sqlType string, err error) {
 	switch strings.ToLower(t) {
 	case "string":
@@ -213,6 +337,23 @@ func mapType(t string) (protoType, sqlType string, err error) {
 	}
 }
 
+// constraintFor returns the buf.validate option that keeps a request value
+// within the range of its SQL column.
+func constraintFor(t string) string {
+	switch strings.ToLower(t) {
+	case "string":
+		return " [(buf.validate.field).string.max_len = 255]"
+	case "text":
+		return " [(buf.validate.field).string.max_bytes = 65535]"
+	case "int8":
+		return " [(buf.validate.field).int32 = {gte: -128, lte: 127}]"
+	case "uint8":
+		return " [(buf.validate.field).uint32.lte = 255]"
+	default:
+		return ""
+	}
+}
+
 func writeFromTemplate(label, path, tmpl string, m Model) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return err
@@ -315,15 +456,22 @@ func toPascal(s string) string {
 const protoTmpl = `syntax = "proto3";
 
 package {{.ProtoPackage}};
+{{ if .HasAuthOptions }}
+import "auth/options.proto";
+{{- end }}
+import "buf/validate/validate.proto";
 
 option go_package = "{{.GoPackagePath}}";
 
 service {{.Name}}Service {
-  rpc Create{{.Name}}(Create{{.Name}}Request) returns (Create{{.Name}}Response) {}
-  rpc Get{{.Name}}(Get{{.Name}}Request) returns (Get{{.Name}}Response) {}
-  rpc List{{.Name}}s(List{{.Name}}sRequest) returns (List{{.Name}}sResponse) {}
-  rpc Update{{.Name}}(Update{{.Name}}Request) returns (Update{{.Name}}Response) {}
-  rpc Delete{{.Name}}(Delete{{.Name}}Request) returns (Delete{{.Name}}Response) {}
+{{- if .PublicService }}
+  option (auth.public_service) = true;
+{{ end }}
+  rpc Create{{.Name}}(Create{{.Name}}Request) returns (Create{{.Name}}Response) {{.RPCBody "Create"}}
+  rpc Get{{.Name}}(Get{{.Name}}Request) returns (Get{{.Name}}Response) {{.RPCBody "Get"}}
+  rpc List{{.Name}}s(List{{.Name}}sRequest) returns (List{{.Name}}sResponse) {{.RPCBody "List"}}
+  rpc Update{{.Name}}(Update{{.Name}}Request) returns (Update{{.Name}}Response) {{.RPCBody "Update"}}
+  rpc Delete{{.Name}}(Delete{{.Name}}Request) returns (Delete{{.Name}}Response) {{.RPCBody "Delete"}}
 }
 
 message {{.Name}} {
@@ -334,26 +482,30 @@ message {{.Name}} {
 }
 message Create{{.Name}}Request {
 {{- range $i, $f := .Fields }}
-  {{$f.ProtoType}} {{$f.JSONName}} = {{inc $i}};
+  {{$f.ProtoType}} {{$f.JSONName}} = {{inc $i}}{{$f.Constraint}};
 {{- end }}
 }
 message Create{{.Name}}Response { {{.Name}} {{.NameLower}} = 1; }
 
-message Get{{.Name}}Request { int64 id = 1; }
+message Get{{.Name}}Request {
+  int64 id = 1 [(buf.validate.field).int64.gt = 0];
+}
 message Get{{.Name}}Response { {{.Name}} {{.NameLower}} = 1; }
 
 message List{{.Name}}sRequest {}
 message List{{.Name}}sResponse { repeated {{.Name}} {{.NameLower}}s = 1; }
 
 message Update{{.Name}}Request {
-  int64 id = 1;
+  int64 id = 1 [(buf.validate.field).int64.gt = 0];
 {{- range $i, $f := .Fields }}
-  {{$f.ProtoType}} {{$f.JSONName}} = {{add2 $i}};
+  {{$f.ProtoType}} {{$f.JSONName}} = {{add2 $i}}{{$f.Constraint}};
 {{- end }}
 }
 message Update{{.Name}}Response { {{.Name}} {{.NameLower}} = 1; }
 
-message Delete{{.Name}}Request { int64 id = 1; }
+message Delete{{.Name}}Request {
+  int64 id = 1 [(buf.validate.field).int64.gt = 0];
+}
 message Delete{{.Name}}Response {}
 `
 
@@ -409,6 +561,7 @@ import (
     "{{.Module}}/internal/domain/entity"
 )
 
+// {{.Name}}Repository の Get/Update/Delete は対象の ID が存在しない場合 apperr.NotFound のエラーを返します。
 type {{.Name}}Repository interface {
     Create(ctx context.Context, in *entity.{{.Name}}) (*entity.{{.Name}}, error)
     Get(ctx context.Context, id int64) (*entity.{{.Name}}, error)
@@ -540,7 +693,8 @@ func register{{.Name}}(mux *http.ServeMux, deps Deps) {
     repo := mysqlrepo.New{{.Name}}Repository(deps.Gorm)
     uc := usecase.New{{.Name}}Usecase(repo)
     h := New{{.Name}}Handler(uc)
-    path, handler := {{.GoPkgName}}connect.New{{.Name}}ServiceHandler(h)
+    // authentication by default; (auth.public) / (auth.authz) in the proto adjust it per RPC
+    path, handler := {{.GoPkgName}}connect.New{{.Name}}ServiceHandler(h, deps.AuthInterceptors())
     mux.Handle(path, handler)
 }
 `
@@ -549,8 +703,13 @@ const repoMemoryTmpl = `package memory
 
 import (
     "context"
+    "log/slog"
+    "strconv"
     "sync"
 
+    "github.com/newmo-oss/ergo"
+
+    "{{.Module}}/internal/apperr"
     "{{.Module}}/internal/domain"
     "{{.Module}}/internal/domain/entity"
     domainrepo "{{.Module}}/internal/domain/repository"
@@ -581,7 +740,7 @@ func (r *{{.Name}}Repository) Get(ctx context.Context, id int64) (*entity.{{.Nam
     defer r.mu.Unlock()
     v, ok := r.data[id]
     if !ok {
-        return nil, nil
+        return nil, r.notFound(id)
     }
     cp := *v
     return &cp, nil
@@ -606,7 +765,7 @@ func (r *{{.Name}}Repository) Update(ctx context.Context, in *entity.{{.Name}})
     r.mu.Lock()
     defer r.mu.Unlock()
     if _, ok := r.data[in.ID]; !ok {
-        return nil, nil
+        return nil, r.notFound(in.ID)
     }
     cp := *in
     r.data[cp.ID] = &cp
@@ -616,9 +775,16 @@ func (r *{{.Name}}Repository) Update(ctx context.Context, in *entity.{{.Name}})
 func (r *{{.Name}}Repository) Delete(ctx context.Context, id int64) error {
     r.mu.Lock()
     defer r.mu.Unlock()
+    if _, ok := r.data[id]; !ok {
+        return r.notFound(id)
+    }
     delete(r.data, id)
     return nil
 }
+
+func (r *{{.Name}}Repository) notFound(id int64) error {
+    return ergo.WithCode(ergo.New("{{.NameLower}} not found", slog.Int64("id", id), apperr.Resource("{{.NameLower}}", strconv.FormatInt(id, 10))), apperr.NotFound)
+}
 `
 
 const repoMySQLTmpl = `package mysql
@@ -630,8 +796,6 @@ import (
 
     "gorm.io/gorm"
 
-    "{{.Module}}/internal/apperr"
-    "github.com/newmo-oss/ergo"
     "{{.Module}}/internal/domain"
     "{{.Module}}/internal/domain/entity"
     domainrepo "{{.Module}}/internal/domain/repository"
@@ -659,7 +823,7 @@ func (r *{{.Name}}Repository) Create(ctx context.Context, in *entity.{{.Name}})
 {{- end }}
     }
     if err := r.db.WithContext(ctx).Create(&m).Error; err != nil {
-        return nil, ergo.WithCode(ergo.Wrap(err, "gorm Create {{.Table}}"), apperr.Internal)
+        return nil, wrapDBError(err, "gorm Create {{.Table}}")
     }
     out := *in
     out.ID = m.ID
@@ -670,9 +834,9 @@ func (r *{{.Name}}Repository) Get(ctx context.Context, id int64) (*entity.{{.Nam
     var m {{.Name}}Model
     if err := r.db.WithContext(ctx).First(&m, id).Error; err != nil {
         if errors.Is(err, gorm.ErrRecordNotFound) {
-            return nil, nil
+            return nil, notFoundError("{{.NameLower}}", id)
         }
-        return nil, ergo.WithCode(ergo.Wrap(err, "gorm First {{.Table}}"), apperr.Internal)
+        return nil, wrapDBError(err, "gorm First {{.Table}}")
     }
     return &entity.{{.Name}}{
         ID: m.ID,
@@ -687,7 +851,7 @@ func (r *{{.Name}}Repository) List(ctx context.Context, p domain.ListParams) ([]
     p = p.Sanitize()
     q := r.db.WithContext(ctx).Order("id DESC").Offset(p.Offset).Limit(p.Limit)
     if err := q.Find(&rows).Error; err != nil {
-        return nil, ergo.WithCode(ergo.Wrap(err, "gorm Find {{.Table}}"), apperr.Internal)
+        return nil, wrapDBError(err, "gorm Find {{.Table}}")
     }
     out := make([]*entity.{{.Name}}, 0, len(rows))
     for _, m := range rows {
@@ -710,14 +874,18 @@ func (r *{{.Name}}Repository) Update(ctx context.Context, in *entity.{{.Name}})
         "updated_at": time.Now(),
     }
     if err := r.db.WithContext(ctx).Model(&{{.Name}}Model{}).Where("id = ?", in.ID).Updates(updates).Error; err != nil {
-        return nil, ergo.WithCode(ergo.Wrap(err, "gorm Updates {{.Table}}"), apperr.Internal)
+        return nil, wrapDBError(err, "gorm Updates {{.Table}}")
     }
     return r.Get(ctx, in.ID)
 }
 
 func (r *{{.Name}}Repository) Delete(ctx context.Context, id int64) error {
-    if err := r.db.WithContext(ctx).Delete(&{{.Name}}Model{}, id).Error; err != nil {
-        return ergo.WithCode(ergo.Wrap(err, "gorm Delete {{.Table}}"), apperr.Internal)
+    res := r.db.WithContext(ctx).Delete(&{{.Name}}Model{}, id)
+    if res.Error != nil {
+        return wrapDBError(res.Error, "gorm Delete {{.Table}}")
+    }
+    if res.RowsAffected == 0 {
+        return notFoundError("{{.NameLower}}", id)
     }
     return nil
 }
@@ -1,14 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	grpcadapter "github.com/xiao1203/go-onion-grpc-template/internal/adapter/grpc"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
 	inframysql "github.com/xiao1203/go-onion-grpc-template/internal/infra/mysql"
 )
 
+// shutdownTimeout bounds the graceful shutdown: in-flight requests and the
+// flush of queued auth events.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	mux := http.NewServeMux()
 
@@ -20,9 +31,60 @@ func main() {
     if sqlDB, err := db.DB(); err == nil {
         defer func() { _ = sqlDB.Close() }()
     }
-	grpcadapter.RegisterAll(mux, grpcadapter.Deps{Gorm: db})
+	closeDeps := grpcadapter.RegisterAll(mux, grpcadapter.Deps{Gorm: db})
+	// Everything else requires authentication; make the exceptions visible.
+	for _, p := range grpcadapter.PublicProcedures(mux) {
+		log.Printf("public procedure (no auth): %s", p)
+	}
+	if dev, err := auth.DevBypassFromEnv(); err != nil {
+		log.Printf("WARNING: DEV_AUTH_BYPASS ignored: %v", err)
+	} else if dev != nil {
+		log.Printf("!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!")
+		log.Printf("WARNING: DEV_AUTH_BYPASS is ON - authentication is DISABLED")
+		log.Printf("WARNING: every request runs as user %d %v (override with X-Dev-* headers)", dev.UserID, dev.Roles)
+		log.Printf("!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!")
+	}
 
 	addr := ":8080"
-	fmt.Printf("listening on %s\n", addr)
-	log.Fatal(http.ListenAndServe(addr, mux))
+	// TLS_CERT_FILE switches to HTTPS; TLS_CLIENT_CA_FILE additionally accepts client certificates (mTLS).
+	tlsCfg, err := auth.LoadServerTLSConfigFromEnv()
+	if err != nil {
+		log.Fatalf("tls config: %v", err)
+	}
+	srv := &http.Server{Addr: addr, Handler: mux}
+	if tlsCfg != nil {
+		srv.Handler, srv.TLSConfig = grpcadapter.WithPeerCertificate(mux), tlsCfg
+	}
+
+	// Stop on SIGINT / SIGTERM: finish in-flight requests, then flush the
+	// queued auth events before the process exits.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	serveErr := make(chan error, 1)
+	go func() {
+		if tlsCfg == nil {
+			fmt.Printf("listening on %s\n", addr)
+			serveErr <- srv.ListenAndServe()
+			return
+		}
+		fmt.Printf("listening on %s (tls, client auth: %s)\n", addr, tlsCfg.ClientAuth)
+		serveErr <- srv.ListenAndServeTLS("", "")
+	}()
+	var listenErr error
+	select {
+	case listenErr = <-serveErr:
+	case <-ctx.Done():
+		log.Printf("shutting down")
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("shutdown: %v", err)
+	}
+	if err := closeDeps(shutdownCtx); err != nil {
+		log.Printf("flush: %v", err)
+	}
+	if listenErr != nil && !errors.Is(listenErr, http.ErrServerClosed) {
+		log.Fatal(listenErr)
+	}
 }
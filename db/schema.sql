@@ -58,10 +58,11 @@ CREATE TABLE user_roles (
 
 -- セッション（BFF+Cookie採用時のみ）
 CREATE TABLE sessions (
-  id CHAR(36) NOT NULL COMMENT 'セッションID（UUID）',
+  id CHAR(64) NOT NULL COMMENT 'セッションID（UUID）の SHA-256（16進）。ID自体は保存しない',
   user_id BIGINT UNSIGNED NOT NULL COMMENT 'users.id への参照',
   user_agent VARCHAR(255) NULL COMMENT 'ユーザーエージェント（任意）',
   ip_address VARCHAR(64) NULL COMMENT 'アクセス元IP（任意）',
+  csrf_token VARCHAR(64) NOT NULL COMMENT 'CSRFトークン（変更系RPCで X-CSRF-Token ヘッダと照合）',
   expires_at DATETIME(6) NOT NULL COMMENT '有効期限',
   revoked_at DATETIME(6) NULL COMMENT '失効時刻（NULLなら有効）',
   created_at DATETIME(6) NOT NULL COMMENT '作成時刻',
@@ -71,6 +72,108 @@ CREATE TABLE sessions (
     ON DELETE CASCADE ON UPDATE RESTRICT
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='BFF+Cookie採用時のサーバサイドセッション';
 
+-- トークン失効（JWTのjti単位 / ユーザー単位）
+CREATE TABLE revoked_tokens (
+  jti VARCHAR(255) NOT NULL COMMENT '失効させたトークンのjti',
+  user_id BIGINT UNSIGNED NULL COMMENT 'トークンの持ち主 users.id（任意）',
+  expires_at DATETIME(6) NOT NULL COMMENT 'トークン本来の有効期限（これ以降は行を削除してよい）',
+  revoked_at DATETIME(6) NOT NULL COMMENT '失効時刻',
+  PRIMARY KEY (jti),
+  KEY idx_revoked_tokens_expires (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='jti単位で失効させたJWT（denylist）';
+
+CREATE TABLE user_token_revocations (
+  user_id BIGINT UNSIGNED NOT NULL COMMENT 'users.id への参照',
+  revoked_before DATETIME(6) NOT NULL COMMENT 'この時刻以前に発行（iat）されたトークンを無効とする',
+  updated_at DATETIME(6) NOT NULL COMMENT '更新時刻',
+  PRIMARY KEY (user_id),
+  CONSTRAINT fk_user_token_revocations_user FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='ユーザー単位のトークン一括失効（発行時刻の下限）';
+
+-- APIキー（サービス間連携・バッチ用）
+CREATE TABLE api_keys (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'APIキーの内部ID',
+  name VARCHAR(255) NOT NULL COMMENT '用途の説明（例: nightly-batch）',
+  prefix VARCHAR(16) NOT NULL COMMENT '識別用プレフィックス（キー平文の先頭。一覧・ログ表示用）',
+  key_hash CHAR(64) NOT NULL COMMENT 'キー平文のSHA-256（16進）。平文は保存しない',
+  owner_user_id BIGINT UNSIGNED NOT NULL COMMENT '所有者 users.id への参照',
+  scopes VARCHAR(1024) NOT NULL DEFAULT '' COMMENT '許可スコープ（スペース区切り）',
+  expires_at DATETIME(6) NULL COMMENT '有効期限（NULLなら無期限）',
+  last_used_at DATETIME(6) NULL COMMENT '最終利用時刻',
+  revoked_at DATETIME(6) NULL COMMENT '失効時刻（NULLなら有効）',
+  created_at DATETIME(6) NOT NULL COMMENT '作成時刻',
+  updated_at DATETIME(6) NOT NULL COMMENT '更新時刻',
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_api_keys_hash (key_hash),
+  UNIQUE KEY uk_api_keys_prefix (prefix),
+  KEY idx_api_keys_owner (owner_user_id),
+  CONSTRAINT fk_api_keys_owner FOREIGN KEY (owner_user_id) REFERENCES users(id)
+    ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='サービス間連携用のAPIキー（ハッシュのみ保存）';
+
+-- ローカル認証（メール/パスワード。外部IdPを使わない構成向け）
+CREATE TABLE user_credentials (
+  user_id BIGINT UNSIGNED NOT NULL COMMENT 'users.id への参照',
+  password_hash VARCHAR(255) NOT NULL COMMENT 'パスワードのargon2idハッシュ（PHC形式。パラメータとソルトを含む）',
+  failed_attempts INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '連続ログイン失敗回数（成功時・ロック時に0へ戻す）',
+  locked_until DATETIME(6) NULL COMMENT 'ロック解除時刻（NULLならロックなし）',
+  password_changed_at DATETIME(6) NOT NULL COMMENT 'パスワード設定・変更時刻',
+  created_at DATETIME(6) NOT NULL COMMENT '作成時刻',
+  updated_at DATETIME(6) NOT NULL COMMENT '更新時刻',
+  PRIMARY KEY (user_id),
+  CONSTRAINT fk_user_credentials_user FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='ローカル認証のパスワード（ハッシュのみ保存）とロック状態';
+
+CREATE TABLE password_reset_tokens (
+  token_hash CHAR(64) NOT NULL COMMENT 'リセットトークン平文のSHA-256（16進）。平文は保存しない',
+  user_id BIGINT UNSIGNED NOT NULL COMMENT 'users.id への参照',
+  expires_at DATETIME(6) NOT NULL COMMENT '有効期限',
+  used_at DATETIME(6) NULL COMMENT '使用時刻（NULLなら未使用。1回限り有効）',
+  created_at DATETIME(6) NOT NULL COMMENT '作成時刻',
+  PRIMARY KEY (token_hash),
+  KEY idx_password_reset_tokens_user (user_id),
+  CONSTRAINT fk_password_reset_tokens_user FOREIGN KEY (user_id) REFERENCES users(id)
+    ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='パスワードリセット用の一時トークン';
+
+-- なりすまし（管理者が他ユーザーとして操作）の監査ログ
+CREATE TABLE impersonation_logs (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '監査ログの内部ID',
+  actor_user_id BIGINT UNSIGNED NOT NULL COMMENT '操作した管理者の users.id',
+  actor_subject VARCHAR(255) NOT NULL COMMENT '管理者の認証時のsubject（IdP上のID）',
+  target_user_id BIGINT UNSIGNED NOT NULL COMMENT 'なりすまし対象の users.id',
+  `procedure` VARCHAR(255) NOT NULL COMMENT '呼び出したRPC（例: /user.v1.UserService/GetMe）',
+  reason VARCHAR(255) NOT NULL DEFAULT '' COMMENT '理由（X-Impersonate-Reason。任意）',
+  allowed TINYINT(1) NOT NULL COMMENT '実行を許可したか（0なら拒否）',
+  denied_reason VARCHAR(255) NOT NULL DEFAULT '' COMMENT '拒否理由（許可時は空）',
+  created_at DATETIME(6) NOT NULL COMMENT '記録時刻',
+  PRIMARY KEY (id),
+  KEY idx_impersonation_logs_actor (actor_user_id, created_at),
+  KEY idx_impersonation_logs_target (target_user_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='なりすまし呼び出しの監査ログ（拒否も含む）';
+
+-- 認証イベント（成功・失敗）。インターセプタが非同期に記録する
+CREATE TABLE auth_events (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'イベントの内部ID',
+  outcome VARCHAR(16) NOT NULL COMMENT '結果（success / failure）',
+  reason VARCHAR(64) NOT NULL DEFAULT '' COMMENT '失敗理由（expired / bad_audience / unknown_kid など。成功時は空）',
+  method VARCHAR(32) NOT NULL DEFAULT '' COMMENT '資格情報の種類（bearer / apikey / session / mtls / dev。無しは空）',
+  provider VARCHAR(64) NOT NULL DEFAULT '' COMMENT '検証したプロバイダ（信頼するIssuerの名前 / hs256 など）',
+  issuer VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'トークンのiss（失敗時は未検証の値）',
+  subject VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'トークンのsub（失敗時は未検証の値）',
+  user_id BIGINT UNSIGNED NULL COMMENT '解決済みの users.id（不明ならNULL）',
+  `procedure` VARCHAR(255) NOT NULL COMMENT '呼び出されたRPC',
+  ip_address VARCHAR(64) NOT NULL DEFAULT '' COMMENT 'アクセス元IP',
+  user_agent VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'ユーザーエージェント',
+  occurred_at DATETIME(6) NOT NULL COMMENT '発生時刻',
+  PRIMARY KEY (id),
+  KEY idx_auth_events_user (user_id, id),
+  KEY idx_auth_events_subject (subject, id),
+  KEY idx_auth_events_occurred (occurred_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='認証の成功・失敗イベント（監査・調査用）';
+
 -- Sample table
 CREATE TABLE samples (
   id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
@@ -0,0 +1,521 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: apikey/v1/apikey.proto
+
+package apikeyv1
+
+import (
+	_ "github.com/xiao1203/go-onion-grpc-template/gen/auth"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ApiKey is the metadata of a key. The secret itself is only returned once by IssueApiKey.
+type ApiKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// prefix identifies the key in lists and logs (e.g. "ak_3f9c2a1b").
+	Prefix      string   `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	OwnerUserId uint64   `protobuf:"varint,4,opt,name=owner_user_id,json=ownerUserId,proto3" json:"owner_user_id,omitempty"`
+	Scopes      []string `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// Unix seconds; 0 means unset.
+	ExpiresAt     int64 `protobuf:"varint,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	LastUsedAt    int64 `protobuf:"varint,7,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	RevokedAt     int64 `protobuf:"varint,8,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	CreatedAt     int64 `protobuf:"varint,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApiKey) Reset() {
+	*x = ApiKey{}
+	mi := &file_apikey_v1_apikey_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApiKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApiKey) ProtoMessage() {}
+
+func (x *ApiKey) ProtoReflect() protoreflect.Message {
+	mi := &file_apikey_v1_apikey_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApiKey.ProtoReflect.Descriptor instead.
+func (*ApiKey) Descriptor() ([]byte, []int) {
+	return file_apikey_v1_apikey_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ApiKey) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ApiKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ApiKey) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ApiKey) GetOwnerUserId() uint64 {
+	if x != nil {
+		return x.OwnerUserId
+	}
+	return 0
+}
+
+func (x *ApiKey) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *ApiKey) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *ApiKey) GetLastUsedAt() int64 {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return 0
+}
+
+func (x *ApiKey) GetRevokedAt() int64 {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return 0
+}
+
+func (x *ApiKey) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type IssueApiKeyRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Name   string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes []string               `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// Owner of the key (defaults to the caller).
+	OwnerUserId uint64 `protobuf:"varint,3,opt,name=owner_user_id,json=ownerUserId,proto3" json:"owner_user_id,omitempty"`
+	// Unix seconds; 0 means the key does not expire.
+	ExpiresAt     int64 `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueApiKeyRequest) Reset() {
+	*x = IssueApiKeyRequest{}
+	mi := &file_apikey_v1_apikey_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueApiKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueApiKeyRequest) ProtoMessage() {}
+
+func (x *IssueApiKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_apikey_v1_apikey_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueApiKeyRequest.ProtoReflect.Descriptor instead.
+func (*IssueApiKeyRequest) Descriptor() ([]byte, []int) {
+	return file_apikey_v1_apikey_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IssueApiKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IssueApiKeyRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *IssueApiKeyRequest) GetOwnerUserId() uint64 {
+	if x != nil {
+		return x.OwnerUserId
+	}
+	return 0
+}
+
+func (x *IssueApiKeyRequest) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type IssueApiKeyResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey *ApiKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// The plaintext key. It is not stored and cannot be retrieved again.
+	Secret        string `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueApiKeyResponse) Reset() {
+	*x = IssueApiKeyResponse{}
+	mi := &file_apikey_v1_apikey_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueApiKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueApiKeyResponse) ProtoMessage() {}
+
+func (x *IssueApiKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_apikey_v1_apikey_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueApiKeyResponse.ProtoReflect.Descriptor instead.
+func (*IssueApiKeyResponse) Descriptor() ([]byte, []int) {
+	return file_apikey_v1_apikey_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *IssueApiKeyResponse) GetApiKey() *ApiKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *IssueApiKeyResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListApiKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListApiKeysRequest) Reset() {
+	*x = ListApiKeysRequest{}
+	mi := &file_apikey_v1_apikey_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListApiKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListApiKeysRequest) ProtoMessage() {}
+
+func (x *ListApiKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_apikey_v1_apikey_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListApiKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListApiKeysRequest) Descriptor() ([]byte, []int) {
+	return file_apikey_v1_apikey_proto_rawDescGZIP(), []int{3}
+}
+
+type ListApiKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeys       []*ApiKey              `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListApiKeysResponse) Reset() {
+	*x = ListApiKeysResponse{}
+	mi := &file_apikey_v1_apikey_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListApiKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListApiKeysResponse) ProtoMessage() {}
+
+func (x *ListApiKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_apikey_v1_apikey_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListApiKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListApiKeysResponse) Descriptor() ([]byte, []int) {
+	return file_apikey_v1_apikey_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListApiKeysResponse) GetApiKeys() []*ApiKey {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+type RevokeApiKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeApiKeyRequest) Reset() {
+	*x = RevokeApiKeyRequest{}
+	mi := &file_apikey_v1_apikey_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeApiKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeApiKeyRequest) ProtoMessage() {}
+
+func (x *RevokeApiKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_apikey_v1_apikey_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeApiKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeApiKeyRequest) Descriptor() ([]byte, []int) {
+	return file_apikey_v1_apikey_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RevokeApiKeyRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type RevokeApiKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeApiKeyResponse) Reset() {
+	*x = RevokeApiKeyResponse{}
+	mi := &file_apikey_v1_apikey_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeApiKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeApiKeyResponse) ProtoMessage() {}
+
+func (x *RevokeApiKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_apikey_v1_apikey_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeApiKeyResponse.ProtoReflect.Descriptor instead.
+func (*RevokeApiKeyResponse) Descriptor() ([]byte, []int) {
+	return file_apikey_v1_apikey_proto_rawDescGZIP(), []int{6}
+}
+
+var File_apikey_v1_apikey_proto protoreflect.FileDescriptor
+
+const file_apikey_v1_apikey_proto_rawDesc = "" +
+	"\n" +
+	"\x16apikey/v1/apikey.proto\x12\tapikey.v1\x1a\x12auth/options.proto\"\xff\x01\n" +
+	"\x06ApiKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06prefix\x18\x03 \x01(\tR\x06prefix\x12\"\n" +
+	"\rowner_user_id\x18\x04 \x01(\x04R\vownerUserId\x12\x16\n" +
+	"\x06scopes\x18\x05 \x03(\tR\x06scopes\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\x03R\texpiresAt\x12 \n" +
+	"\flast_used_at\x18\a \x01(\x03R\n" +
+	"lastUsedAt\x12\x1d\n" +
+	"\n" +
+	"revoked_at\x18\b \x01(\x03R\trevokedAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\x03R\tcreatedAt\"\x83\x01\n" +
+	"\x12IssueApiKeyRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x02 \x03(\tR\x06scopes\x12\"\n" +
+	"\rowner_user_id\x18\x03 \x01(\x04R\vownerUserId\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\texpiresAt\"Y\n" +
+	"\x13IssueApiKeyResponse\x12*\n" +
+	"\aapi_key\x18\x01 \x01(\v2\x11.apikey.v1.ApiKeyR\x06apiKey\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"\x14\n" +
+	"\x12ListApiKeysRequest\"C\n" +
+	"\x13ListApiKeysResponse\x12,\n" +
+	"\bapi_keys\x18\x01 \x03(\v2\x11.apikey.v1.ApiKeyR\aapiKeys\"%\n" +
+	"\x13RevokeApiKeyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x16\n" +
+	"\x14RevokeApiKeyResponse2\xa6\x02\n" +
+	"\rApiKeyService\x12Y\n" +
+	"\vIssueApiKey\x12\x1d.apikey.v1.IssueApiKeyRequest\x1a\x1e.apikey.v1.IssueApiKeyResponse\"\v\xc2\xf3\x18\a\n" +
+	"\x05admin\x12\\\n" +
+	"\vListApiKeys\x12\x1d.apikey.v1.ListApiKeysRequest\x1a\x1e.apikey.v1.ListApiKeysResponse\"\x0e\xc2\xf3\x18\a\n" +
+	"\x05admin\x90\x02\x01\x12\\\n" +
+	"\fRevokeApiKey\x12\x1e.apikey.v1.RevokeApiKeyRequest\x1a\x1f.apikey.v1.RevokeApiKeyResponse\"\v\xc2\xf3\x18\a\n" +
+	"\x05adminBCZAgithub.com/xiao1203/go-onion-grpc-template/gen/apikey/v1;apikeyv1b\x06proto3"
+
+var (
+	file_apikey_v1_apikey_proto_rawDescOnce sync.Once
+	file_apikey_v1_apikey_proto_rawDescData []byte
+)
+
+func file_apikey_v1_apikey_proto_rawDescGZIP() []byte {
+	file_apikey_v1_apikey_proto_rawDescOnce.Do(func() {
+		file_apikey_v1_apikey_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_apikey_v1_apikey_proto_rawDesc), len(file_apikey_v1_apikey_proto_rawDesc)))
+	})
+	return file_apikey_v1_apikey_proto_rawDescData
+}
+
+var file_apikey_v1_apikey_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_apikey_v1_apikey_proto_goTypes = []any{
+	(*ApiKey)(nil),               // 0: apikey.v1.ApiKey
+	(*IssueApiKeyRequest)(nil),   // 1: apikey.v1.IssueApiKeyRequest
+	(*IssueApiKeyResponse)(nil),  // 2: apikey.v1.IssueApiKeyResponse
+	(*ListApiKeysRequest)(nil),   // 3: apikey.v1.ListApiKeysRequest
+	(*ListApiKeysResponse)(nil),  // 4: apikey.v1.ListApiKeysResponse
+	(*RevokeApiKeyRequest)(nil),  // 5: apikey.v1.RevokeApiKeyRequest
+	(*RevokeApiKeyResponse)(nil), // 6: apikey.v1.RevokeApiKeyResponse
+}
+var file_apikey_v1_apikey_proto_depIdxs = []int32{
+	0, // 0: apikey.v1.IssueApiKeyResponse.api_key:type_name -> apikey.v1.ApiKey
+	0, // 1: apikey.v1.ListApiKeysResponse.api_keys:type_name -> apikey.v1.ApiKey
+	1, // 2: apikey.v1.ApiKeyService.IssueApiKey:input_type -> apikey.v1.IssueApiKeyRequest
+	3, // 3: apikey.v1.ApiKeyService.ListApiKeys:input_type -> apikey.v1.ListApiKeysRequest
+	5, // 4: apikey.v1.ApiKeyService.RevokeApiKey:input_type -> apikey.v1.RevokeApiKeyRequest
+	2, // 5: apikey.v1.ApiKeyService.IssueApiKey:output_type -> apikey.v1.IssueApiKeyResponse
+	4, // 6: apikey.v1.ApiKeyService.ListApiKeys:output_type -> apikey.v1.ListApiKeysResponse
+	6, // 7: apikey.v1.ApiKeyService.RevokeApiKey:output_type -> apikey.v1.RevokeApiKeyResponse
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_apikey_v1_apikey_proto_init() }
+func file_apikey_v1_apikey_proto_init() {
+	if File_apikey_v1_apikey_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_apikey_v1_apikey_proto_rawDesc), len(file_apikey_v1_apikey_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_apikey_v1_apikey_proto_goTypes,
+		DependencyIndexes: file_apikey_v1_apikey_proto_depIdxs,
+		MessageInfos:      file_apikey_v1_apikey_proto_msgTypes,
+	}.Build()
+	File_apikey_v1_apikey_proto = out.File
+	file_apikey_v1_apikey_proto_goTypes = nil
+	file_apikey_v1_apikey_proto_depIdxs = nil
+}
@@ -0,0 +1,226 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: apperr/v1/apperr.proto
+
+package apperrv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Reason is sent as google.rpc.ErrorInfo.reason (the enum value name, e.g.
+// "ACCOUNT_LOCKED") with domain "go-onion-grpc-template". Clients should
+// switch on it rather than on error messages. The generic reasons are used
+// when no specific one applies; new values may be added at any time.
+type Reason int32
+
+const (
+	Reason_REASON_UNSPECIFIED Reason = 0
+	// Generic reasons, one per status code.
+	Reason_UNAUTHENTICATED     Reason = 1
+	Reason_PERMISSION_DENIED   Reason = 2
+	Reason_INVALID_ARGUMENT    Reason = 3
+	Reason_NOT_FOUND           Reason = 4
+	Reason_CONFLICT            Reason = 5
+	Reason_INTERNAL            Reason = 6
+	Reason_ABORTED             Reason = 7
+	Reason_FAILED_PRECONDITION Reason = 8
+	Reason_UNAVAILABLE         Reason = 9
+	Reason_DEADLINE_EXCEEDED   Reason = 10
+	Reason_CANCELED            Reason = 11
+	Reason_RESOURCE_EXHAUSTED  Reason = 12
+	Reason_OUT_OF_RANGE        Reason = 13
+	Reason_UNIMPLEMENTED       Reason = 14
+	Reason_DATA_LOSS           Reason = 15
+	// Authentication.
+	Reason_INVALID_CREDENTIALS   Reason = 100
+	Reason_ACCOUNT_LOCKED        Reason = 101
+	Reason_TOKEN_REVOKED         Reason = 102
+	Reason_STEP_UP_REQUIRED      Reason = 103
+	Reason_RESET_TOKEN_INVALID   Reason = 104
+	Reason_IDENTITY_EMAIL_IN_USE Reason = 105
+	// Accounts.
+	Reason_EMAIL_ALREADY_REGISTERED Reason = 200
+	Reason_PASSWORD_TOO_WEAK        Reason = 201
+	// Authorization.
+	Reason_IMPERSONATION_DENIED Reason = 300
+)
+
+// Enum value maps for Reason.
+var (
+	Reason_name = map[int32]string{
+		0:   "REASON_UNSPECIFIED",
+		1:   "UNAUTHENTICATED",
+		2:   "PERMISSION_DENIED",
+		3:   "INVALID_ARGUMENT",
+		4:   "NOT_FOUND",
+		5:   "CONFLICT",
+		6:   "INTERNAL",
+		7:   "ABORTED",
+		8:   "FAILED_PRECONDITION",
+		9:   "UNAVAILABLE",
+		10:  "DEADLINE_EXCEEDED",
+		11:  "CANCELED",
+		12:  "RESOURCE_EXHAUSTED",
+		13:  "OUT_OF_RANGE",
+		14:  "UNIMPLEMENTED",
+		15:  "DATA_LOSS",
+		100: "INVALID_CREDENTIALS",
+		101: "ACCOUNT_LOCKED",
+		102: "TOKEN_REVOKED",
+		103: "STEP_UP_REQUIRED",
+		104: "RESET_TOKEN_INVALID",
+		105: "IDENTITY_EMAIL_IN_USE",
+		200: "EMAIL_ALREADY_REGISTERED",
+		201: "PASSWORD_TOO_WEAK",
+		300: "IMPERSONATION_DENIED",
+	}
+	Reason_value = map[string]int32{
+		"REASON_UNSPECIFIED":       0,
+		"UNAUTHENTICATED":          1,
+		"PERMISSION_DENIED":        2,
+		"INVALID_ARGUMENT":         3,
+		"NOT_FOUND":                4,
+		"CONFLICT":                 5,
+		"INTERNAL":                 6,
+		"ABORTED":                  7,
+		"FAILED_PRECONDITION":      8,
+		"UNAVAILABLE":              9,
+		"DEADLINE_EXCEEDED":        10,
+		"CANCELED":                 11,
+		"RESOURCE_EXHAUSTED":       12,
+		"OUT_OF_RANGE":             13,
+		"UNIMPLEMENTED":            14,
+		"DATA_LOSS":                15,
+		"INVALID_CREDENTIALS":      100,
+		"ACCOUNT_LOCKED":           101,
+		"TOKEN_REVOKED":            102,
+		"STEP_UP_REQUIRED":         103,
+		"RESET_TOKEN_INVALID":      104,
+		"IDENTITY_EMAIL_IN_USE":    105,
+		"EMAIL_ALREADY_REGISTERED": 200,
+		"PASSWORD_TOO_WEAK":        201,
+		"IMPERSONATION_DENIED":     300,
+	}
+)
+
+func (x Reason) Enum() *Reason {
+	p := new(Reason)
+	*p = x
+	return p
+}
+
+func (x Reason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Reason) Descriptor() protoreflect.EnumDescriptor {
+	return file_apperr_v1_apperr_proto_enumTypes[0].Descriptor()
+}
+
+func (Reason) Type() protoreflect.EnumType {
+	return &file_apperr_v1_apperr_proto_enumTypes[0]
+}
+
+func (x Reason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Reason.Descriptor instead.
+func (Reason) EnumDescriptor() ([]byte, []int) {
+	return file_apperr_v1_apperr_proto_rawDescGZIP(), []int{0}
+}
+
+var File_apperr_v1_apperr_proto protoreflect.FileDescriptor
+
+const file_apperr_v1_apperr_proto_rawDesc = "" +
+	"\n" +
+	"\x16apperr/v1/apperr.proto\x12\tapperr.v1*\x91\x04\n" +
+	"\x06Reason\x12\x16\n" +
+	"\x12REASON_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fUNAUTHENTICATED\x10\x01\x12\x15\n" +
+	"\x11PERMISSION_DENIED\x10\x02\x12\x14\n" +
+	"\x10INVALID_ARGUMENT\x10\x03\x12\r\n" +
+	"\tNOT_FOUND\x10\x04\x12\f\n" +
+	"\bCONFLICT\x10\x05\x12\f\n" +
+	"\bINTERNAL\x10\x06\x12\v\n" +
+	"\aABORTED\x10\a\x12\x17\n" +
+	"\x13FAILED_PRECONDITION\x10\b\x12\x0f\n" +
+	"\vUNAVAILABLE\x10\t\x12\x15\n" +
+	"\x11DEADLINE_EXCEEDED\x10\n" +
+	"\x12\f\n" +
+	"\bCANCELED\x10\v\x12\x16\n" +
+	"\x12RESOURCE_EXHAUSTED\x10\f\x12\x10\n" +
+	"\fOUT_OF_RANGE\x10\r\x12\x11\n" +
+	"\rUNIMPLEMENTED\x10\x0e\x12\r\n" +
+	"\tDATA_LOSS\x10\x0f\x12\x17\n" +
+	"\x13INVALID_CREDENTIALS\x10d\x12\x12\n" +
+	"\x0eACCOUNT_LOCKED\x10e\x12\x11\n" +
+	"\rTOKEN_REVOKED\x10f\x12\x14\n" +
+	"\x10STEP_UP_REQUIRED\x10g\x12\x17\n" +
+	"\x13RESET_TOKEN_INVALID\x10h\x12\x19\n" +
+	"\x15IDENTITY_EMAIL_IN_USE\x10i\x12\x1d\n" +
+	"\x18EMAIL_ALREADY_REGISTERED\x10\xc8\x01\x12\x16\n" +
+	"\x11PASSWORD_TOO_WEAK\x10\xc9\x01\x12\x19\n" +
+	"\x14IMPERSONATION_DENIED\x10\xac\x02BCZAgithub.com/xiao1203/go-onion-grpc-template/gen/apperr/v1;apperrv1b\x06proto3"
+
+var (
+	file_apperr_v1_apperr_proto_rawDescOnce sync.Once
+	file_apperr_v1_apperr_proto_rawDescData []byte
+)
+
+func file_apperr_v1_apperr_proto_rawDescGZIP() []byte {
+	file_apperr_v1_apperr_proto_rawDescOnce.Do(func() {
+		file_apperr_v1_apperr_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_apperr_v1_apperr_proto_rawDesc), len(file_apperr_v1_apperr_proto_rawDesc)))
+	})
+	return file_apperr_v1_apperr_proto_rawDescData
+}
+
+var file_apperr_v1_apperr_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_apperr_v1_apperr_proto_goTypes = []any{
+	(Reason)(0), // 0: apperr.v1.Reason
+}
+var file_apperr_v1_apperr_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_apperr_v1_apperr_proto_init() }
+func file_apperr_v1_apperr_proto_init() {
+	if File_apperr_v1_apperr_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_apperr_v1_apperr_proto_rawDesc), len(file_apperr_v1_apperr_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_apperr_v1_apperr_proto_goTypes,
+		DependencyIndexes: file_apperr_v1_apperr_proto_depIdxs,
+		EnumInfos:         file_apperr_v1_apperr_proto_enumTypes,
+	}.Build()
+	File_apperr_v1_apperr_proto = out.File
+	file_apperr_v1_apperr_proto_goTypes = nil
+	file_apperr_v1_apperr_proto_depIdxs = nil
+}
@@ -0,0 +1,369 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: auth/options.proto
+
+package authpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AuthzRule declares who may call an RPC.
+//
+//	rpc DeleteArticle(DeleteArticleRequest) returns (DeleteArticleResponse) {
+//	  option (auth.authz) = { roles: ["admin"], permissions: ["article.delete"] };
+//	}
+type AuthzRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The caller must have at least one of these roles (empty: no role requirement).
+	Roles []string `protobuf:"bytes,1,rep,name=roles,proto3" json:"roles,omitempty"`
+	// The caller must hold every one of these permissions (granted via roles).
+	Permissions   []string `protobuf:"bytes,2,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthzRule) Reset() {
+	*x = AuthzRule{}
+	mi := &file_auth_options_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthzRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthzRule) ProtoMessage() {}
+
+func (x *AuthzRule) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_options_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthzRule.ProtoReflect.Descriptor instead.
+func (*AuthzRule) Descriptor() ([]byte, []int) {
+	return file_auth_options_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AuthzRule) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *AuthzRule) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+// StepUpRule requires a recent, strong login for an RPC, checked against the
+// acr / amr / auth_time claims of the caller's token.
+//
+//	rpc DeleteAccount(DeleteAccountRequest) returns (DeleteAccountResponse) {
+//	  option (auth.step_up) = { amr: ["mfa"], max_age_seconds: 300 };
+//	}
+type StepUpRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The acr claim must be one of these (empty: any).
+	Acr []string `protobuf:"bytes,1,rep,name=acr,proto3" json:"acr,omitempty"`
+	// The amr claim must contain every one of these (e.g. "mfa", "hwk").
+	Amr []string `protobuf:"bytes,2,rep,name=amr,proto3" json:"amr,omitempty"`
+	// auth_time must be at most this many seconds old (0: no limit).
+	MaxAgeSeconds int64 `protobuf:"varint,3,opt,name=max_age_seconds,json=maxAgeSeconds,proto3" json:"max_age_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StepUpRule) Reset() {
+	*x = StepUpRule{}
+	mi := &file_auth_options_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StepUpRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepUpRule) ProtoMessage() {}
+
+func (x *StepUpRule) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_options_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepUpRule.ProtoReflect.Descriptor instead.
+func (*StepUpRule) Descriptor() ([]byte, []int) {
+	return file_auth_options_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StepUpRule) GetAcr() []string {
+	if x != nil {
+		return x.Acr
+	}
+	return nil
+}
+
+func (x *StepUpRule) GetAmr() []string {
+	if x != nil {
+		return x.Amr
+	}
+	return nil
+}
+
+func (x *StepUpRule) GetMaxAgeSeconds() int64 {
+	if x != nil {
+		return x.MaxAgeSeconds
+	}
+	return 0
+}
+
+// StepUpRequired is attached as an error detail to the Unauthenticated error
+// returned when a StepUpRule is not met. The client should re-authenticate
+// with these requirements (e.g. OIDC acr_values / max_age) and retry.
+type StepUpRequired struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The unmet requirement: "acr", "amr" or "max_age".
+	Reason        string   `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	AcrValues     []string `protobuf:"bytes,2,rep,name=acr_values,json=acrValues,proto3" json:"acr_values,omitempty"`
+	Amr           []string `protobuf:"bytes,3,rep,name=amr,proto3" json:"amr,omitempty"`
+	MaxAgeSeconds int64    `protobuf:"varint,4,opt,name=max_age_seconds,json=maxAgeSeconds,proto3" json:"max_age_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StepUpRequired) Reset() {
+	*x = StepUpRequired{}
+	mi := &file_auth_options_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StepUpRequired) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepUpRequired) ProtoMessage() {}
+
+func (x *StepUpRequired) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_options_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepUpRequired.ProtoReflect.Descriptor instead.
+func (*StepUpRequired) Descriptor() ([]byte, []int) {
+	return file_auth_options_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StepUpRequired) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *StepUpRequired) GetAcrValues() []string {
+	if x != nil {
+		return x.AcrValues
+	}
+	return nil
+}
+
+func (x *StepUpRequired) GetAmr() []string {
+	if x != nil {
+		return x.Amr
+	}
+	return nil
+}
+
+func (x *StepUpRequired) GetMaxAgeSeconds() int64 {
+	if x != nil {
+		return x.MaxAgeSeconds
+	}
+	return 0
+}
+
+var file_auth_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*AuthzRule)(nil),
+		Field:         51000,
+		Name:          "auth.authz",
+		Tag:           "bytes,51000,opt,name=authz",
+		Filename:      "auth/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*StepUpRule)(nil),
+		Field:         51003,
+		Name:          "auth.step_up",
+		Tag:           "bytes,51003,opt,name=step_up",
+		Filename:      "auth/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         51004,
+		Name:          "auth.sensitive",
+		Tag:           "varint,51004,opt,name=sensitive",
+		Filename:      "auth/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         51001,
+		Name:          "auth.public",
+		Tag:           "varint,51001,opt,name=public",
+		Filename:      "auth/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         51002,
+		Name:          "auth.public_service",
+		Tag:           "varint,51002,opt,name=public_service",
+		Filename:      "auth/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// optional auth.AuthzRule authz = 51000;
+	E_Authz = &file_auth_options_proto_extTypes[0]
+	// optional auth.StepUpRule step_up = 51003;
+	E_StepUp = &file_auth_options_proto_extTypes[1]
+	// optional bool sensitive = 51004;
+	E_Sensitive = &file_auth_options_proto_extTypes[2]
+	// optional bool public = 51001;
+	E_Public = &file_auth_options_proto_extTypes[3]
+)
+
+// Extension fields to descriptorpb.ServiceOptions.
+var (
+	// optional bool public_service = 51002;
+	E_PublicService = &file_auth_options_proto_extTypes[4]
+)
+
+var File_auth_options_proto protoreflect.FileDescriptor
+
+const file_auth_options_proto_rawDesc = "" +
+	"\n" +
+	"\x12auth/options.proto\x12\x04auth\x1a google/protobuf/descriptor.proto\"C\n" +
+	"\tAuthzRule\x12\x14\n" +
+	"\x05roles\x18\x01 \x03(\tR\x05roles\x12 \n" +
+	"\vpermissions\x18\x02 \x03(\tR\vpermissions\"X\n" +
+	"\n" +
+	"StepUpRule\x12\x10\n" +
+	"\x03acr\x18\x01 \x03(\tR\x03acr\x12\x10\n" +
+	"\x03amr\x18\x02 \x03(\tR\x03amr\x12&\n" +
+	"\x0fmax_age_seconds\x18\x03 \x01(\x03R\rmaxAgeSeconds\"\x81\x01\n" +
+	"\x0eStepUpRequired\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"acr_values\x18\x02 \x03(\tR\tacrValues\x12\x10\n" +
+	"\x03amr\x18\x03 \x03(\tR\x03amr\x12&\n" +
+	"\x0fmax_age_seconds\x18\x04 \x01(\x03R\rmaxAgeSeconds:G\n" +
+	"\x05authz\x12\x1e.google.protobuf.MethodOptions\x18\xb8\x8e\x03 \x01(\v2\x0f.auth.AuthzRuleR\x05authz:K\n" +
+	"\astep_up\x12\x1e.google.protobuf.MethodOptions\x18\xbb\x8e\x03 \x01(\v2\x10.auth.StepUpRuleR\x06stepUp:>\n" +
+	"\tsensitive\x12\x1e.google.protobuf.MethodOptions\x18\xbc\x8e\x03 \x01(\bR\tsensitive:8\n" +
+	"\x06public\x12\x1e.google.protobuf.MethodOptions\x18\xb9\x8e\x03 \x01(\bR\x06public:H\n" +
+	"\x0epublic_service\x12\x1f.google.protobuf.ServiceOptions\x18\xba\x8e\x03 \x01(\bR\rpublicServiceB<Z:github.com/xiao1203/go-onion-grpc-template/gen/auth;authpbb\x06proto3"
+
+var (
+	file_auth_options_proto_rawDescOnce sync.Once
+	file_auth_options_proto_rawDescData []byte
+)
+
+func file_auth_options_proto_rawDescGZIP() []byte {
+	file_auth_options_proto_rawDescOnce.Do(func() {
+		file_auth_options_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_auth_options_proto_rawDesc), len(file_auth_options_proto_rawDesc)))
+	})
+	return file_auth_options_proto_rawDescData
+}
+
+var file_auth_options_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_auth_options_proto_goTypes = []any{
+	(*AuthzRule)(nil),                   // 0: auth.AuthzRule
+	(*StepUpRule)(nil),                  // 1: auth.StepUpRule
+	(*StepUpRequired)(nil),              // 2: auth.StepUpRequired
+	(*descriptorpb.MethodOptions)(nil),  // 3: google.protobuf.MethodOptions
+	(*descriptorpb.ServiceOptions)(nil), // 4: google.protobuf.ServiceOptions
+}
+var file_auth_options_proto_depIdxs = []int32{
+	3, // 0: auth.authz:extendee -> google.protobuf.MethodOptions
+	3, // 1: auth.step_up:extendee -> google.protobuf.MethodOptions
+	3, // 2: auth.sensitive:extendee -> google.protobuf.MethodOptions
+	3, // 3: auth.public:extendee -> google.protobuf.MethodOptions
+	4, // 4: auth.public_service:extendee -> google.protobuf.ServiceOptions
+	0, // 5: auth.authz:type_name -> auth.AuthzRule
+	1, // 6: auth.step_up:type_name -> auth.StepUpRule
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	5, // [5:7] is the sub-list for extension type_name
+	0, // [0:5] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_auth_options_proto_init() }
+func file_auth_options_proto_init() {
+	if File_auth_options_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_auth_options_proto_rawDesc), len(file_auth_options_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 5,
+			NumServices:   0,
+		},
+		GoTypes:           file_auth_options_proto_goTypes,
+		DependencyIndexes: file_auth_options_proto_depIdxs,
+		MessageInfos:      file_auth_options_proto_msgTypes,
+		ExtensionInfos:    file_auth_options_proto_extTypes,
+	}.Build()
+	File_auth_options_proto = out.File
+	file_auth_options_proto_goTypes = nil
+	file_auth_options_proto_depIdxs = nil
+}
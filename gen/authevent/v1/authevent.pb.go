@@ -0,0 +1,433 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: authevent/v1/authevent.proto
+
+package autheventv1
+
+import (
+	_ "github.com/xiao1203/go-onion-grpc-template/gen/auth"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AuthEvent is one authentication attempt seen by the auth interceptor.
+type AuthEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// "success" or "failure".
+	Outcome string `protobuf:"bytes,2,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	// Why a failure happened (e.g. "expired", "bad_audience", "unknown_kid"); empty on success.
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Credential type: "bearer", "apikey", "session", "mtls", "dev"; empty when none was sent.
+	Method   string `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
+	Provider string `protobuf:"bytes,5,opt,name=provider,proto3" json:"provider,omitempty"`
+	// iss / sub of the token. For failures they come from the unverified token.
+	Issuer  string `protobuf:"bytes,6,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	Subject string `protobuf:"bytes,7,opt,name=subject,proto3" json:"subject,omitempty"`
+	// Resolved users.id; 0 when unknown.
+	UserId    uint64 `protobuf:"varint,8,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Procedure string `protobuf:"bytes,9,opt,name=procedure,proto3" json:"procedure,omitempty"`
+	IpAddress string `protobuf:"bytes,10,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	UserAgent string `protobuf:"bytes,11,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	// Unix seconds.
+	OccurredAt    int64 `protobuf:"varint,12,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthEvent) Reset() {
+	*x = AuthEvent{}
+	mi := &file_authevent_v1_authevent_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthEvent) ProtoMessage() {}
+
+func (x *AuthEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_authevent_v1_authevent_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthEvent.ProtoReflect.Descriptor instead.
+func (*AuthEvent) Descriptor() ([]byte, []int) {
+	return file_authevent_v1_authevent_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AuthEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuthEvent) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *AuthEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AuthEvent) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *AuthEvent) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *AuthEvent) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *AuthEvent) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *AuthEvent) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AuthEvent) GetProcedure() string {
+	if x != nil {
+		return x.Procedure
+	}
+	return ""
+}
+
+func (x *AuthEvent) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *AuthEvent) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *AuthEvent) GetOccurredAt() int64 {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return 0
+}
+
+// Filters are combined with AND; unset fields are ignored.
+type ListAuthEventsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserId  uint64                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Subject string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	Issuer  string                 `protobuf:"bytes,3,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	// "success" or "failure".
+	Outcome   string `protobuf:"bytes,4,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	Reason    string `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	IpAddress string `protobuf:"bytes,6,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	// Unix seconds: since <= occurred_at < until.
+	Since int64 `protobuf:"varint,7,opt,name=since,proto3" json:"since,omitempty"`
+	Until int64 `protobuf:"varint,8,opt,name=until,proto3" json:"until,omitempty"`
+	// Defaults to 50, at most 500.
+	PageSize int32 `protobuf:"varint,9,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous response.
+	PageToken     string `protobuf:"bytes,10,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuthEventsRequest) Reset() {
+	*x = ListAuthEventsRequest{}
+	mi := &file_authevent_v1_authevent_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuthEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuthEventsRequest) ProtoMessage() {}
+
+func (x *ListAuthEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authevent_v1_authevent_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuthEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuthEventsRequest) Descriptor() ([]byte, []int) {
+	return file_authevent_v1_authevent_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListAuthEventsRequest) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListAuthEventsRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ListAuthEventsRequest) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *ListAuthEventsRequest) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *ListAuthEventsRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ListAuthEventsRequest) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *ListAuthEventsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *ListAuthEventsRequest) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+func (x *ListAuthEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAuthEventsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListAuthEventsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Newest first.
+	Events []*AuthEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// Empty when there are no more events.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuthEventsResponse) Reset() {
+	*x = ListAuthEventsResponse{}
+	mi := &file_authevent_v1_authevent_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuthEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuthEventsResponse) ProtoMessage() {}
+
+func (x *ListAuthEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_authevent_v1_authevent_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuthEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuthEventsResponse) Descriptor() ([]byte, []int) {
+	return file_authevent_v1_authevent_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListAuthEventsResponse) GetEvents() []*AuthEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListAuthEventsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+var File_authevent_v1_authevent_proto protoreflect.FileDescriptor
+
+const file_authevent_v1_authevent_proto_rawDesc = "" +
+	"\n" +
+	"\x1cauthevent/v1/authevent.proto\x12\fauthevent.v1\x1a\x12auth/options.proto\"\xc9\x02\n" +
+	"\tAuthEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aoutcome\x18\x02 \x01(\tR\aoutcome\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06method\x18\x04 \x01(\tR\x06method\x12\x1a\n" +
+	"\bprovider\x18\x05 \x01(\tR\bprovider\x12\x16\n" +
+	"\x06issuer\x18\x06 \x01(\tR\x06issuer\x12\x18\n" +
+	"\asubject\x18\a \x01(\tR\asubject\x12\x17\n" +
+	"\auser_id\x18\b \x01(\x04R\x06userId\x12\x1c\n" +
+	"\tprocedure\x18\t \x01(\tR\tprocedure\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\n" +
+	" \x01(\tR\tipAddress\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\v \x01(\tR\tuserAgent\x12\x1f\n" +
+	"\voccurred_at\x18\f \x01(\x03R\n" +
+	"occurredAt\"\x9b\x02\n" +
+	"\x15ListAuthEventsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x04R\x06userId\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x16\n" +
+	"\x06issuer\x18\x03 \x01(\tR\x06issuer\x12\x18\n" +
+	"\aoutcome\x18\x04 \x01(\tR\aoutcome\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x06 \x01(\tR\tipAddress\x12\x14\n" +
+	"\x05since\x18\a \x01(\x03R\x05since\x12\x14\n" +
+	"\x05until\x18\b \x01(\x03R\x05until\x12\x1b\n" +
+	"\tpage_size\x18\t \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\n" +
+	" \x01(\tR\tpageToken\"q\n" +
+	"\x16ListAuthEventsResponse\x12/\n" +
+	"\x06events\x18\x01 \x03(\v2\x17.authevent.v1.AuthEventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken2\x7f\n" +
+	"\x10AuthEventService\x12k\n" +
+	"\x0eListAuthEvents\x12#.authevent.v1.ListAuthEventsRequest\x1a$.authevent.v1.ListAuthEventsResponse\"\x0e\xc2\xf3\x18\a\n" +
+	"\x05admin\x90\x02\x01BIZGgithub.com/xiao1203/go-onion-grpc-template/gen/authevent/v1;autheventv1b\x06proto3"
+
+var (
+	file_authevent_v1_authevent_proto_rawDescOnce sync.Once
+	file_authevent_v1_authevent_proto_rawDescData []byte
+)
+
+func file_authevent_v1_authevent_proto_rawDescGZIP() []byte {
+	file_authevent_v1_authevent_proto_rawDescOnce.Do(func() {
+		file_authevent_v1_authevent_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_authevent_v1_authevent_proto_rawDesc), len(file_authevent_v1_authevent_proto_rawDesc)))
+	})
+	return file_authevent_v1_authevent_proto_rawDescData
+}
+
+var file_authevent_v1_authevent_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_authevent_v1_authevent_proto_goTypes = []any{
+	(*AuthEvent)(nil),              // 0: authevent.v1.AuthEvent
+	(*ListAuthEventsRequest)(nil),  // 1: authevent.v1.ListAuthEventsRequest
+	(*ListAuthEventsResponse)(nil), // 2: authevent.v1.ListAuthEventsResponse
+}
+var file_authevent_v1_authevent_proto_depIdxs = []int32{
+	0, // 0: authevent.v1.ListAuthEventsResponse.events:type_name -> authevent.v1.AuthEvent
+	1, // 1: authevent.v1.AuthEventService.ListAuthEvents:input_type -> authevent.v1.ListAuthEventsRequest
+	2, // 2: authevent.v1.AuthEventService.ListAuthEvents:output_type -> authevent.v1.ListAuthEventsResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_authevent_v1_authevent_proto_init() }
+func file_authevent_v1_authevent_proto_init() {
+	if File_authevent_v1_authevent_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_authevent_v1_authevent_proto_rawDesc), len(file_authevent_v1_authevent_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_authevent_v1_authevent_proto_goTypes,
+		DependencyIndexes: file_authevent_v1_authevent_proto_depIdxs,
+		MessageInfos:      file_authevent_v1_authevent_proto_msgTypes,
+	}.Build()
+	File_authevent_v1_authevent_proto = out.File
+	file_authevent_v1_authevent_proto_goTypes = nil
+	file_authevent_v1_authevent_proto_depIdxs = nil
+}
@@ -0,0 +1,111 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: authevent/v1/authevent.proto
+
+package autheventv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/xiao1203/go-onion-grpc-template/gen/authevent/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// AuthEventServiceName is the fully-qualified name of the AuthEventService service.
+	AuthEventServiceName = "authevent.v1.AuthEventService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// AuthEventServiceListAuthEventsProcedure is the fully-qualified name of the AuthEventService's
+	// ListAuthEvents RPC.
+	AuthEventServiceListAuthEventsProcedure = "/authevent.v1.AuthEventService/ListAuthEvents"
+)
+
+// AuthEventServiceClient is a client for the authevent.v1.AuthEventService service.
+type AuthEventServiceClient interface {
+	ListAuthEvents(context.Context, *connect.Request[v1.ListAuthEventsRequest]) (*connect.Response[v1.ListAuthEventsResponse], error)
+}
+
+// NewAuthEventServiceClient constructs a client for the authevent.v1.AuthEventService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAuthEventServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) AuthEventServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	authEventServiceMethods := v1.File_authevent_v1_authevent_proto.Services().ByName("AuthEventService").Methods()
+	return &authEventServiceClient{
+		listAuthEvents: connect.NewClient[v1.ListAuthEventsRequest, v1.ListAuthEventsResponse](
+			httpClient,
+			baseURL+AuthEventServiceListAuthEventsProcedure,
+			connect.WithSchema(authEventServiceMethods.ByName("ListAuthEvents")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// authEventServiceClient implements AuthEventServiceClient.
+type authEventServiceClient struct {
+	listAuthEvents *connect.Client[v1.ListAuthEventsRequest, v1.ListAuthEventsResponse]
+}
+
+// ListAuthEvents calls authevent.v1.AuthEventService.ListAuthEvents.
+func (c *authEventServiceClient) ListAuthEvents(ctx context.Context, req *connect.Request[v1.ListAuthEventsRequest]) (*connect.Response[v1.ListAuthEventsResponse], error) {
+	return c.listAuthEvents.CallUnary(ctx, req)
+}
+
+// AuthEventServiceHandler is an implementation of the authevent.v1.AuthEventService service.
+type AuthEventServiceHandler interface {
+	ListAuthEvents(context.Context, *connect.Request[v1.ListAuthEventsRequest]) (*connect.Response[v1.ListAuthEventsResponse], error)
+}
+
+// NewAuthEventServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAuthEventServiceHandler(svc AuthEventServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	authEventServiceMethods := v1.File_authevent_v1_authevent_proto.Services().ByName("AuthEventService").Methods()
+	authEventServiceListAuthEventsHandler := connect.NewUnaryHandler(
+		AuthEventServiceListAuthEventsProcedure,
+		svc.ListAuthEvents,
+		connect.WithSchema(authEventServiceMethods.ByName("ListAuthEvents")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/authevent.v1.AuthEventService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AuthEventServiceListAuthEventsProcedure:
+			authEventServiceListAuthEventsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAuthEventServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAuthEventServiceHandler struct{}
+
+func (UnimplementedAuthEventServiceHandler) ListAuthEvents(context.Context, *connect.Request[v1.ListAuthEventsRequest]) (*connect.Response[v1.ListAuthEventsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("authevent.v1.AuthEventService.ListAuthEvents is not implemented"))
+}
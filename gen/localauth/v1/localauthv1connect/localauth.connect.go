@@ -0,0 +1,229 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: localauth/v1/localauth.proto
+
+package localauthv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/xiao1203/go-onion-grpc-template/gen/localauth/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// LocalAuthServiceName is the fully-qualified name of the LocalAuthService service.
+	LocalAuthServiceName = "localauth.v1.LocalAuthService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// LocalAuthServiceSignUpProcedure is the fully-qualified name of the LocalAuthService's SignUp RPC.
+	LocalAuthServiceSignUpProcedure = "/localauth.v1.LocalAuthService/SignUp"
+	// LocalAuthServiceLoginProcedure is the fully-qualified name of the LocalAuthService's Login RPC.
+	LocalAuthServiceLoginProcedure = "/localauth.v1.LocalAuthService/Login"
+	// LocalAuthServiceChangePasswordProcedure is the fully-qualified name of the LocalAuthService's
+	// ChangePassword RPC.
+	LocalAuthServiceChangePasswordProcedure = "/localauth.v1.LocalAuthService/ChangePassword"
+	// LocalAuthServiceRequestPasswordResetProcedure is the fully-qualified name of the
+	// LocalAuthService's RequestPasswordReset RPC.
+	LocalAuthServiceRequestPasswordResetProcedure = "/localauth.v1.LocalAuthService/RequestPasswordReset"
+	// LocalAuthServiceResetPasswordProcedure is the fully-qualified name of the LocalAuthService's
+	// ResetPassword RPC.
+	LocalAuthServiceResetPasswordProcedure = "/localauth.v1.LocalAuthService/ResetPassword"
+)
+
+// LocalAuthServiceClient is a client for the localauth.v1.LocalAuthService service.
+type LocalAuthServiceClient interface {
+	SignUp(context.Context, *connect.Request[v1.SignUpRequest]) (*connect.Response[v1.SignUpResponse], error)
+	Login(context.Context, *connect.Request[v1.LoginRequest]) (*connect.Response[v1.LoginResponse], error)
+	// Changes the caller's password.
+	ChangePassword(context.Context, *connect.Request[v1.ChangePasswordRequest]) (*connect.Response[v1.ChangePasswordResponse], error)
+	// Always succeeds, whether or not the email is registered.
+	RequestPasswordReset(context.Context, *connect.Request[v1.RequestPasswordResetRequest]) (*connect.Response[v1.RequestPasswordResetResponse], error)
+	// Sets a new password and revokes the user's existing tokens and sessions.
+	ResetPassword(context.Context, *connect.Request[v1.ResetPasswordRequest]) (*connect.Response[v1.ResetPasswordResponse], error)
+}
+
+// NewLocalAuthServiceClient constructs a client for the localauth.v1.LocalAuthService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewLocalAuthServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) LocalAuthServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	localAuthServiceMethods := v1.File_localauth_v1_localauth_proto.Services().ByName("LocalAuthService").Methods()
+	return &localAuthServiceClient{
+		signUp: connect.NewClient[v1.SignUpRequest, v1.SignUpResponse](
+			httpClient,
+			baseURL+LocalAuthServiceSignUpProcedure,
+			connect.WithSchema(localAuthServiceMethods.ByName("SignUp")),
+			connect.WithClientOptions(opts...),
+		),
+		login: connect.NewClient[v1.LoginRequest, v1.LoginResponse](
+			httpClient,
+			baseURL+LocalAuthServiceLoginProcedure,
+			connect.WithSchema(localAuthServiceMethods.ByName("Login")),
+			connect.WithClientOptions(opts...),
+		),
+		changePassword: connect.NewClient[v1.ChangePasswordRequest, v1.ChangePasswordResponse](
+			httpClient,
+			baseURL+LocalAuthServiceChangePasswordProcedure,
+			connect.WithSchema(localAuthServiceMethods.ByName("ChangePassword")),
+			connect.WithClientOptions(opts...),
+		),
+		requestPasswordReset: connect.NewClient[v1.RequestPasswordResetRequest, v1.RequestPasswordResetResponse](
+			httpClient,
+			baseURL+LocalAuthServiceRequestPasswordResetProcedure,
+			connect.WithSchema(localAuthServiceMethods.ByName("RequestPasswordReset")),
+			connect.WithClientOptions(opts...),
+		),
+		resetPassword: connect.NewClient[v1.ResetPasswordRequest, v1.ResetPasswordResponse](
+			httpClient,
+			baseURL+LocalAuthServiceResetPasswordProcedure,
+			connect.WithSchema(localAuthServiceMethods.ByName("ResetPassword")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// localAuthServiceClient implements LocalAuthServiceClient.
+type localAuthServiceClient struct {
+	signUp               *connect.Client[v1.SignUpRequest, v1.SignUpResponse]
+	login                *connect.Client[v1.LoginRequest, v1.LoginResponse]
+	changePassword       *connect.Client[v1.ChangePasswordRequest, v1.ChangePasswordResponse]
+	requestPasswordReset *connect.Client[v1.RequestPasswordResetRequest, v1.RequestPasswordResetResponse]
+	resetPassword        *connect.Client[v1.ResetPasswordRequest, v1.ResetPasswordResponse]
+}
+
+// SignUp calls localauth.v1.LocalAuthService.SignUp.
+func (c *localAuthServiceClient) SignUp(ctx context.Context, req *connect.Request[v1.SignUpRequest]) (*connect.Response[v1.SignUpResponse], error) {
+	return c.signUp.CallUnary(ctx, req)
+}
+
+// Login calls localauth.v1.LocalAuthService.Login.
+func (c *localAuthServiceClient) Login(ctx context.Context, req *connect.Request[v1.LoginRequest]) (*connect.Response[v1.LoginResponse], error) {
+	return c.login.CallUnary(ctx, req)
+}
+
+// ChangePassword calls localauth.v1.LocalAuthService.ChangePassword.
+func (c *localAuthServiceClient) ChangePassword(ctx context.Context, req *connect.Request[v1.ChangePasswordRequest]) (*connect.Response[v1.ChangePasswordResponse], error) {
+	return c.changePassword.CallUnary(ctx, req)
+}
+
+// RequestPasswordReset calls localauth.v1.LocalAuthService.RequestPasswordReset.
+func (c *localAuthServiceClient) RequestPasswordReset(ctx context.Context, req *connect.Request[v1.RequestPasswordResetRequest]) (*connect.Response[v1.RequestPasswordResetResponse], error) {
+	return c.requestPasswordReset.CallUnary(ctx, req)
+}
+
+// ResetPassword calls localauth.v1.LocalAuthService.ResetPassword.
+func (c *localAuthServiceClient) ResetPassword(ctx context.Context, req *connect.Request[v1.ResetPasswordRequest]) (*connect.Response[v1.ResetPasswordResponse], error) {
+	return c.resetPassword.CallUnary(ctx, req)
+}
+
+// LocalAuthServiceHandler is an implementation of the localauth.v1.LocalAuthService service.
+type LocalAuthServiceHandler interface {
+	SignUp(context.Context, *connect.Request[v1.SignUpRequest]) (*connect.Response[v1.SignUpResponse], error)
+	Login(context.Context, *connect.Request[v1.LoginRequest]) (*connect.Response[v1.LoginResponse], error)
+	// Changes the caller's password.
+	ChangePassword(context.Context, *connect.Request[v1.ChangePasswordRequest]) (*connect.Response[v1.ChangePasswordResponse], error)
+	// Always succeeds, whether or not the email is registered.
+	RequestPasswordReset(context.Context, *connect.Request[v1.RequestPasswordResetRequest]) (*connect.Response[v1.RequestPasswordResetResponse], error)
+	// Sets a new password and revokes the user's existing tokens and sessions.
+	ResetPassword(context.Context, *connect.Request[v1.ResetPasswordRequest]) (*connect.Response[v1.ResetPasswordResponse], error)
+}
+
+// NewLocalAuthServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewLocalAuthServiceHandler(svc LocalAuthServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	localAuthServiceMethods := v1.File_localauth_v1_localauth_proto.Services().ByName("LocalAuthService").Methods()
+	localAuthServiceSignUpHandler := connect.NewUnaryHandler(
+		LocalAuthServiceSignUpProcedure,
+		svc.SignUp,
+		connect.WithSchema(localAuthServiceMethods.ByName("SignUp")),
+		connect.WithHandlerOptions(opts...),
+	)
+	localAuthServiceLoginHandler := connect.NewUnaryHandler(
+		LocalAuthServiceLoginProcedure,
+		svc.Login,
+		connect.WithSchema(localAuthServiceMethods.ByName("Login")),
+		connect.WithHandlerOptions(opts...),
+	)
+	localAuthServiceChangePasswordHandler := connect.NewUnaryHandler(
+		LocalAuthServiceChangePasswordProcedure,
+		svc.ChangePassword,
+		connect.WithSchema(localAuthServiceMethods.ByName("ChangePassword")),
+		connect.WithHandlerOptions(opts...),
+	)
+	localAuthServiceRequestPasswordResetHandler := connect.NewUnaryHandler(
+		LocalAuthServiceRequestPasswordResetProcedure,
+		svc.RequestPasswordReset,
+		connect.WithSchema(localAuthServiceMethods.ByName("RequestPasswordReset")),
+		connect.WithHandlerOptions(opts...),
+	)
+	localAuthServiceResetPasswordHandler := connect.NewUnaryHandler(
+		LocalAuthServiceResetPasswordProcedure,
+		svc.ResetPassword,
+		connect.WithSchema(localAuthServiceMethods.ByName("ResetPassword")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/localauth.v1.LocalAuthService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case LocalAuthServiceSignUpProcedure:
+			localAuthServiceSignUpHandler.ServeHTTP(w, r)
+		case LocalAuthServiceLoginProcedure:
+			localAuthServiceLoginHandler.ServeHTTP(w, r)
+		case LocalAuthServiceChangePasswordProcedure:
+			localAuthServiceChangePasswordHandler.ServeHTTP(w, r)
+		case LocalAuthServiceRequestPasswordResetProcedure:
+			localAuthServiceRequestPasswordResetHandler.ServeHTTP(w, r)
+		case LocalAuthServiceResetPasswordProcedure:
+			localAuthServiceResetPasswordHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedLocalAuthServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedLocalAuthServiceHandler struct{}
+
+func (UnimplementedLocalAuthServiceHandler) SignUp(context.Context, *connect.Request[v1.SignUpRequest]) (*connect.Response[v1.SignUpResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("localauth.v1.LocalAuthService.SignUp is not implemented"))
+}
+
+func (UnimplementedLocalAuthServiceHandler) Login(context.Context, *connect.Request[v1.LoginRequest]) (*connect.Response[v1.LoginResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("localauth.v1.LocalAuthService.Login is not implemented"))
+}
+
+func (UnimplementedLocalAuthServiceHandler) ChangePassword(context.Context, *connect.Request[v1.ChangePasswordRequest]) (*connect.Response[v1.ChangePasswordResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("localauth.v1.LocalAuthService.ChangePassword is not implemented"))
+}
+
+func (UnimplementedLocalAuthServiceHandler) RequestPasswordReset(context.Context, *connect.Request[v1.RequestPasswordResetRequest]) (*connect.Response[v1.RequestPasswordResetResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("localauth.v1.LocalAuthService.RequestPasswordReset is not implemented"))
+}
+
+func (UnimplementedLocalAuthServiceHandler) ResetPassword(context.Context, *connect.Request[v1.ResetPasswordRequest]) (*connect.Response[v1.ResetPasswordResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("localauth.v1.LocalAuthService.ResetPassword is not implemented"))
+}
@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: role/v1/role.proto
+
+package rolev1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/xiao1203/go-onion-grpc-template/gen/role/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// RoleServiceName is the fully-qualified name of the RoleService service.
+	RoleServiceName = "role.v1.RoleService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// RoleServiceGrantRoleProcedure is the fully-qualified name of the RoleService's GrantRole RPC.
+	RoleServiceGrantRoleProcedure = "/role.v1.RoleService/GrantRole"
+	// RoleServiceRevokeRoleProcedure is the fully-qualified name of the RoleService's RevokeRole RPC.
+	RoleServiceRevokeRoleProcedure = "/role.v1.RoleService/RevokeRole"
+	// RoleServiceListUserRolesProcedure is the fully-qualified name of the RoleService's ListUserRoles
+	// RPC.
+	RoleServiceListUserRolesProcedure = "/role.v1.RoleService/ListUserRoles"
+)
+
+// RoleServiceClient is a client for the role.v1.RoleService service.
+type RoleServiceClient interface {
+	// Admin: grant a role to a user.
+	GrantRole(context.Context, *connect.Request[v1.GrantRoleRequest]) (*connect.Response[v1.GrantRoleResponse], error)
+	// Admin: remove a role from a user.
+	RevokeRole(context.Context, *connect.Request[v1.RevokeRoleRequest]) (*connect.Response[v1.RevokeRoleResponse], error)
+	// Admin: roles granted to a user in the database.
+	ListUserRoles(context.Context, *connect.Request[v1.ListUserRolesRequest]) (*connect.Response[v1.ListUserRolesResponse], error)
+}
+
+// NewRoleServiceClient constructs a client for the role.v1.RoleService service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewRoleServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) RoleServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	roleServiceMethods := v1.File_role_v1_role_proto.Services().ByName("RoleService").Methods()
+	return &roleServiceClient{
+		grantRole: connect.NewClient[v1.GrantRoleRequest, v1.GrantRoleResponse](
+			httpClient,
+			baseURL+RoleServiceGrantRoleProcedure,
+			connect.WithSchema(roleServiceMethods.ByName("GrantRole")),
+			connect.WithClientOptions(opts...),
+		),
+		revokeRole: connect.NewClient[v1.RevokeRoleRequest, v1.RevokeRoleResponse](
+			httpClient,
+			baseURL+RoleServiceRevokeRoleProcedure,
+			connect.WithSchema(roleServiceMethods.ByName("RevokeRole")),
+			connect.WithClientOptions(opts...),
+		),
+		listUserRoles: connect.NewClient[v1.ListUserRolesRequest, v1.ListUserRolesResponse](
+			httpClient,
+			baseURL+RoleServiceListUserRolesProcedure,
+			connect.WithSchema(roleServiceMethods.ByName("ListUserRoles")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// roleServiceClient implements RoleServiceClient.
+type roleServiceClient struct {
+	grantRole     *connect.Client[v1.GrantRoleRequest, v1.GrantRoleResponse]
+	revokeRole    *connect.Client[v1.RevokeRoleRequest, v1.RevokeRoleResponse]
+	listUserRoles *connect.Client[v1.ListUserRolesRequest, v1.ListUserRolesResponse]
+}
+
+// GrantRole calls role.v1.RoleService.GrantRole.
+func (c *roleServiceClient) GrantRole(ctx context.Context, req *connect.Request[v1.GrantRoleRequest]) (*connect.Response[v1.GrantRoleResponse], error) {
+	return c.grantRole.CallUnary(ctx, req)
+}
+
+// RevokeRole calls role.v1.RoleService.RevokeRole.
+func (c *roleServiceClient) RevokeRole(ctx context.Context, req *connect.Request[v1.RevokeRoleRequest]) (*connect.Response[v1.RevokeRoleResponse], error) {
+	return c.revokeRole.CallUnary(ctx, req)
+}
+
+// ListUserRoles calls role.v1.RoleService.ListUserRoles.
+func (c *roleServiceClient) ListUserRoles(ctx context.Context, req *connect.Request[v1.ListUserRolesRequest]) (*connect.Response[v1.ListUserRolesResponse], error) {
+	return c.listUserRoles.CallUnary(ctx, req)
+}
+
+// RoleServiceHandler is an implementation of the role.v1.RoleService service.
+type RoleServiceHandler interface {
+	// Admin: grant a role to a user.
+	GrantRole(context.Context, *connect.Request[v1.GrantRoleRequest]) (*connect.Response[v1.GrantRoleResponse], error)
+	// Admin: remove a role from a user.
+	RevokeRole(context.Context, *connect.Request[v1.RevokeRoleRequest]) (*connect.Response[v1.RevokeRoleResponse], error)
+	// Admin: roles granted to a user in the database.
+	ListUserRoles(context.Context, *connect.Request[v1.ListUserRolesRequest]) (*connect.Response[v1.ListUserRolesResponse], error)
+}
+
+// NewRoleServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewRoleServiceHandler(svc RoleServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	roleServiceMethods := v1.File_role_v1_role_proto.Services().ByName("RoleService").Methods()
+	roleServiceGrantRoleHandler := connect.NewUnaryHandler(
+		RoleServiceGrantRoleProcedure,
+		svc.GrantRole,
+		connect.WithSchema(roleServiceMethods.ByName("GrantRole")),
+		connect.WithHandlerOptions(opts...),
+	)
+	roleServiceRevokeRoleHandler := connect.NewUnaryHandler(
+		RoleServiceRevokeRoleProcedure,
+		svc.RevokeRole,
+		connect.WithSchema(roleServiceMethods.ByName("RevokeRole")),
+		connect.WithHandlerOptions(opts...),
+	)
+	roleServiceListUserRolesHandler := connect.NewUnaryHandler(
+		RoleServiceListUserRolesProcedure,
+		svc.ListUserRoles,
+		connect.WithSchema(roleServiceMethods.ByName("ListUserRoles")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/role.v1.RoleService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case RoleServiceGrantRoleProcedure:
+			roleServiceGrantRoleHandler.ServeHTTP(w, r)
+		case RoleServiceRevokeRoleProcedure:
+			roleServiceRevokeRoleHandler.ServeHTTP(w, r)
+		case RoleServiceListUserRolesProcedure:
+			roleServiceListUserRolesHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedRoleServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedRoleServiceHandler struct{}
+
+func (UnimplementedRoleServiceHandler) GrantRole(context.Context, *connect.Request[v1.GrantRoleRequest]) (*connect.Response[v1.GrantRoleResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("role.v1.RoleService.GrantRole is not implemented"))
+}
+
+func (UnimplementedRoleServiceHandler) RevokeRole(context.Context, *connect.Request[v1.RevokeRoleRequest]) (*connect.Response[v1.RevokeRoleResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("role.v1.RoleService.RevokeRole is not implemented"))
+}
+
+func (UnimplementedRoleServiceHandler) ListUserRoles(context.Context, *connect.Request[v1.ListUserRolesRequest]) (*connect.Response[v1.ListUserRolesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("role.v1.RoleService.ListUserRoles is not implemented"))
+}
@@ -7,6 +7,8 @@
 package samplev1
 
 import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	_ "github.com/xiao1203/go-onion-grpc-template/gen/auth"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -89,6 +91,7 @@ func (x *Sample) GetCount() uint32 {
 	return 0
 }
 
+// Lengths match the VARCHAR(255) columns of samples.
 type CreateSampleRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -557,41 +560,43 @@ var File_sample_v1_sample_proto protoreflect.FileDescriptor
 
 const file_sample_v1_sample_proto_rawDesc = "" +
 	"\n" +
-	"\x16sample/v1/sample.proto\x12\tsample.v1\"\\\n" +
+	"\x16sample/v1/sample.proto\x12\tsample.v1\x1a\x12auth/options.proto\x1a\x1bbuf/validate/validate.proto\"\\\n" +
 	"\x06Sample\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
 	"\acontent\x18\x03 \x01(\tR\acontent\x12\x14\n" +
-	"\x05count\x18\x04 \x01(\rR\x05count\"Y\n" +
-	"\x13CreateSampleRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
-	"\acontent\x18\x02 \x01(\tR\acontent\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\rR\x05count\"o\n" +
+	"\x13CreateSampleRequest\x12\x1e\n" +
+	"\x04name\x18\x01 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\x04name\x12\"\n" +
+	"\acontent\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\acontent\x12\x14\n" +
 	"\x05count\x18\x03 \x01(\rR\x05count\"A\n" +
 	"\x14CreateSampleResponse\x12)\n" +
-	"\x06sample\x18\x01 \x01(\v2\x11.sample.v1.SampleR\x06sample\"\"\n" +
-	"\x10GetSampleRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\">\n" +
+	"\x06sample\x18\x01 \x01(\v2\x11.sample.v1.SampleR\x06sample\"+\n" +
+	"\x10GetSampleRequest\x12\x17\n" +
+	"\x02id\x18\x01 \x01(\x03B\a\xbaH\x04\"\x02 \x00R\x02id\">\n" +
 	"\x11GetSampleResponse\x12)\n" +
 	"\x06sample\x18\x01 \x01(\v2\x11.sample.v1.SampleR\x06sample\"\x14\n" +
 	"\x12ListSamplesRequest\"B\n" +
 	"\x13ListSamplesResponse\x12+\n" +
-	"\asamples\x18\x01 \x03(\v2\x11.sample.v1.SampleR\asamples\"i\n" +
-	"\x13UpdateSampleRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
-	"\acontent\x18\x03 \x01(\tR\acontent\x12\x14\n" +
+	"\asamples\x18\x01 \x03(\v2\x11.sample.v1.SampleR\asamples\"\x88\x01\n" +
+	"\x13UpdateSampleRequest\x12\x17\n" +
+	"\x02id\x18\x01 \x01(\x03B\a\xbaH\x04\"\x02 \x00R\x02id\x12\x1e\n" +
+	"\x04name\x18\x02 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\x04name\x12\"\n" +
+	"\acontent\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\acontent\x12\x14\n" +
 	"\x05count\x18\x04 \x01(\rR\x05count\"A\n" +
 	"\x14UpdateSampleResponse\x12)\n" +
-	"\x06sample\x18\x01 \x01(\v2\x11.sample.v1.SampleR\x06sample\"%\n" +
-	"\x13DeleteSampleRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\"\x16\n" +
-	"\x14DeleteSampleResponse2\xa2\x03\n" +
+	"\x06sample\x18\x01 \x01(\v2\x11.sample.v1.SampleR\x06sample\".\n" +
+	"\x13DeleteSampleRequest\x12\x17\n" +
+	"\x02id\x18\x01 \x01(\x03B\a\xbaH\x04\"\x02 \x00R\x02id\"\x16\n" +
+	"\x14DeleteSampleResponse2\xa8\x03\n" +
 	"\rSampleService\x12Q\n" +
 	"\fCreateSample\x12\x1e.sample.v1.CreateSampleRequest\x1a\x1f.sample.v1.CreateSampleResponse\"\x00\x12H\n" +
 	"\tGetSample\x12\x1b.sample.v1.GetSampleRequest\x1a\x1c.sample.v1.GetSampleResponse\"\x00\x12N\n" +
 	"\vListSamples\x12\x1d.sample.v1.ListSamplesRequest\x1a\x1e.sample.v1.ListSamplesResponse\"\x00\x12Q\n" +
 	"\fUpdateSample\x12\x1e.sample.v1.UpdateSampleRequest\x1a\x1f.sample.v1.UpdateSampleResponse\"\x00\x12Q\n" +
-	"\fDeleteSample\x12\x1e.sample.v1.DeleteSampleRequest\x1a\x1f.sample.v1.DeleteSampleResponse\"\x00BCZAgithub.com/xiao1203/go-onion-grpc-template/gen/sample/v1;samplev1b\x06proto3"
+	"\fDeleteSample\x12\x1e.sample.v1.DeleteSampleRequest\x1a\x1f.sample.v1.DeleteSampleResponse\"\x00\x1a\x04\xd0\xf3\x18\x01BCZAgithub.com/xiao1203/go-onion-grpc-template/gen/sample/v1;samplev1b\x06proto3"
 
 var (
 	file_sample_v1_sample_proto_rawDescOnce sync.Once
@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: token/v1/token.proto
+
+package tokenv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/xiao1203/go-onion-grpc-template/gen/token/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// TokenServiceName is the fully-qualified name of the TokenService service.
+	TokenServiceName = "token.v1.TokenService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// TokenServiceRevokeTokenProcedure is the fully-qualified name of the TokenService's RevokeToken
+	// RPC.
+	TokenServiceRevokeTokenProcedure = "/token.v1.TokenService/RevokeToken"
+	// TokenServiceRevokeUserTokensProcedure is the fully-qualified name of the TokenService's
+	// RevokeUserTokens RPC.
+	TokenServiceRevokeUserTokensProcedure = "/token.v1.TokenService/RevokeUserTokens"
+	// TokenServiceRevokeMyTokensProcedure is the fully-qualified name of the TokenService's
+	// RevokeMyTokens RPC.
+	TokenServiceRevokeMyTokensProcedure = "/token.v1.TokenService/RevokeMyTokens"
+)
+
+// TokenServiceClient is a client for the token.v1.TokenService service.
+type TokenServiceClient interface {
+	// Admin: revoke one token by jti.
+	RevokeToken(context.Context, *connect.Request[v1.RevokeTokenRequest]) (*connect.Response[v1.RevokeTokenResponse], error)
+	// Admin: force logout of a user (e.g. after suspension).
+	RevokeUserTokens(context.Context, *connect.Request[v1.RevokeUserTokensRequest]) (*connect.Response[v1.RevokeUserTokensResponse], error)
+	// Self-service: sign out everywhere.
+	RevokeMyTokens(context.Context, *connect.Request[v1.RevokeMyTokensRequest]) (*connect.Response[v1.RevokeMyTokensResponse], error)
+}
+
+// NewTokenServiceClient constructs a client for the token.v1.TokenService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewTokenServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) TokenServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	tokenServiceMethods := v1.File_token_v1_token_proto.Services().ByName("TokenService").Methods()
+	return &tokenServiceClient{
+		revokeToken: connect.NewClient[v1.RevokeTokenRequest, v1.RevokeTokenResponse](
+			httpClient,
+			baseURL+TokenServiceRevokeTokenProcedure,
+			connect.WithSchema(tokenServiceMethods.ByName("RevokeToken")),
+			connect.WithClientOptions(opts...),
+		),
+		revokeUserTokens: connect.NewClient[v1.RevokeUserTokensRequest, v1.RevokeUserTokensResponse](
+			httpClient,
+			baseURL+TokenServiceRevokeUserTokensProcedure,
+			connect.WithSchema(tokenServiceMethods.ByName("RevokeUserTokens")),
+			connect.WithClientOptions(opts...),
+		),
+		revokeMyTokens: connect.NewClient[v1.RevokeMyTokensRequest, v1.RevokeMyTokensResponse](
+			httpClient,
+			baseURL+TokenServiceRevokeMyTokensProcedure,
+			connect.WithSchema(tokenServiceMethods.ByName("RevokeMyTokens")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// tokenServiceClient implements TokenServiceClient.
+type tokenServiceClient struct {
+	revokeToken      *connect.Client[v1.RevokeTokenRequest, v1.RevokeTokenResponse]
+	revokeUserTokens *connect.Client[v1.RevokeUserTokensRequest, v1.RevokeUserTokensResponse]
+	revokeMyTokens   *connect.Client[v1.RevokeMyTokensRequest, v1.RevokeMyTokensResponse]
+}
+
+// RevokeToken calls token.v1.TokenService.RevokeToken.
+func (c *tokenServiceClient) RevokeToken(ctx context.Context, req *connect.Request[v1.RevokeTokenRequest]) (*connect.Response[v1.RevokeTokenResponse], error) {
+	return c.revokeToken.CallUnary(ctx, req)
+}
+
+// RevokeUserTokens calls token.v1.TokenService.RevokeUserTokens.
+func (c *tokenServiceClient) RevokeUserTokens(ctx context.Context, req *connect.Request[v1.RevokeUserTokensRequest]) (*connect.Response[v1.RevokeUserTokensResponse], error) {
+	return c.revokeUserTokens.CallUnary(ctx, req)
+}
+
+// RevokeMyTokens calls token.v1.TokenService.RevokeMyTokens.
+func (c *tokenServiceClient) RevokeMyTokens(ctx context.Context, req *connect.Request[v1.RevokeMyTokensRequest]) (*connect.Response[v1.RevokeMyTokensResponse], error) {
+	return c.revokeMyTokens.CallUnary(ctx, req)
+}
+
+// TokenServiceHandler is an implementation of the token.v1.TokenService service.
+type TokenServiceHandler interface {
+	// Admin: revoke one token by jti.
+	RevokeToken(context.Context, *connect.Request[v1.RevokeTokenRequest]) (*connect.Response[v1.RevokeTokenResponse], error)
+	// Admin: force logout of a user (e.g. after suspension).
+	RevokeUserTokens(context.Context, *connect.Request[v1.RevokeUserTokensRequest]) (*connect.Response[v1.RevokeUserTokensResponse], error)
+	// Self-service: sign out everywhere.
+	RevokeMyTokens(context.Context, *connect.Request[v1.RevokeMyTokensRequest]) (*connect.Response[v1.RevokeMyTokensResponse], error)
+}
+
+// NewTokenServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewTokenServiceHandler(svc TokenServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	tokenServiceMethods := v1.File_token_v1_token_proto.Services().ByName("TokenService").Methods()
+	tokenServiceRevokeTokenHandler := connect.NewUnaryHandler(
+		TokenServiceRevokeTokenProcedure,
+		svc.RevokeToken,
+		connect.WithSchema(tokenServiceMethods.ByName("RevokeToken")),
+		connect.WithHandlerOptions(opts...),
+	)
+	tokenServiceRevokeUserTokensHandler := connect.NewUnaryHandler(
+		TokenServiceRevokeUserTokensProcedure,
+		svc.RevokeUserTokens,
+		connect.WithSchema(tokenServiceMethods.ByName("RevokeUserTokens")),
+		connect.WithHandlerOptions(opts...),
+	)
+	tokenServiceRevokeMyTokensHandler := connect.NewUnaryHandler(
+		TokenServiceRevokeMyTokensProcedure,
+		svc.RevokeMyTokens,
+		connect.WithSchema(tokenServiceMethods.ByName("RevokeMyTokens")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/token.v1.TokenService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case TokenServiceRevokeTokenProcedure:
+			tokenServiceRevokeTokenHandler.ServeHTTP(w, r)
+		case TokenServiceRevokeUserTokensProcedure:
+			tokenServiceRevokeUserTokensHandler.ServeHTTP(w, r)
+		case TokenServiceRevokeMyTokensProcedure:
+			tokenServiceRevokeMyTokensHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedTokenServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedTokenServiceHandler struct{}
+
+func (UnimplementedTokenServiceHandler) RevokeToken(context.Context, *connect.Request[v1.RevokeTokenRequest]) (*connect.Response[v1.RevokeTokenResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("token.v1.TokenService.RevokeToken is not implemented"))
+}
+
+func (UnimplementedTokenServiceHandler) RevokeUserTokens(context.Context, *connect.Request[v1.RevokeUserTokensRequest]) (*connect.Response[v1.RevokeUserTokensResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("token.v1.TokenService.RevokeUserTokens is not implemented"))
+}
+
+func (UnimplementedTokenServiceHandler) RevokeMyTokens(context.Context, *connect.Request[v1.RevokeMyTokensRequest]) (*connect.Response[v1.RevokeMyTokensResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("token.v1.TokenService.RevokeMyTokens is not implemented"))
+}
@@ -7,6 +7,7 @@
 package userv1
 
 import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -215,9 +216,10 @@ func (x *GetMeResponse) GetUser() *User {
 }
 
 type UpdateMyProfileRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DisplayName   string                 `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
-	PictureUrl    string                 `protobuf:"bytes,2,opt,name=picture_url,json=pictureUrl,proto3" json:"picture_url,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	DisplayName string                 `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// Empty clears the picture.
+	PictureUrl    string `protobuf:"bytes,2,opt,name=picture_url,json=pictureUrl,proto3" json:"picture_url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -314,7 +316,7 @@ var File_user_v1_user_proto protoreflect.FileDescriptor
 
 const file_user_v1_user_proto_rawDesc = "" +
 	"\n" +
-	"\x12user/v1/user.proto\x12\auser.v1\"\a\n" +
+	"\x12user/v1/user.proto\x12\auser.v1\x1a\x1bbuf/validate/validate.proto\"\a\n" +
 	"\x05Empty\"\x86\x01\n" +
 	"\x04User\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x14\n" +
@@ -325,15 +327,16 @@ const file_user_v1_user_proto_rawDesc = "" +
 	"\x05roles\x18\x05 \x03(\tR\x05roles\"\x0e\n" +
 	"\fGetMeRequest\"2\n" +
 	"\rGetMeResponse\x12!\n" +
-	"\x04user\x18\x01 \x01(\v2\r.user.v1.UserR\x04user\"\\\n" +
-	"\x16UpdateMyProfileRequest\x12!\n" +
-	"\fdisplay_name\x18\x01 \x01(\tR\vdisplayName\x12\x1f\n" +
-	"\vpicture_url\x18\x02 \x01(\tR\n" +
+	"\x04user\x18\x01 \x01(\v2\r.user.v1.UserR\x04user\"x\n" +
+	"\x16UpdateMyProfileRequest\x12-\n" +
+	"\fdisplay_name\x18\x01 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\vdisplayName\x12/\n" +
+	"\vpicture_url\x18\x02 \x01(\tB\x0e\xbaH\v\xd8\x01\x01r\x06\x18\x80\x04\x88\x01\x01R\n" +
 	"pictureUrl\"<\n" +
 	"\x17UpdateMyProfileResponse\x12!\n" +
-	"\x04user\x18\x01 \x01(\v2\r.user.v1.UserR\x04user2\x9f\x01\n" +
-	"\vUserService\x128\n" +
-	"\x05GetMe\x12\x15.user.v1.GetMeRequest\x1a\x16.user.v1.GetMeResponse\"\x00\x12V\n" +
+	"\x04user\x18\x01 \x01(\v2\r.user.v1.UserR\x04user2\xa2\x01\n" +
+	"\vUserService\x12;\n" +
+	"\x05GetMe\x12\x15.user.v1.GetMeRequest\x1a\x16.user.v1.GetMeResponse\"\x03\x90\x02\x01\x12V\n" +
 	"\x0fUpdateMyProfile\x12\x1f.user.v1.UpdateMyProfileRequest\x1a .user.v1.UpdateMyProfileResponse\"\x00B?Z=github.com/xiao1203/go-onion-grpc-template/gen/user/v1;userv1b\x06proto3"
 
 var (
@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// WithAPIKeys accepts "Authorization: ApiKey <key>" or "X-API-Key: <key>" and
+// authenticates the caller as a machine principal whose scopes act as permissions.
+func WithAPIKeys(uc *usecase.APIKeyUsecase) AuthOption {
+	return func(c *authConfig) { c.apiKeys = uc }
+}
+
+// apiKeyFromHeader returns the presented API key, if any.
+func apiKeyFromHeader(req connect.AnyRequest) string {
+	if v := req.Header().Get("X-API-Key"); v != "" {
+		return strings.TrimSpace(v)
+	}
+	parts := strings.SplitN(req.Header().Get("Authorization"), " ", 2)
+	if len(parts) == 2 && strings.EqualFold(parts[0], "ApiKey") {
+		return strings.TrimSpace(parts[1])
+	}
+	return ""
+}
+
+// apiKeyAuthenticator authenticates service-to-service callers presenting an API key.
+type apiKeyAuthenticator struct{ apiKeys *usecase.APIKeyUsecase }
+
+func (a apiKeyAuthenticator) Authenticate(ctx context.Context, req connect.AnyRequest) (*Authentication, error) {
+	key := apiKeyFromHeader(req)
+	if key == "" {
+		return nil, nil
+	}
+	k, err := a.apiKeys.Authenticate(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &Authentication{Principal: apiKeyPrincipal(k)}, nil
+}
+
+func apiKeyPrincipal(k *entity.APIKey) *auth.Principal {
+	return &auth.Principal{
+		Subject:  "apikey:" + strconv.FormatInt(k.ID, 10),
+		Provider: "apikey",
+		Machine:  true,
+		Scopes:   append([]string(nil), k.Scopes...),
+		Claims: map[string]any{
+			"api_key_prefix": k.Prefix,
+			"owner_user_id":  k.OwnerUserID,
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	iauth "github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// memAPIKeys is an in-memory APIKeyRepository.
+type memAPIKeys struct{ keys []*entity.APIKey }
+
+func (m *memAPIKeys) Create(ctx context.Context, k *entity.APIKey) (*entity.APIKey, error) {
+	cp := *k
+	cp.ID = int64(len(m.keys) + 1)
+	m.keys = append(m.keys, &cp)
+	return &cp, nil
+}
+
+func (m *memAPIKeys) FindByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	for _, k := range m.keys {
+		if k.KeyHash == keyHash {
+			cp := *k
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memAPIKeys) List(ctx context.Context) ([]*entity.APIKey, error) { return m.keys, nil }
+
+func (m *memAPIKeys) Revoke(ctx context.Context, id int64, at time.Time) error {
+	m.keys[id-1].RevokedAt = &at
+	return nil
+}
+
+func (m *memAPIKeys) TouchLastUsed(ctx context.Context, id int64, at time.Time) error { return nil }
+
+func TestAuth_APIKey(t *testing.T) {
+	uc := usecase.NewAPIKeyUsecase(&memAPIKeys{})
+	_, secret, err := uc.Issue(context.Background(), usecase.IssueAPIKeyInput{Name: "batch", OwnerUserID: 1, Scopes: []string{"sample.write"}})
+	if err != nil {
+		t.Fatalf("Issue() failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    connect.Code
+	}{
+		{"正常系: Authorization: ApiKey で機械プリンシパルになること", map[string]string{"Authorization": "ApiKey " + secret}, 0},
+		{"正常系: X-API-Key で機械プリンシパルになること", map[string]string{"X-API-Key": secret}, 0},
+		{"異常系: 未知のキーはUnauthenticatedになること", map[string]string{"X-API-Key": "ak_00000000_unknown"}, connect.CodeUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := connect.NewRequest(&pingReq{})
+			for k, v := range tt.headers {
+				req.Header().Set(k, v)
+			}
+			var got *iauth.Principal
+			next := func(ctx context.Context, r connect.AnyRequest) (connect.AnyResponse, error) {
+				got, _ = iauth.FromContext(ctx)
+				return nil, nil
+			}
+			_, err := AuthUnaryInterceptor(nil, WithAPIKeys(uc))(next)(context.Background(), req)
+			if tt.want != 0 {
+				if connect.CodeOf(err) != tt.want {
+					t.Fatalf("want %v, got %v", tt.want, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			if got == nil || !got.Machine || got.UserID != 0 || got.Provider != "apikey" {
+				t.Fatalf("principal = %+v, want machine principal", got)
+			}
+			// scopes act as permissions for (auth.authz) / AUTHZ_POLICY rules
+			if err := (&iauth.Policy{}).Authorize(got, iauth.Rule{Permissions: []string{"sample.write"}}); err != nil {
+				t.Fatalf("scope should grant the permission: %v", err)
+			}
+		})
+	}
+}
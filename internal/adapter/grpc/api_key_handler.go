@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	apikeyv1 "github.com/xiao1203/go-onion-grpc-template/gen/apikey/v1"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// APIKeyHandler implements the admin ApiKeyService (admin role is enforced by (auth.authz)).
+type APIKeyHandler struct{ uc *usecase.APIKeyUsecase }
+
+func NewAPIKeyHandler(uc *usecase.APIKeyUsecase) *APIKeyHandler { return &APIKeyHandler{uc: uc} }
+
+func (h *APIKeyHandler) IssueApiKey(ctx context.Context, req *connect.Request[apikeyv1.IssueApiKeyRequest]) (*connect.Response[apikeyv1.IssueApiKeyResponse], error) {
+	in := usecase.IssueAPIKeyInput{
+		Name:        req.Msg.GetName(),
+		OwnerUserID: int64(req.Msg.GetOwnerUserId()),
+		Scopes:      req.Msg.GetScopes(),
+	}
+	if in.OwnerUserID == 0 {
+		p, ok := auth.FromContext(ctx)
+		if !ok || p.UserID == 0 {
+			return nil, apperr.ToConnect(ergo.WithCode(ergo.New("owner_user_id is required", apperr.FieldViolation("owner_user_id", "is required")), apperr.InvalidArgument))
+		}
+		in.OwnerUserID = p.UserID
+	}
+	if v := req.Msg.GetExpiresAt(); v > 0 {
+		t := time.Unix(v, 0)
+		in.ExpiresAt = &t
+	}
+	k, secret, err := h.uc.Issue(ctx, in)
+	if err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	return connect.NewResponse(&apikeyv1.IssueApiKeyResponse{ApiKey: toProtoAPIKey(k), Secret: secret}), nil
+}
+
+func (h *APIKeyHandler) ListApiKeys(ctx context.Context, req *connect.Request[apikeyv1.ListApiKeysRequest]) (*connect.Response[apikeyv1.ListApiKeysResponse], error) {
+	keys, err := h.uc.List(ctx)
+	if err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	res := &apikeyv1.ListApiKeysResponse{ApiKeys: make([]*apikeyv1.ApiKey, 0, len(keys))}
+	for _, k := range keys {
+		res.ApiKeys = append(res.ApiKeys, toProtoAPIKey(k))
+	}
+	return connect.NewResponse(res), nil
+}
+
+func (h *APIKeyHandler) RevokeApiKey(ctx context.Context, req *connect.Request[apikeyv1.RevokeApiKeyRequest]) (*connect.Response[apikeyv1.RevokeApiKeyResponse], error) {
+	if err := h.uc.Revoke(ctx, req.Msg.GetId()); err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	return connect.NewResponse(&apikeyv1.RevokeApiKeyResponse{}), nil
+}
+
+func toProtoAPIKey(k *entity.APIKey) *apikeyv1.ApiKey {
+	if k == nil {
+		return nil
+	}
+	return &apikeyv1.ApiKey{
+		Id:          k.ID,
+		Name:        k.Name,
+		Prefix:      k.Prefix,
+		OwnerUserId: uint64(k.OwnerUserID),
+		Scopes:      append([]string(nil), k.Scopes...),
+		ExpiresAt:   unixOrZero(k.ExpiresAt),
+		LastUsedAt:  unixOrZero(k.LastUsedAt),
+		RevokedAt:   unixOrZero(k.RevokedAt),
+		CreatedAt:   k.CreatedAt.Unix(),
+	}
+}
+
+func unixOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,15 @@
+package grpc
+
+import (
+	"net/http"
+
+	apikeyv1connect "github.com/xiao1203/go-onion-grpc-template/gen/apikey/v1/apikeyv1connect"
+)
+
+func init() { Add(registerAPIKey) }
+
+func registerAPIKey(mux *http.ServeMux, deps Deps) {
+	h := NewAPIKeyHandler(deps.authDeps().apiKeys)
+	path, handler := apikeyv1connect.NewApiKeyServiceHandler(h, deps.AuthInterceptors())
+	mux.Handle(path, handler)
+}
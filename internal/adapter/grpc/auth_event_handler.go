@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	autheventv1 "github.com/xiao1203/go-onion-grpc-template/gen/authevent/v1"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// AuthEventHandler implements the admin AuthEventService (admin role is enforced by (auth.authz)).
+type AuthEventHandler struct{ uc *usecase.AuthEventUsecase }
+
+func NewAuthEventHandler(uc *usecase.AuthEventUsecase) *AuthEventHandler {
+	return &AuthEventHandler{uc: uc}
+}
+
+func (h *AuthEventHandler) ListAuthEvents(ctx context.Context, req *connect.Request[autheventv1.ListAuthEventsRequest]) (*connect.Response[autheventv1.ListAuthEventsResponse], error) {
+	m := req.Msg
+	f := domainrepo.AuthEventFilter{
+		UserID:  int64(m.GetUserId()),
+		Subject: m.GetSubject(),
+		Issuer:  m.GetIssuer(),
+		Outcome: m.GetOutcome(),
+		Reason:  m.GetReason(),
+		IP:      m.GetIpAddress(),
+		Limit:   int(m.GetPageSize()),
+	}
+	if v := m.GetSince(); v > 0 {
+		f.Since = time.Unix(v, 0)
+	}
+	if v := m.GetUntil(); v > 0 {
+		f.Until = time.Unix(v, 0)
+	}
+	// The page token is the ID of the last event of the previous page.
+	if t := m.GetPageToken(); t != "" {
+		id, err := strconv.ParseInt(t, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, apperr.ToConnect(ergo.WithCode(ergo.New("invalid page_token", apperr.FieldViolation("page_token", "is invalid")), apperr.InvalidArgument))
+		}
+		f.BeforeID = id
+	}
+	events, next, err := h.uc.Search(ctx, f)
+	if err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	out := &autheventv1.ListAuthEventsResponse{Events: make([]*autheventv1.AuthEvent, 0, len(events))}
+	for _, e := range events {
+		out.Events = append(out.Events, &autheventv1.AuthEvent{
+			Id:         e.ID,
+			Outcome:    e.Outcome,
+			Reason:     e.Reason,
+			Method:     e.Method,
+			Provider:   e.Provider,
+			Issuer:     e.Issuer,
+			Subject:    e.Subject,
+			UserId:     uint64(e.UserID),
+			Procedure:  e.Procedure,
+			IpAddress:  e.IP,
+			UserAgent:  e.UserAgent,
+			OccurredAt: e.OccurredAt.Unix(),
+		})
+	}
+	if next > 0 {
+		out.NextPageToken = strconv.FormatInt(next, 10)
+	}
+	return connect.NewResponse(out), nil
+}
@@ -0,0 +1,15 @@
+package grpc
+
+import (
+	"net/http"
+
+	autheventv1connect "github.com/xiao1203/go-onion-grpc-template/gen/authevent/v1/autheventv1connect"
+)
+
+func init() { Add(registerAuthEvent) }
+
+func registerAuthEvent(mux *http.ServeMux, deps Deps) {
+	h := NewAuthEventHandler(deps.authDeps().events)
+	path, handler := autheventv1connect.NewAuthEventServiceHandler(h, deps.AuthInterceptors())
+	mux.Handle(path, handler)
+}
@@ -0,0 +1,161 @@
+package grpc
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/newmo-oss/ergo"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// Failure reasons of authentication events.
+const (
+	reasonMissingCredentials = "missing_credentials"
+	reasonInvalidCredentials = "invalid_credentials"
+	reasonMalformed          = "malformed"
+	reasonExpired            = "expired"
+	reasonNotYetValid        = "not_yet_valid"
+	reasonBadAudience        = "bad_audience"
+	reasonBadIssuer          = "bad_issuer"
+	reasonUnknownKID         = "unknown_kid"
+	reasonBadSignature       = "bad_signature"
+	reasonMissingClaim       = "missing_claim"
+	reasonRevoked            = "revoked"
+	reasonStepUpRequired     = "step_up_required"
+	reasonInternal           = "internal"
+)
+
+// Recording modes selected by AUTH_EVENTS.
+const (
+	authEventsAll      = "all"
+	authEventsFailures = "failures"
+	authEventsOff      = "off"
+)
+
+// WithAuthEvents records every authentication success and failure through
+// uc (asynchronously). AUTH_EVENTS=failures records failures only and
+// AUTH_EVENTS=off disables recording; it is read when the option is applied.
+func WithAuthEvents(uc *usecase.AuthEventUsecase) AuthOption {
+	return func(c *authConfig) {
+		c.events = uc
+		c.eventsMode = authEventsModeFromEnv()
+	}
+}
+
+// authEventsModeFromEnv reads AUTH_EVENTS; unknown values record everything.
+func authEventsModeFromEnv() string {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("AUTH_EVENTS"))) {
+	case "off", "0", "false":
+		return authEventsOff
+	case "failures":
+		return authEventsFailures
+	default:
+		return authEventsAll
+	}
+}
+
+// recordAuthEvent records the outcome of authenticating req with method
+// (empty when no credentials were recognized). An empty reason is a success
+// of p; failures may pass a nil p.
+func (c *authConfig) recordAuthEvent(req connect.AnyRequest, method string, p *auth.Principal, reason string) {
+	if c.events == nil || c.eventsMode == authEventsOff || (c.eventsMode == authEventsFailures && reason == "") {
+		return
+	}
+	e := &entity.AuthEvent{
+		Outcome:   entity.AuthEventSuccess,
+		Reason:    reason,
+		Method:    method,
+		Procedure: req.Spec().Procedure,
+		IP:        peerIP(req.Peer().Addr),
+		UserAgent: req.Header().Get("User-Agent"),
+	}
+	if reason != "" {
+		e.Outcome = entity.AuthEventFailure
+	}
+	if p != nil {
+		e.Provider, e.Issuer, e.Subject, e.UserID = p.Provider, p.Issuer, p.Subject, p.UserID
+	} else if method == "bearer" {
+		e.Issuer, e.Subject = unverifiedIssuerSubject(req)
+	}
+	c.events.Record(e)
+}
+
+// authMethod names the credential type an authenticator handles.
+func authMethod(a Authenticator) string {
+	switch a.(type) {
+	case devBypassAuthenticator:
+		return "dev"
+	case clientCertAuthenticator:
+		return "mtls"
+	case apiKeyAuthenticator:
+		return "apikey"
+	case sessionAuthenticator:
+		return "session"
+	case bearerAuthenticator:
+		return "bearer"
+	default:
+		return "custom"
+	}
+}
+
+// failureReason classifies an authentication error for the event log.
+func failureReason(err error) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return reasonExpired
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return reasonNotYetValid
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return reasonBadAudience
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer), errors.Is(err, auth.ErrUntrustedIssuer):
+		return reasonBadIssuer
+	case errors.Is(err, auth.ErrUnknownKID):
+		return reasonUnknownKID
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrSignatureInvalid):
+		return reasonBadSignature
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return reasonMalformed
+	case errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+		return reasonMissingClaim
+	case errors.Is(err, usecase.ErrTokenRevoked):
+		return reasonRevoked
+	case ergo.CodeOf(err) == apperr.Internal:
+		return reasonInternal
+	default:
+		return reasonInvalidCredentials
+	}
+}
+
+// unverifiedIssuerSubject reads iss and sub of the bearer token without
+// verifying it, so that failures can be attributed.
+func unverifiedIssuerSubject(req connect.AnyRequest) (string, string) {
+	parts := strings.SplitN(req.Header().Get("Authorization"), " ", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	var claims jwt.MapClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(strings.TrimSpace(parts[1]), &claims); err != nil {
+		return "", ""
+	}
+	iss, _ := claims["iss"].(string)
+	sub, _ := claims["sub"].(string)
+	return iss, sub
+}
+
+// peerIP strips the port of the peer address. Behind a proxy this is the
+// proxy's address.
+func peerIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
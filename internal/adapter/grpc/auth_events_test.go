@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// memAuthEvents is an in-memory AuthEventRepository.
+type memAuthEvents struct {
+	mu     sync.Mutex
+	events []*entity.AuthEvent
+}
+
+func (m *memAuthEvents) CreateBatch(ctx context.Context, events []*entity.AuthEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, events...)
+	return nil
+}
+
+func (m *memAuthEvents) Search(ctx context.Context, f domainrepo.AuthEventFilter) ([]*entity.AuthEvent, error) {
+	return nil, nil
+}
+
+func TestAuth_Events(t *testing.T) {
+	t.Setenv("DEV_AUTH_BYPASS", "")
+	t.Setenv("AUTH_JWKS_URL", "")
+	t.Setenv("AUTH_ISSUERS", "")
+	t.Setenv("AUTH_ISSUERS_FILE", "")
+	t.Setenv("AUTH_HS256_SECRET", "secret")
+	t.Setenv("AUTH_HS256_AUDIENCE", "api")
+	now := time.Now()
+
+	sign := func(claims jwt.MapClaims, kid string) string {
+		tok := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		if kid != "" {
+			tok.Header["kid"] = kid
+		}
+		s, err := tok.SignedString([]byte("secret"))
+		if err != nil {
+			t.Fatalf("sign err: %v", err)
+		}
+		return s
+	}
+	valid := jwt.MapClaims{"sub": "1", "iss": "self", "aud": "api", "exp": now.Add(time.Minute).Unix()}
+
+	tests := []struct {
+		name        string
+		events      string
+		token       string
+		wantOutcome string
+		wantReason  string
+		wantMethod  string
+	}{
+		{"正常系: 成功が記録されること", "", sign(valid, ""), entity.AuthEventSuccess, "", "bearer"},
+		{"異常系: 期限切れはexpiredとして記録されること", "", sign(jwt.MapClaims{"sub": "1", "iss": "self", "aud": "api", "exp": now.Add(-time.Hour).Unix()}, ""), entity.AuthEventFailure, reasonExpired, "bearer"},
+		{"異常系: audが異なる場合はbad_audienceとして記録されること", "", sign(jwt.MapClaims{"sub": "1", "iss": "self", "aud": "other", "exp": now.Add(time.Minute).Unix()}, ""), entity.AuthEventFailure, reasonBadAudience, "bearer"},
+		{"異常系: 未知のkidはunknown_kidとして記録されること", "", sign(valid, "nope"), entity.AuthEventFailure, reasonUnknownKID, "bearer"},
+		{"異常系: 壊れたトークンはmalformedとして記録されること", "", "not-a-jwt", entity.AuthEventFailure, reasonMalformed, "bearer"},
+		{"異常系: 資格情報が無い場合はmissing_credentialsとして記録されること", "", "", entity.AuthEventFailure, reasonMissingCredentials, ""},
+		{"正常系: AUTH_EVENTS=failuresでは成功を記録しないこと", "failures", sign(valid, ""), "", "", ""},
+		{"正常系: AUTH_EVENTS=offでは失敗も記録しないこと", "off", "not-a-jwt", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AUTH_EVENTS", tt.events)
+			repo := &memAuthEvents{}
+			uc := usecase.NewAuthEventUsecase(repo, 0)
+			req := connect.NewRequest(&pingReq{})
+			req.Header().Set("User-Agent", "test-agent")
+			if tt.token != "" {
+				req.Header().Set("Authorization", "Bearer "+tt.token)
+			}
+			next := func(ctx context.Context, r connect.AnyRequest) (connect.AnyResponse, error) { return nil, nil }
+			_, _ = AuthUnaryInterceptor(nil, WithAuthEvents(uc))(next)(context.Background(), req)
+			if err := uc.Close(context.Background()); err != nil {
+				t.Fatalf("close err: %v", err)
+			}
+			if tt.wantOutcome == "" {
+				if len(repo.events) != 0 {
+					t.Fatalf("events = %+v, want none", repo.events)
+				}
+				return
+			}
+			if len(repo.events) != 1 {
+				t.Fatalf("events = %d, want 1", len(repo.events))
+			}
+			e := repo.events[0]
+			if e.Outcome != tt.wantOutcome || e.Reason != tt.wantReason || e.Method != tt.wantMethod || e.UserAgent != "test-agent" {
+				t.Fatalf("event = %+v", e)
+			}
+			if tt.wantMethod == "bearer" && tt.token != "not-a-jwt" && (e.Subject != "1" || e.Issuer != "self") {
+				t.Fatalf("subject/issuer = %q/%q", e.Subject, e.Issuer)
+			}
+		})
+	}
+}
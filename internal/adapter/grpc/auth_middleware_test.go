@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
 
@@ -33,6 +34,7 @@ func runThrough(t *testing.T, headers map[string]string) (context.Context, error
 
 func TestAuth_DevBypass(t *testing.T) {
 	t.Setenv("DEV_AUTH_BYPASS", "1")
+	t.Setenv("APP_ENV", "dev")
 	ctx, err := runThrough(t, nil)
 	if err != nil {
 		t.Fatalf("err: %v", err)
@@ -42,6 +44,55 @@ func TestAuth_DevBypass(t *testing.T) {
 	}
 }
 
+func TestAuth_DevBypass_Headers(t *testing.T) {
+	t.Setenv("DEV_AUTH_BYPASS", "1")
+	t.Setenv("AUTH_HS256_SECRET", "")
+	t.Setenv("AUTH_JWKS_URL", "")
+	t.Setenv("DEV_USER_ID", "")
+	t.Setenv("DEV_USER_EMAIL", "")
+	t.Setenv("DEV_USER_ROLES", "")
+
+	tests := []struct {
+		name      string
+		appEnv    string
+		headers   map[string]string
+		wantUID   int64
+		wantEmail string
+		wantRoles []string
+		want      connect.Code
+	}{
+		{name: "正常系: 既定ではuserロールのみの開発ユーザーになること", appEnv: "dev", wantUID: 1, wantEmail: "dev@example.com", wantRoles: []string{"user"}},
+		{name: "正常系: X-Dev-*ヘッダでユーザーID/メール/ロールを上書きできること", appEnv: "dev",
+			headers: map[string]string{"X-Dev-User-Id": "42", "X-Dev-Email": "alice@example.com", "X-Dev-Roles": "admin, user"},
+			wantUID: 42, wantEmail: "alice@example.com", wantRoles: []string{"admin", "user"}},
+		{name: "異常系: 数値でないX-Dev-User-IdはInvalidArgumentになること", appEnv: "dev", headers: map[string]string{"X-Dev-User-Id": "abc"}, want: connect.CodeInvalidArgument},
+		{name: "異常系: APP_ENVがdevでなければバイパスされないこと", appEnv: "production", headers: map[string]string{"X-Dev-Roles": "admin"}, want: connect.CodeUnauthenticated},
+		{name: "異常系: APP_ENV未設定ならバイパスされないこと", want: connect.CodeUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_ENV", tt.appEnv)
+			ctx, err := runThrough(t, tt.headers)
+			if tt.want != 0 {
+				if connect.CodeOf(err) != tt.want {
+					t.Fatalf("want %v, got %v", tt.want, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			p, ok := iauth.FromContext(ctx)
+			if !ok {
+				t.Fatalf("principal missing")
+			}
+			if p.UserID != tt.wantUID || p.Email != tt.wantEmail || !slices.Equal(p.Roles, tt.wantRoles) {
+				t.Fatalf("principal = %+v", p)
+			}
+		})
+	}
+}
+
 func TestAuth_HS256_OK(t *testing.T) {
 	t.Setenv("AUTH_HS256_SECRET", "secret")
 	// build HS256 token with sub=1
@@ -72,3 +123,95 @@ func TestAuth_MissingAuth_Unauthenticated(t *testing.T) {
 		t.Fatalf("want Unauthenticated, got %v", connect.CodeOf(err))
 	}
 }
+
+func TestAuth_HS256_Audience(t *testing.T) {
+	t.Setenv("DEV_AUTH_BYPASS", "")
+	t.Setenv("AUTH_JWKS_URL", "")
+	t.Setenv("AUTH_HS256_SECRET", "secret")
+	t.Setenv("AUTH_HS256_AUDIENCE", "api")
+	tests := []struct {
+		name string
+		aud  string
+		want connect.Code
+	}{
+		{"正常系: audが一致するHS256トークンは通ること", "api", 0},
+		{"異常系: audが一致しないHS256トークンはUnauthenticatedになること", "other", connect.CodeUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"sub": "1", "aud": tt.aud, "exp": time.Now().Add(5 * time.Minute).Unix(),
+			}).SignedString([]byte("secret"))
+			if err != nil {
+				t.Fatalf("sign err: %v", err)
+			}
+			_, err = runThrough(t, map[string]string{"Authorization": "Bearer " + s})
+			if tt.want == 0 {
+				if err != nil {
+					t.Fatalf("err: %v", err)
+				}
+				return
+			}
+			if connect.CodeOf(err) != tt.want {
+				t.Fatalf("want %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAuth_CustomAuthenticator(t *testing.T) {
+	t.Setenv("DEV_AUTH_BYPASS", "")
+	t.Setenv("AUTH_JWKS_URL", "")
+	t.Setenv("AUTH_HS256_SECRET", "")
+	custom := AuthenticatorFunc(func(ctx context.Context, req connect.AnyRequest) (*Authentication, error) {
+		if req.Header().Get("X-Partner-Token") != "partner-secret" {
+			return nil, nil
+		}
+		return &Authentication{Principal: &iauth.Principal{Subject: "partner", Machine: true}}, nil
+	})
+	run := func(headers map[string]string) (*iauth.Principal, error) {
+		req := connect.NewRequest(&pingReq{})
+		for k, v := range headers {
+			req.Header().Set(k, v)
+		}
+		var got *iauth.Principal
+		next := func(ctx context.Context, r connect.AnyRequest) (connect.AnyResponse, error) {
+			got, _ = iauth.FromContext(ctx)
+			return nil, nil
+		}
+		_, err := AuthUnaryInterceptor(nil, WithAuthenticators(custom))(next)(context.Background(), req)
+		return got, err
+	}
+
+	t.Run("正常系: 追加したAuthenticatorで認証できること", func(t *testing.T) {
+		p, err := run(map[string]string{"X-Partner-Token": "partner-secret"})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if p == nil || p.Subject != "partner" {
+			t.Fatalf("principal = %+v", p)
+		}
+	})
+	t.Run("異常系: どのAuthenticatorも認証しなければUnauthenticatedになること", func(t *testing.T) {
+		if _, err := run(nil); connect.CodeOf(err) != connect.CodeUnauthenticated {
+			t.Fatalf("want Unauthenticated, got %v", err)
+		}
+	})
+	t.Run("正常系: 複数の資格情報がある場合は組み込みのBearerトークンが先に使われること", func(t *testing.T) {
+		t.Setenv("AUTH_HS256_SECRET", "secret")
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": "1",
+			"exp": time.Now().Add(5 * time.Minute).Unix(),
+		}).SignedString([]byte("secret"))
+		if err != nil {
+			t.Fatalf("sign err: %v", err)
+		}
+		p, err := run(map[string]string{"Authorization": "Bearer " + token, "X-Partner-Token": "partner-secret"})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if p == nil || p.Subject != "1" || p.Machine {
+			t.Fatalf("principal = %+v, want the bearer token subject", p)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+)
+
+// Authenticator is one step of the interceptor's authentication chain.
+type Authenticator interface {
+	// Authenticate returns nil (and no error) when the request carries no
+	// credentials it understands, so that the next authenticator runs.
+	// Errors must carry an apperr code.
+	Authenticate(ctx context.Context, req connect.AnyRequest) (*Authentication, error)
+}
+
+// AuthenticatorFunc adapts a function to Authenticator.
+type AuthenticatorFunc func(ctx context.Context, req connect.AnyRequest) (*Authentication, error)
+
+// Authenticate implements Authenticator.
+func (f AuthenticatorFunc) Authenticate(ctx context.Context, req connect.AnyRequest) (*Authentication, error) {
+	return f(ctx, req)
+}
+
+// Authentication is the result of a successful Authenticator.
+type Authentication struct {
+	Principal *auth.Principal
+	// OnSuccess, when set, decorates the response of a successful call
+	// (e.g. refreshed session cookies).
+	OnSuccess func(connect.AnyResponse)
+}
+
+// WithAuthenticators adds authenticators for new credential types. They run
+// after the built-in ones.
+func WithAuthenticators(a ...Authenticator) AuthOption {
+	return func(c *authConfig) { c.extra = append(c.extra, a...) }
+}
+
+// authenticators returns the chain in order: dev bypass, bearer token (JWKS,
+// then HS256), API key, session cookie, client certificate, extra
+// authenticators. When a request carries several credentials the first
+// recognized one decides; e.g. a mesh caller presenting its certificate
+// while forwarding a user's bearer token is authenticated as the user.
+func (c *authConfig) authenticators() []Authenticator {
+	chain := []Authenticator{devBypassAuthenticator{}, bearerAuthenticator{cfg: c}}
+	if c.apiKeys != nil {
+		chain = append(chain, apiKeyAuthenticator{apiKeys: c.apiKeys})
+	}
+	if c.sessions != nil {
+		chain = append(chain, sessionAuthenticator{sessions: c.sessions})
+	}
+	chain = append(chain, clientCertAuthenticator{})
+	return append(chain, c.extra...)
+}
+
+// devBypassAuthenticator injects the development Principal when
+// DEV_AUTH_BYPASS=1 and APP_ENV=dev. Outside dev the bypass is refused
+// (reported at startup).
+type devBypassAuthenticator struct{}
+
+func (devBypassAuthenticator) Authenticate(ctx context.Context, req connect.AnyRequest) (*Authentication, error) {
+	dev, err := auth.DevBypassFromEnv()
+	if err != nil || dev == nil {
+		return nil, nil
+	}
+	p, err := dev.Principal(req.Header())
+	if err != nil {
+		return nil, ergo.WithCode(err, apperr.InvalidArgument)
+	}
+	return &Authentication{Principal: p}, nil
+}
+
+// bearerAuthenticator verifies "Authorization: Bearer <jwt>" with the
+// verifiers from the environment (trusted issuers, then HS256), resolves the
+// internal user and checks revocation.
+type bearerAuthenticator struct{ cfg *authConfig }
+
+func (a bearerAuthenticator) Authenticate(ctx context.Context, req connect.AnyRequest) (*Authentication, error) {
+	parts := strings.SplitN(req.Header().Get("Authorization"), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return nil, nil
+	}
+	verifiers, err := auth.VerifiersFromEnv()
+	if err != nil {
+		return nil, ergo.WithCode(ergo.Wrap(err, "load token verifiers"), apperr.Internal)
+	}
+	if len(verifiers) == 0 {
+		return nil, ergo.WithCode(ergo.New("no verifier configured"), apperr.Unauthenticated)
+	}
+	p, err := verifiers.Verify(ctx, strings.TrimSpace(parts[1]))
+	if err != nil {
+		return nil, ergo.WithCode(err, apperr.Unauthenticated)
+	}
+	// HS256 tokens are self-issued: their sub already is the internal user ID.
+	if a.cfg.identities != nil && p.Provider != auth.ProviderHS256 {
+		uid, err := a.cfg.identities.ResolveUserID(ctx, externalIdentity(p))
+		if err != nil {
+			return nil, err
+		}
+		p.UserID = uid
+	}
+	if err := checkRevocation(ctx, a.cfg, p); err != nil {
+		return nil, err
+	}
+	return &Authentication{Principal: p}, nil
+}
@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	authpb "github.com/xiao1203/go-onion-grpc-template/gen/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+)
+
+// AuthzUnaryInterceptor enforces the roles / permissions declared for each RPC.
+// The rule comes from the (auth.authz) method option; procedures without it fall
+// back to policy.Procedures. A nil policy is loaded from AUTHZ_POLICY(_FILE).
+// Chain it after AuthUnaryInterceptor so that the Principal is already set.
+func AuthzUnaryInterceptor(policy *auth.Policy) connect.UnaryInterceptorFunc {
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			pol := policy
+			if pol == nil {
+				var err error
+				if pol, err = auth.PolicyFromEnv(); err != nil {
+					return nil, apperr.ToConnect(ergo.WithCode(ergo.Wrap(err, "load authz policy"), apperr.Internal))
+				}
+			}
+			rule, ok := ruleFromSchema(req.Spec().Schema)
+			if !ok {
+				rule, ok = pol.RuleFor(req.Spec().Procedure)
+			}
+			if !ok || rule.IsZero() {
+				return next(ctx, req)
+			}
+			p, ok := auth.FromContext(ctx)
+			if !ok {
+				return nil, apperr.ToConnect(ergo.WithCode(ergo.New("unauthenticated"), apperr.Unauthenticated))
+			}
+			if err := pol.Authorize(p, rule); err != nil {
+				return nil, apperr.ToConnect(ergo.WithCode(ergo.Wrap(err, "authorize", apperr.ClientMessage(err.Error())), apperr.PermissionDenied))
+			}
+			return next(ctx, req)
+		}
+	})
+}
+
+// ruleFromSchema reads the (auth.authz) option from the method descriptor.
+func ruleFromSchema(schema any) (auth.Rule, bool) {
+	md, ok := schema.(protoreflect.MethodDescriptor)
+	if !ok || !proto.HasExtension(md.Options(), authpb.E_Authz) {
+		return auth.Rule{}, false
+	}
+	r, _ := proto.GetExtension(md.Options(), authpb.E_Authz).(*authpb.AuthzRule)
+	return auth.Rule{Roles: r.GetRoles(), Permissions: r.GetPermissions()}, true
+}
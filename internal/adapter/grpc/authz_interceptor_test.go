@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	authpb "github.com/xiao1203/go-onion-grpc-template/gen/auth"
+	iauth "github.com/xiao1203/go-onion-grpc-template/internal/auth"
+)
+
+// testService builds "test.v1.TestService" with Annotated (roles: admin) and Plain (no option).
+func testService(t *testing.T) protoreflect.ServiceDescriptor {
+	t.Helper()
+	annotated := &descriptorpb.MethodOptions{}
+	proto.SetExtension(annotated, authpb.E_Authz, &authpb.AuthzRule{Roles: []string{"admin"}})
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test/v1/test.proto"),
+		Package:    proto.String("test.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/empty.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("TestService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Annotated"), InputType: proto.String(".google.protobuf.Empty"), OutputType: proto.String(".google.protobuf.Empty"), Options: annotated},
+				{Name: proto.String("Plain"), InputType: proto.String(".google.protobuf.Empty"), OutputType: proto.String(".google.protobuf.Empty")},
+			},
+		}},
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("build descriptor: %v", err)
+	}
+	return fd.Services().Get(0)
+}
+
+func callWithRoles(t *testing.T, md protoreflect.MethodDescriptor, policy *iauth.Policy, roles []string) error {
+	t.Helper()
+	procedure := "/test.v1.TestService/" + string(md.Name())
+	inject := connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			return next(iauth.WithPrincipal(ctx, &iauth.Principal{UserID: 1, Roles: roles}), req)
+		}
+	})
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewUnaryHandler(procedure,
+		func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		},
+		connect.WithSchema(md),
+		connect.WithInterceptors(inject, AuthzUnaryInterceptor(policy)),
+	))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	client := connect.NewClient[emptypb.Empty, emptypb.Empty](srv.Client(), srv.URL+procedure)
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	return err
+}
+
+func TestAuthz_ProtoOption(t *testing.T) {
+	sd := testService(t)
+	annotated := sd.Methods().ByName("Annotated")
+	policy := &iauth.Policy{}
+
+	if err := callWithRoles(t, annotated, policy, []string{"admin"}); err != nil {
+		t.Fatalf("admin should be allowed: %v", err)
+	}
+	err := callWithRoles(t, annotated, policy, []string{"user"})
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("want PermissionDenied, got %v", err)
+	}
+	if err := callWithRoles(t, sd.Methods().ByName("Plain"), policy, nil); err != nil {
+		t.Fatalf("unannotated method without config rule should be allowed: %v", err)
+	}
+}
+
+func TestAuthz_ConfigFallback(t *testing.T) {
+	sd := testService(t)
+	plain := sd.Methods().ByName("Plain")
+	policy := &iauth.Policy{
+		Procedures: map[string]iauth.Rule{
+			"/test.v1.TestService/Plain": {Permissions: []string{"test.write"}},
+		},
+		RolePermissions: map[string][]string{"editor": {"test.write"}, "admin": {"*"}},
+	}
+
+	for _, roles := range [][]string{{"editor"}, {"admin"}} {
+		if err := callWithRoles(t, plain, policy, roles); err != nil {
+			t.Fatalf("roles %v should be allowed: %v", roles, err)
+		}
+	}
+	err := callWithRoles(t, plain, policy, []string{"user"})
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("want PermissionDenied, got %v", err)
+	}
+	var cerr *connect.Error
+	if !errors.As(err, &cerr) || !strings.Contains(cerr.Message(), "test.write") {
+		t.Fatalf("error should name the missing permission: %v", err)
+	}
+}
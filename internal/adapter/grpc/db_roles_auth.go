@@ -0,0 +1,33 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// WithDatabaseRoles combines the roles granted in user_roles with the roles
+// of the credential, as selected by mode (auth.DBRolesMerge or
+// auth.DBRolesReplace; see auth.DBRolesModeFromEnv). It applies to human
+// principals with an internal user ID; machine principals and the dev bypass
+// (whose roles come from X-Dev-Roles) are left as is.
+func WithDatabaseRoles(uc *usecase.RoleUsecase, mode string) AuthOption {
+	return func(c *authConfig) { c.roles, c.rolesMode = uc, mode }
+}
+
+// applyDatabaseRoles runs after an authenticator resolved the principal.
+func applyDatabaseRoles(ctx context.Context, cfg *authConfig, p *auth.Principal) error {
+	if cfg.roles == nil || p.Machine || p.UserID <= 0 || p.Provider == "dev" {
+		return nil
+	}
+	if cfg.rolesMode != auth.DBRolesMerge && cfg.rolesMode != auth.DBRolesReplace {
+		return nil
+	}
+	roles, err := cfg.roles.RolesForUser(ctx, p.UserID)
+	if err != nil {
+		return err
+	}
+	p.Roles = auth.CombineRoles(cfg.rolesMode, p.Roles, roles)
+	return nil
+}
@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// memRoles is an in-memory RoleRepository.
+type memRoles map[int64][]string
+
+func (m memRoles) RolesForUser(ctx context.Context, userID int64) ([]string, error) {
+	return m[userID], nil
+}
+
+func (m memRoles) Grant(ctx context.Context, userID int64, role string, at time.Time) error {
+	m[userID] = append(m[userID], role)
+	return nil
+}
+
+func (m memRoles) Revoke(ctx context.Context, userID int64, role string) error {
+	m[userID] = slices.DeleteFunc(m[userID], func(s string) bool { return s == role })
+	return nil
+}
+
+func TestAuth_DatabaseRoles(t *testing.T) {
+	t.Setenv("DEV_AUTH_BYPASS", "")
+	t.Setenv("AUTH_JWKS_URL", "")
+	t.Setenv("AUTH_ISSUERS", "")
+	t.Setenv("AUTH_ISSUERS_FILE", "")
+	t.Setenv("AUTH_HS256_SECRET", "secret")
+	ctx := context.Background()
+	uc := usecase.NewRoleUsecase(memRoles{1: {"editor"}}, time.Minute)
+
+	tests := []struct {
+		name string
+		mode string
+		sub  string
+		want []string
+	}{
+		{"正常系: 未設定ではトークンのロールのみであること", "", "1", []string{"user"}},
+		{"正常系: mergeではDBのロールが追加されること", "merge", "1", []string{"user", "editor"}},
+		{"正常系: replaceではDBのロールに置き換わること", "replace", "1", []string{"editor"}},
+		{"正常系: replaceでDBにロールがなければロールなしになること", "replace", "2", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"sub": tt.sub, "roles": []string{"user"}, "exp": time.Now().Add(5 * time.Minute).Unix(),
+			}).SignedString([]byte("secret"))
+			if err != nil {
+				t.Fatalf("sign err: %v", err)
+			}
+			req := connect.NewRequest(&pingReq{})
+			req.Header().Set("Authorization", "Bearer "+s)
+			var got []string
+			next := func(ctx context.Context, r connect.AnyRequest) (connect.AnyResponse, error) {
+				p, _ := auth.FromContext(ctx)
+				got = p.Roles
+				return nil, nil
+			}
+			if _, err := AuthUnaryInterceptor(nil, WithDatabaseRoles(uc, tt.mode))(next)(ctx, req); err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("roles = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	authpb "github.com/xiao1203/go-onion-grpc-template/gen/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// ProviderImpersonation is the Principal.Provider of impersonated calls.
+const ProviderImpersonation = "impersonation"
+
+// maxImpersonationReason is the length of impersonation_logs.reason
+// (VARCHAR(255)) in characters.
+const maxImpersonationReason = 255
+
+// WithImpersonation lets holders of the impersonation role (AUTH_IMPERSONATION_ROLE,
+// default admin) send "X-Impersonate-User: <users.id>" to call as that user.
+// The handler sees the target user with Principal.Actor set to the admin.
+// Every attempt is written to impersonation_logs; procedures marked
+// (auth.sensitive) are refused.
+func WithImpersonation(uc *usecase.ImpersonationUsecase) AuthOption {
+	return func(c *authConfig) { c.impersonation = uc }
+}
+
+// impersonate returns the principal the call runs as: the target user when
+// the request asks for impersonation, otherwise actor itself.
+func impersonate(ctx context.Context, cfg *authConfig, req connect.AnyRequest, actor *auth.Principal) (*auth.Principal, error) {
+	raw := strings.TrimSpace(req.Header().Get("X-Impersonate-User"))
+	if raw == "" {
+		return actor, nil
+	}
+	if cfg.impersonation == nil {
+		return nil, ergo.WithCode(ergo.New("impersonation is not enabled", apperr.Reason(apperr.ReasonImpersonationDenied)), apperr.PermissionDenied)
+	}
+	if actor.Machine || actor.Actor != nil {
+		return nil, ergo.WithCode(ergo.New("only users can impersonate", apperr.Reason(apperr.ReasonImpersonationDenied)), apperr.PermissionDenied)
+	}
+	targetID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, ergo.WithCode(ergo.Wrap(err, "invalid X-Impersonate-User", apperr.FieldViolation("X-Impersonate-User", "must be a user ID")), apperr.InvalidArgument)
+	}
+	reason := strings.TrimSpace(req.Header().Get("X-Impersonate-Reason"))
+	if !utf8.ValidString(reason) || utf8.RuneCountInString(reason) > maxImpersonationReason {
+		msg := fmt.Sprintf("must be valid UTF-8 of at most %d characters", maxImpersonationReason)
+		return nil, ergo.WithCode(ergo.New("invalid X-Impersonate-Reason", apperr.FieldViolation("X-Impersonate-Reason", msg)), apperr.InvalidArgument)
+	}
+	sensitive, err := isSensitive(req.Spec())
+	if err != nil {
+		return nil, err
+	}
+	target, err := cfg.impersonation.Begin(ctx, usecase.ImpersonationRequest{
+		ActorUserID:  actor.UserID,
+		ActorSubject: actor.Subject,
+		ActorRoles:   actor.Roles,
+		TargetUserID: targetID,
+		Procedure:    req.Spec().Procedure,
+		Reason:       reason,
+		Sensitive:    sensitive,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &auth.Principal{
+		UserID:   target.ID,
+		Email:    target.Email,
+		Roles:    target.Roles,
+		Subject:  strconv.FormatInt(target.ID, 10),
+		TenantID: actor.TenantID,
+		Provider: ProviderImpersonation,
+		Actor:    actor,
+	}, nil
+}
+
+// isSensitive reports whether the procedure is marked (auth.sensitive) or
+// listed in the "sensitive" section of AUTHZ_POLICY(_FILE).
+func isSensitive(spec connect.Spec) (bool, error) {
+	if md, ok := spec.Schema.(protoreflect.MethodDescriptor); ok {
+		if v, _ := proto.GetExtension(md.Options(), authpb.E_Sensitive).(bool); v {
+			return true, nil
+		}
+	}
+	pol, err := auth.PolicyFromEnv()
+	if err != nil {
+		return false, ergo.WithCode(ergo.Wrap(err, "load authz policy"), apperr.Internal)
+	}
+	return pol.IsSensitive(spec.Procedure), nil
+}
@@ -0,0 +1,220 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/newmo-oss/ergo"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	authpb "github.com/xiao1203/go-onion-grpc-template/gen/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// memUsers is an in-memory UserRepository.
+type memUsers map[int64]*entity.User
+
+func (m memUsers) FindByID(ctx context.Context, id int64) (*entity.User, error) {
+	if u, ok := m[id]; ok {
+		return u, nil
+	}
+	return nil, ergo.WithCode(ergo.New("user not found"), apperr.NotFound)
+}
+
+func (m memUsers) UpdateProfile(ctx context.Context, id int64, displayName, pictureURL string) (*entity.User, error) {
+	return m.FindByID(ctx, id)
+}
+
+// memImpersonationLogs is an in-memory ImpersonationLogRepository.
+type memImpersonationLogs struct{ logs []*entity.ImpersonationLog }
+
+func (m *memImpersonationLogs) Create(ctx context.Context, l *entity.ImpersonationLog) error {
+	m.logs = append(m.logs, l)
+	return nil
+}
+
+// impersonationClients serves "imp.v1.ImpService" with GetMe and
+// ChangePassword ((auth.sensitive) = true) and reports the principal seen by
+// the handler through got.
+func impersonationClients(t *testing.T, uc *usecase.ImpersonationUsecase, got **auth.Principal) map[string]*connect.Client[emptypb.Empty, emptypb.Empty] {
+	t.Helper()
+	sensitive := &descriptorpb.MethodOptions{}
+	proto.SetExtension(sensitive, authpb.E_Sensitive, true)
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("imp/v1/imp.proto"),
+		Package:    proto.String("imp.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/empty.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("ImpService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("GetMe"), InputType: proto.String(".google.protobuf.Empty"), OutputType: proto.String(".google.protobuf.Empty")},
+				{Name: proto.String("ChangePassword"), InputType: proto.String(".google.protobuf.Empty"), OutputType: proto.String(".google.protobuf.Empty"), Options: sensitive},
+			},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("build descriptor: %v", err)
+	}
+	methods := fd.Services().Get(0).Methods()
+	mux := http.NewServeMux()
+	var procedures []string
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		procedure := procedureOf(md)
+		procedures = append(procedures, procedure)
+		mux.Handle(procedure, connect.NewUnaryHandler(procedure,
+			func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+				*got, _ = auth.FromContext(ctx)
+				return connect.NewResponse(&emptypb.Empty{}), nil
+			},
+			connect.WithSchema(md),
+			connect.WithInterceptors(AuthUnaryInterceptor(nil, WithImpersonation(uc))),
+		))
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	clients := map[string]*connect.Client[emptypb.Empty, emptypb.Empty]{}
+	for _, p := range procedures {
+		clients[p] = connect.NewClient[emptypb.Empty, emptypb.Empty](srv.Client(), srv.URL+p)
+	}
+	return clients
+}
+
+func TestAuth_Impersonation(t *testing.T) {
+	t.Setenv("DEV_AUTH_BYPASS", "")
+	t.Setenv("AUTH_JWKS_URL", "")
+	t.Setenv("AUTH_ISSUERS", "")
+	t.Setenv("AUTH_ISSUERS_FILE", "")
+	t.Setenv("AUTH_HS256_SECRET", "secret")
+	t.Setenv("AUTHZ_POLICY", "")
+	t.Setenv("AUTHZ_POLICY_FILE", "")
+
+	logs := &memImpersonationLogs{}
+	users := memUsers{2: {ID: 2, Email: "customer@example.com", Roles: []string{"user"}}}
+	var got *auth.Principal
+	clients := impersonationClients(t, usecase.NewImpersonationUsecase(users, logs, ""), &got)
+
+	reason := "ticket-123"
+	call := func(procedure string, roles []string, target string) error {
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": "1", "roles": roles, "exp": time.Now().Add(5 * time.Minute).Unix(),
+		}).SignedString([]byte("secret"))
+		if err != nil {
+			return err
+		}
+		req := connect.NewRequest(&emptypb.Empty{})
+		req.Header().Set("Authorization", "Bearer "+s)
+		if target != "" {
+			req.Header().Set("X-Impersonate-User", target)
+			req.Header().Set("X-Impersonate-Reason", reason)
+		}
+		_, err = clients[procedure].CallUnary(context.Background(), req)
+		return err
+	}
+
+	t.Run("正常系: ヘッダーが無ければ本人として呼び出されること", func(t *testing.T) {
+		got = nil
+		if err := call("/imp.v1.ImpService/GetMe", []string{"admin"}, ""); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if got.UserID != 1 || got.Actor != nil {
+			t.Fatalf("principal = %+v", got)
+		}
+	})
+
+	t.Run("正常系: 管理者は対象ユーザーとして呼び出され、Actorに本人が入ること", func(t *testing.T) {
+		got, logs.logs = nil, nil
+		if err := call("/imp.v1.ImpService/GetMe", []string{"admin"}, "2"); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if got.UserID != 2 || got.Email != "customer@example.com" || got.Provider != ProviderImpersonation {
+			t.Fatalf("principal = %+v", got)
+		}
+		if got.Actor == nil || got.Actor.UserID != 1 {
+			t.Fatalf("actor = %+v", got.Actor)
+		}
+		if len(logs.logs) != 1 || !logs.logs[0].Allowed || logs.logs[0].Reason != "ticket-123" {
+			t.Fatalf("logs = %+v", logs.logs)
+		}
+	})
+
+	tests := []struct {
+		name      string
+		procedure string
+		roles     []string
+		target    string
+		want      connect.Code
+		logged    bool
+	}{
+		{"異常系: 管理者でなければPermissionDeniedになること", "/imp.v1.ImpService/GetMe", []string{"user"}, "2", connect.CodePermissionDenied, true},
+		{"異常系: sensitiveなメソッドではPermissionDeniedになること", "/imp.v1.ImpService/ChangePassword", []string{"admin"}, "2", connect.CodePermissionDenied, true},
+		{"異常系: 存在しないユーザーはNotFoundになること", "/imp.v1.ImpService/GetMe", []string{"admin"}, "99", connect.CodeNotFound, true},
+		{"異常系: ユーザーIDが数値でなければInvalidArgumentになること", "/imp.v1.ImpService/GetMe", []string{"admin"}, "abc", connect.CodeInvalidArgument, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, logs.logs = nil, nil
+			err := call(tt.procedure, tt.roles, tt.target)
+			if connect.CodeOf(err) != tt.want {
+				t.Fatalf("code = %v, want %v (err: %v)", connect.CodeOf(err), tt.want, err)
+			}
+			if got != nil {
+				t.Fatalf("handler must not run: %+v", got)
+			}
+			if (len(logs.logs) == 1) != tt.logged {
+				t.Fatalf("logs = %+v", logs.logs)
+			}
+		})
+	}
+
+	t.Run("異常系: 255文字を超える理由はInvalidArgumentになり記録されないこと", func(t *testing.T) {
+		t.Cleanup(func() { reason = "ticket-123" })
+		got, logs.logs = nil, nil
+		reason = strings.Repeat("理", 255)
+		if err := call("/imp.v1.ImpService/GetMe", []string{"admin"}, "2"); err != nil {
+			t.Fatalf("255 characters: err: %v", err)
+		}
+		if len(logs.logs) != 1 || logs.logs[0].Reason != reason {
+			t.Fatalf("logs = %+v", logs.logs)
+		}
+		got, logs.logs = nil, nil
+		reason = strings.Repeat("理", 256)
+		if err := call("/imp.v1.ImpService/GetMe", []string{"admin"}, "2"); connect.CodeOf(err) != connect.CodeInvalidArgument {
+			t.Fatalf("want InvalidArgument, got %v", err)
+		}
+		if got != nil || len(logs.logs) != 0 {
+			t.Fatalf("principal = %+v, logs = %+v", got, logs.logs)
+		}
+	})
+
+	t.Run("異常系: WithImpersonationが無い場合はPermissionDeniedになること", func(t *testing.T) {
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": "1", "roles": []string{"admin"}, "exp": time.Now().Add(5 * time.Minute).Unix(),
+		}).SignedString([]byte("secret"))
+		if err != nil {
+			t.Fatalf("sign err: %v", err)
+		}
+		req := connect.NewRequest(&pingReq{})
+		req.Header().Set("Authorization", "Bearer "+s)
+		req.Header().Set("X-Impersonate-User", "2")
+		next := func(ctx context.Context, r connect.AnyRequest) (connect.AnyResponse, error) { return nil, nil }
+		_, err = AuthUnaryInterceptor(nil)(next)(context.Background(), req)
+		if connect.CodeOf(err) != connect.CodePermissionDenied {
+			t.Fatalf("want PermissionDenied, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	localauthv1 "github.com/xiao1203/go-onion-grpc-template/gen/localauth/v1"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// LocalAuthHandler implements LocalAuthService. A password change or reset
+// also revokes the user's tokens and cookie sessions when revocations /
+// sessions are set.
+type LocalAuthHandler struct {
+	local       *usecase.LocalAuthUsecase
+	revocations *usecase.TokenRevocationUsecase
+	sessions    *usecase.SessionUsecase
+}
+
+func NewLocalAuthHandler(local *usecase.LocalAuthUsecase, revocations *usecase.TokenRevocationUsecase, sessions *usecase.SessionUsecase) *LocalAuthHandler {
+	return &LocalAuthHandler{local: local, revocations: revocations, sessions: sessions}
+}
+
+func (h *LocalAuthHandler) SignUp(ctx context.Context, req *connect.Request[localauthv1.SignUpRequest]) (*connect.Response[localauthv1.SignUpResponse], error) {
+	u, err := h.local.SignUp(ctx, req.Msg.GetEmail(), req.Msg.GetPassword(), req.Msg.GetDisplayName())
+	if err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	return connect.NewResponse(&localauthv1.SignUpResponse{UserId: uint64(u.ID)}), nil
+}
+
+func (h *LocalAuthHandler) Login(ctx context.Context, req *connect.Request[localauthv1.LoginRequest]) (*connect.Response[localauthv1.LoginResponse], error) {
+	t, err := h.local.Login(ctx, req.Msg.GetEmail(), req.Msg.GetPassword())
+	if err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	return connect.NewResponse(&localauthv1.LoginResponse{
+		AccessToken: t.Token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(t.ExpiresAt).Seconds()),
+	}), nil
+}
+
+func (h *LocalAuthHandler) ChangePassword(ctx context.Context, req *connect.Request[localauthv1.ChangePasswordRequest]) (*connect.Response[localauthv1.ChangePasswordResponse], error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.UserID == 0 {
+		return nil, apperr.ToConnect(ergo.WithCode(ergo.New("unauthenticated"), apperr.Unauthenticated))
+	}
+	if err := h.local.ChangePassword(ctx, p.UserID, req.Msg.GetCurrentPassword(), req.Msg.GetNewPassword()); err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	if err := h.revokeAll(ctx, p.UserID); err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	return connect.NewResponse(&localauthv1.ChangePasswordResponse{}), nil
+}
+
+func (h *LocalAuthHandler) RequestPasswordReset(ctx context.Context, req *connect.Request[localauthv1.RequestPasswordResetRequest]) (*connect.Response[localauthv1.RequestPasswordResetResponse], error) {
+	if err := h.local.RequestPasswordReset(ctx, req.Msg.GetEmail()); err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	return connect.NewResponse(&localauthv1.RequestPasswordResetResponse{}), nil
+}
+
+func (h *LocalAuthHandler) ResetPassword(ctx context.Context, req *connect.Request[localauthv1.ResetPasswordRequest]) (*connect.Response[localauthv1.ResetPasswordResponse], error) {
+	userID, err := h.local.ResetPassword(ctx, req.Msg.GetToken(), req.Msg.GetNewPassword())
+	if err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	if err := h.revokeAll(ctx, userID); err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	return connect.NewResponse(&localauthv1.ResetPasswordResponse{}), nil
+}
+
+// revokeAll revokes every token and cookie session of the user, including the
+// caller's own, so that the caller has to sign in with the new password.
+func (h *LocalAuthHandler) revokeAll(ctx context.Context, userID int64) error {
+	if h.revocations != nil {
+		if err := h.revocations.RevokeAllForUser(ctx, userID); err != nil {
+			return err
+		}
+	}
+	if h.sessions != nil {
+		if err := h.sessions.RevokeAll(ctx, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hs256TokenIssuer mints the access tokens of local logins. Their sub is the
+// internal user ID, so the HS256 path resolves them without provisioning.
+type hs256TokenIssuer struct {
+	signer *auth.HS256Signer
+	claims auth.ClaimMapping
+	ttl    time.Duration
+}
+
+func (i hs256TokenIssuer) IssueAccessToken(ctx context.Context, user *entity.User, amr []string, authTime time.Time) (*usecase.AccessToken, error) {
+	exp := authTime.Add(i.ttl)
+	c := i.claims.Claims(strconv.FormatInt(user.ID, 10), user.Email, user.Roles)
+	c["iat"] = authTime.Unix()
+	c["exp"] = exp.Unix()
+	c["jti"] = rand.Text()
+	c["auth_time"] = authTime.Unix()
+	c["amr"] = amr
+	token, err := i.signer.Sign(c)
+	if err != nil {
+		return nil, ergo.WithCode(err, apperr.Internal)
+	}
+	return &usecase.AccessToken{Token: token, ExpiresAt: exp}, nil
+}
+
+// logPasswordResetNotifier is the default PasswordResetNotifier. It logs the
+// token only with APP_ENV=dev; elsewhere set Deps.PasswordResetNotifier to
+// deliver it (e.g. by email).
+type logPasswordResetNotifier struct{}
+
+func (logPasswordResetNotifier) NotifyPasswordReset(ctx context.Context, user *entity.User, token string, expiresAt time.Time) error {
+	if os.Getenv("APP_ENV") == "dev" {
+		slog.InfoContext(ctx, "password reset token (dev only)", slog.Int64("user_id", user.ID), slog.String("token", token), slog.Time("expires_at", expiresAt))
+		return nil
+	}
+	slog.ErrorContext(ctx, "password reset requested but no PasswordResetNotifier is configured", slog.Int64("user_id", user.ID))
+	return nil
+}
@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	localauthv1connect "github.com/xiao1203/go-onion-grpc-template/gen/localauth/v1/localauthv1connect"
+	mysqlrepo "github.com/xiao1203/go-onion-grpc-template/internal/adapter/repository/mysql"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+func init() { Add(registerLocalAuth) }
+
+const defaultLocalTokenTTL = time.Hour
+
+// registerLocalAuth mounts LocalAuthService when AUTH_LOCAL=1. Tokens are
+// signed with the HS256 secret / active key, which must be configured.
+func registerLocalAuth(mux *http.ServeMux, deps Deps) {
+	if os.Getenv("AUTH_LOCAL") != "1" || deps.Gorm == nil {
+		return
+	}
+	hs, err := auth.LoadHS256ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("local auth: %v", err)
+	}
+	if hs == nil {
+		log.Fatalf("local auth: AUTH_LOCAL=1 needs AUTH_HS256_SECRET or AUTH_HS256_KEYS to sign tokens")
+	}
+	signer, err := auth.NewHS256Signer(*hs)
+	if err != nil {
+		log.Fatalf("local auth: %v", err)
+	}
+	claims, err := auth.LoadClaimMappingFromEnv()
+	if err != nil {
+		log.Fatalf("local auth claim mapping: %v", err)
+	}
+	ttl := durationFromEnv("AUTH_LOCAL_TOKEN_TTL")
+	if ttl <= 0 {
+		ttl = defaultLocalTokenTTL
+	}
+	maxFailures, _ := strconv.Atoi(os.Getenv("AUTH_LOCAL_MAX_FAILURES"))
+	minLength, _ := strconv.Atoi(os.Getenv("AUTH_LOCAL_MIN_PASSWORD_LENGTH"))
+	notifier := deps.PasswordResetNotifier
+	if notifier == nil {
+		notifier = logPasswordResetNotifier{}
+	}
+	uc := usecase.NewLocalAuthUsecase(
+		mysqlrepo.NewCredentialRepository(deps.Gorm),
+		mysqlrepo.NewUserRepository(deps.Gorm),
+		hs256TokenIssuer{signer: signer, claims: claims, ttl: ttl},
+		notifier,
+		usecase.LocalAuthConfig{
+			MaxFailures:       maxFailures,
+			Lockout:           durationFromEnv("AUTH_LOCAL_LOCKOUT"),
+			ResetTokenTTL:     durationFromEnv("AUTH_LOCAL_RESET_TOKEN_TTL"),
+			MinPasswordLength: minLength,
+		},
+	)
+	a := deps.authDeps()
+	h := NewLocalAuthHandler(uc, a.revocations, a.sessions)
+	path, handler := localauthv1connect.NewLocalAuthServiceHandler(h, deps.AuthInterceptors())
+	mux.Handle(path, handler)
+}
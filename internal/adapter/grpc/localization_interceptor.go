@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+)
+
+// LocalizationUnaryInterceptor attaches a google.rpc.LocalizedMessage, chosen
+// from the Accept-Language request header, to every error. The message comes
+// from the catalog entry of the ErrorInfo reason; the code and ErrorInfo are
+// left as they are. A nil catalog uses apperr.DefaultCatalog.
+func LocalizationUnaryInterceptor(c *apperr.Catalog) connect.UnaryInterceptorFunc {
+	if c == nil {
+		c = apperr.DefaultCatalog()
+	}
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			res, err := next(ctx, req)
+			if err != nil {
+				return nil, c.Localize(apperr.ToConnect(err), req.Header().Get("Accept-Language"))
+			}
+			return res, nil
+		}
+	})
+}
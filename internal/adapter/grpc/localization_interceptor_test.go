@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	samplev1 "github.com/xiao1203/go-onion-grpc-template/gen/sample/v1"
+	samplev1connect "github.com/xiao1203/go-onion-grpc-template/gen/sample/v1/samplev1connect"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+)
+
+func TestLocalizationInterceptor(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(samplev1connect.NewSampleServiceHandler(
+		failingSampleHandler{err: ergo.WithCode(ergo.New("sample not found"), apperr.NotFound)},
+		connect.WithInterceptors(LocalizationUnaryInterceptor(nil))))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	client := samplev1connect.NewSampleServiceClient(srv.Client(), srv.URL)
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		wantLocale     string
+		wantMessage    string
+	}{
+		{"正常系: Accept-Languageがjaなら日本語のメッセージが付くこと", "ja-JP,ja;q=0.9", "ja", "指定されたデータが見つかりません。"},
+		{"正常系: Accept-Languageがenなら英語のメッセージが付くこと", "en-US", "en", "The requested item was not found."},
+		{"正常系: Accept-Languageが無い場合は英語になること", "", "en", "The requested item was not found."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := connect.NewRequest(&samplev1.GetSampleRequest{Id: 1})
+			if tt.acceptLanguage != "" {
+				req.Header().Set("Accept-Language", tt.acceptLanguage)
+			}
+			_, err := client.GetSample(context.Background(), req)
+			var ce *connect.Error
+			if !errors.As(err, &ce) || ce.Code() != connect.CodeNotFound {
+				t.Fatalf("error = %v, want NotFound", err)
+			}
+			var lm *errdetails.LocalizedMessage
+			for _, d := range ce.Details() {
+				if v, derr := d.Value(); derr == nil {
+					if m, ok := v.(*errdetails.LocalizedMessage); ok {
+						lm = m
+					}
+				}
+			}
+			if lm.GetLocale() != tt.wantLocale || lm.GetMessage() != tt.wantMessage {
+				t.Fatalf("LocalizedMessage = %v, want %s %q", lm, tt.wantLocale, tt.wantMessage)
+			}
+		})
+	}
+}
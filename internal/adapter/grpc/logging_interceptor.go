@@ -7,9 +7,14 @@ import (
 
     "connectrpc.com/connect"
     "github.com/newmo-oss/ergo"
+
+    "github.com/xiao1203/go-onion-grpc-template/internal/apperr"
 )
 
-// LoggingUnaryInterceptor logs errors with ergo stacktrace when available.
+// LoggingUnaryInterceptor converts errors with apperr.ToConnect, so that raw
+// errors returned by handlers never reach clients, and logs them.
+// Internal errors are logged with the full ergo chain and stacktrace under the
+// error_id sent to the client; other errors at info level.
 func LoggingUnaryInterceptor(logger *slog.Logger) connect.UnaryInterceptorFunc {
     if logger == nil {
         logger = slog.Default()
@@ -17,17 +22,27 @@ func LoggingUnaryInterceptor(logger *slog.Logger) connect.UnaryInterceptorFunc {
     return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
         return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
             res, err := next(ctx, req)
-            if err != nil {
-                st := ergo.StackTraceOf(err)
-                logger.Error("rpc error",
+            if err == nil {
+                return res, nil
+            }
+            err = apperr.ToConnect(err)
+            cause := apperr.CauseOf(err)
+            if id := apperr.ErrorIDOf(err); id != "" {
+                logger.ErrorContext(ctx, "rpc error",
                     slog.String("procedure", req.Spec().Procedure),
                     slog.String("code", connect.CodeOf(err).String()),
-                    slog.String("error", fmt.Sprintf("%+v", err)),
-                    slog.String("stack", fmt.Sprintf("%v", st)),
+                    slog.String("error_id", id),
+                    slog.String("error", fmt.Sprintf("%+v", cause)),
+                    slog.String("stack", fmt.Sprintf("%v", ergo.StackTraceOf(cause))),
                 )
+                return nil, err
             }
-            return res, err
+            logger.InfoContext(ctx, "rpc error",
+                slog.String("procedure", req.Spec().Procedure),
+                slog.String("code", connect.CodeOf(err).String()),
+                slog.String("error", cause.Error()),
+            )
+            return nil, err
         }
     })
 }
-
@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+
+	samplev1 "github.com/xiao1203/go-onion-grpc-template/gen/sample/v1"
+	samplev1connect "github.com/xiao1203/go-onion-grpc-template/gen/sample/v1/samplev1connect"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+)
+
+type failingSampleHandler struct {
+	samplev1connect.UnimplementedSampleServiceHandler
+	err error
+}
+
+func (h failingSampleHandler) GetSample(context.Context, *connect.Request[samplev1.GetSampleRequest]) (*connect.Response[samplev1.GetSampleResponse], error) {
+	return nil, h.err
+}
+
+func TestLoggingInterceptor_Redaction(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode connect.Code
+		wantMsg  string
+		wantLog  string
+	}{
+		{"異常系: ハンドラの生エラーは汎用メッセージとerror_idで返りログに全文が残ること",
+			ergo.Wrap(ergo.New("Error 1146: Table 'app.samples' doesn't exist"), "gorm First samples"),
+			connect.CodeInternal, "internal error (error_id: ", "gorm First samples: Error 1146"},
+		{"異常系: ToConnect済みの内部エラーも同じerror_idでログに残ること",
+			apperr.ToConnect(ergo.Wrap(errors.New("connection refused"), "gorm Find samples")),
+			connect.CodeInternal, "internal error (error_id: ", "gorm Find samples: connection refused"},
+		{"異常系: ドメインエラーはコードの汎用メッセージになること",
+			apperr.ToConnect(ergo.WithCode(ergo.Wrap(ergo.New("record not found"), "gorm First samples"), apperr.NotFound)),
+			connect.CodeNotFound, "not found", "gorm First samples: record not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			mux := http.NewServeMux()
+			mux.Handle(samplev1connect.NewSampleServiceHandler(failingSampleHandler{err: tt.err},
+				connect.WithInterceptors(LoggingUnaryInterceptor(logger))))
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+			client := samplev1connect.NewSampleServiceClient(srv.Client(), srv.URL)
+
+			_, err := client.GetSample(context.Background(), connect.NewRequest(&samplev1.GetSampleRequest{Id: 1}))
+			var ce *connect.Error
+			if !errors.As(err, &ce) || ce.Code() != tt.wantCode {
+				t.Fatalf("error = %v, want code %v", err, tt.wantCode)
+			}
+			if !strings.HasPrefix(ce.Message(), tt.wantMsg) || strings.Contains(ce.Message(), "gorm") {
+				t.Fatalf("message = %q, want prefix %q", ce.Message(), tt.wantMsg)
+			}
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("log entry: %v (%s)", err, buf.String())
+			}
+			if !strings.Contains(entry["error"].(string), tt.wantLog) {
+				t.Fatalf("logged error = %v, want %q", entry["error"], tt.wantLog)
+			}
+			if tt.wantCode == connect.CodeInternal {
+				id, _ := entry["error_id"].(string)
+				if id == "" || !strings.Contains(ce.Message(), id) {
+					t.Fatalf("logged error_id = %q, message = %q", id, ce.Message())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+)
+
+type peerCertKey struct{}
+
+// WithPeerCertificate exposes the verified TLS client certificate to the auth
+// interceptor. Wrap the mux with it when the server listens with mTLS.
+// Certificates that did not chain to TLS_CLIENT_CA_FILE are never exposed.
+func WithPeerCertificate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+			r = r.WithContext(withPeerCertificate(r.Context(), r.TLS.VerifiedChains[0][0]))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func withPeerCertificate(ctx context.Context, cert *x509.Certificate) context.Context {
+	return context.WithValue(ctx, peerCertKey{}, cert)
+}
+
+func peerCertificate(ctx context.Context) *x509.Certificate {
+	cert, _ := ctx.Value(peerCertKey{}).(*x509.Certificate)
+	return cert
+}
+
+// clientCertAuthenticator maps a verified client certificate to a machine
+// Principal via AUTH_MTLS_IDENTITIES. Unmapped certificates fall through.
+type clientCertAuthenticator struct{}
+
+func (clientCertAuthenticator) Authenticate(ctx context.Context, req connect.AnyRequest) (*Authentication, error) {
+	cert := peerCertificate(ctx)
+	if cert == nil {
+		return nil, nil
+	}
+	mapping, err := auth.ClientCertMappingFromEnv()
+	if err != nil {
+		return nil, ergo.WithCode(ergo.Wrap(err, "load client certificate identities"), apperr.Internal)
+	}
+	if p, ok := mapping.Principal(cert); ok {
+		return &Authentication{Principal: p}, nil
+	}
+	return nil, nil
+}
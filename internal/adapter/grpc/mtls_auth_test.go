@@ -0,0 +1,127 @@
+package grpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	iauth "github.com/xiao1203/go-onion-grpc-template/internal/auth"
+)
+
+// newTestCert issues a certificate signed by parent (self-signed when parent is nil).
+func newTestCert(t *testing.T, cn string, parent *tls.Certificate) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := tmpl, any(key)
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	leaf, _ := x509.ParseCertificate(der)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestAuth_ClientCertificate(t *testing.T) {
+	t.Setenv("DEV_AUTH_BYPASS", "")
+	t.Setenv("AUTH_HS256_SECRET", "")
+	t.Setenv("AUTH_JWKS_URL", "")
+	t.Setenv("AUTH_ISSUERS", "")
+	t.Setenv("AUTH_ISSUERS_FILE", "")
+	t.Setenv("AUTH_MTLS_IDENTITIES_FILE", "")
+	t.Setenv("AUTH_MTLS_IDENTITIES", `[{"name":"batch","common_name":"batch.internal","roles":["admin"]}]`)
+
+	ca := newTestCert(t, "test-ca", nil)
+	rogueCA := newTestCert(t, "rogue-ca", nil)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	// The handler runs the auth interceptor with the request context, as connect does.
+	var got *iauth.Principal
+	h := WithPeerCertificate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		next := func(ctx context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
+			got, _ = iauth.FromContext(ctx)
+			return nil, nil
+		}
+		if _, err := AuthUnaryInterceptor(nil)(next)(r.Context(), connect.NewRequest(&pingReq{})); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		}
+	}))
+	srv := httptest.NewUnstartedServer(h)
+	srv.TLS = &tls.Config{ClientCAs: pool, ClientAuth: tls.VerifyClientCertIfGiven}
+	srv.StartTLS()
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		cert        *tls.Certificate
+		wantSubject string
+	}{
+		{name: "正常系: 対応表にある証明書で機械プリンシパルになること", cert: ptr(newTestCert(t, "batch.internal", &ca)), wantSubject: "batch"},
+		{name: "異常系: 対応表に無い証明書はUnauthenticatedになること", cert: ptr(newTestCert(t, "other.internal", &ca))},
+		{name: "異常系: 証明書が無ければUnauthenticatedになること"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := srv.Client().Transport.(*http.Transport).Clone()
+			if tt.cert != nil {
+				tr.TLSClientConfig.Certificates = []tls.Certificate{*tt.cert}
+			}
+			resp, err := (&http.Client{Transport: tr}).Get(srv.URL)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+			if tt.wantSubject == "" {
+				if resp.StatusCode != http.StatusUnauthorized {
+					t.Fatalf("status = %d, want 401", resp.StatusCode)
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusOK || got == nil || got.Subject != tt.wantSubject || !got.Machine {
+				t.Fatalf("status = %d, principal = %+v", resp.StatusCode, got)
+			}
+		})
+	}
+
+	t.Run("異常系: 信頼していないCAの証明書はTLSハンドシェイクで拒否されること", func(t *testing.T) {
+		tr := srv.Client().Transport.(*http.Transport).Clone()
+		rogue := newTestCert(t, "batch.internal", &rogueCA)
+		// Force sending the certificate even though the server does not advertise its CA.
+		tr.TLSClientConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) { return &rogue, nil }
+		if resp, err := (&http.Client{Transport: tr}).Get(srv.URL); err == nil {
+			_ = resp.Body.Close()
+			t.Fatal("want handshake error")
+		}
+	})
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,229 @@
+package grpc
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+const (
+	loginCookieName = "oidc_login"
+	loginCookiePath = "/auth/callback"
+	loginTimeout    = 10 * time.Minute
+)
+
+// loginState is kept in a short-lived HttpOnly cookie between /auth/login and /auth/callback.
+type loginState struct {
+	State    string `json:"s"`
+	Nonce    string `json:"n"`
+	Verifier string `json:"v"`
+	ReturnTo string `json:"r,omitempty"`
+}
+
+// OIDCHandler serves the BFF login endpoints:
+//
+//   - GET  /auth/login    redirects to the IdP (authorization code + PKCE)
+//   - GET  /auth/callback verifies the ID token, provisions the user and starts a session
+//   - POST /auth/logout   revokes the session and clears the cookies
+type OIDCHandler struct {
+	client     *auth.OIDCClient
+	identities *usecase.IdentityUsecase
+	sessions   *usecase.SessionUsecase
+	cookies    *auth.SessionConfig
+}
+
+func NewOIDCHandler(client *auth.OIDCClient, identities *usecase.IdentityUsecase, sessions *usecase.SessionUsecase, cookies *auth.SessionConfig) *OIDCHandler {
+	return &OIDCHandler{client: client, identities: identities, sessions: sessions, cookies: cookies}
+}
+
+// Register mounts the endpoints on the mux.
+func (h *OIDCHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /auth/login", h.Login)
+	mux.HandleFunc("GET /auth/callback", h.Callback)
+	mux.HandleFunc("POST /auth/logout", h.Logout)
+}
+
+// Login starts the flow. ?return_to=/path picks the page to land on afterwards.
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	st := loginState{ReturnTo: safeReturnTo(r.URL.Query().Get("return_to"))}
+	for _, dst := range []*string{&st.State, &st.Nonce, &st.Verifier} {
+		v, err := auth.RandomString(32)
+		if err != nil {
+			writeHTTPError(w, r, ergo.WithCode(err, apperr.Internal))
+			return
+		}
+		*dst = v
+	}
+	authURL, err := h.client.AuthCodeURL(r.Context(), st.State, st.Nonce, st.Verifier)
+	if err != nil {
+		writeHTTPError(w, r, ergo.WithCode(err, apperr.Internal))
+		return
+	}
+	b, _ := json.Marshal(st)
+	http.SetCookie(w, &http.Cookie{
+		Name:     loginCookieName,
+		Value:    base64.RawURLEncoding.EncodeToString(b),
+		Path:     loginCookiePath,
+		MaxAge:   int(loginTimeout.Seconds()),
+		Secure:   h.cookies.Secure,
+		HttpOnly: true,
+		// Lax: the cookie must survive the top-level redirect back from the IdP.
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback completes the flow and redirects with session cookies set.
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	// The login cookie is single-use.
+	http.SetCookie(w, &http.Cookie{Name: loginCookieName, Path: loginCookiePath, MaxAge: -1, Secure: h.cookies.Secure, HttpOnly: true})
+
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		writeHTTPError(w, r, ergo.WithCode(ergo.New("login rejected by identity provider", slog.String("error", e)), apperr.Unauthenticated))
+		return
+	}
+	st, err := readLoginState(r)
+	if err != nil {
+		writeHTTPError(w, r, ergo.WithCode(err, apperr.InvalidArgument))
+		return
+	}
+	if q.Get("state") == "" || subtle.ConstantTimeCompare([]byte(q.Get("state")), []byte(st.State)) != 1 {
+		writeHTTPError(w, r, ergo.WithCode(ergo.New("state mismatch"), apperr.InvalidArgument))
+		return
+	}
+	if q.Get("code") == "" {
+		writeHTTPError(w, r, ergo.WithCode(ergo.New("missing code"), apperr.InvalidArgument))
+		return
+	}
+	idToken, err := h.client.Exchange(r.Context(), q.Get("code"), st.Verifier)
+	if err != nil {
+		writeHTTPError(w, r, ergo.WithCode(err, apperr.Unauthenticated))
+		return
+	}
+	p, err := h.client.VerifyIDToken(r.Context(), idToken, st.Nonce)
+	if err != nil {
+		writeHTTPError(w, r, ergo.WithCode(err, apperr.Unauthenticated))
+		return
+	}
+	uid, err := h.identities.ResolveUserID(r.Context(), externalIdentity(p))
+	if err != nil {
+		writeHTTPError(w, r, err)
+		return
+	}
+	s, err := h.sessions.Create(r.Context(), uid, r.UserAgent(), clientIP(r))
+	if err != nil {
+		writeHTTPError(w, r, err)
+		return
+	}
+	http.SetCookie(w, h.cookies.SessionCookie(s.ID, s.ExpiresAt))
+	http.SetCookie(w, h.cookies.CSRFCookie(s.CSRFToken, s.ExpiresAt))
+	dest := st.ReturnTo
+	if dest == "" {
+		dest = h.client.Config().PostLoginRedirect
+	}
+	http.Redirect(w, r, dest, http.StatusSeeOther)
+}
+
+// Logout revokes the current session. The CSRF token is required (X-CSRF-Token
+// header or csrf_token form field) so that other sites cannot log users out.
+func (h *OIDCHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if c, err := r.Cookie(h.cookies.CookieName); err == nil && c.Value != "" {
+		s, _, err := h.sessions.Validate(r.Context(), c.Value)
+		if err == nil {
+			token := r.Header.Get(h.cookies.CSRFHeader)
+			if token == "" {
+				token = r.PostFormValue("csrf_token")
+			}
+			if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.CSRFToken)) != 1 {
+				writeHTTPError(w, r, ergo.WithCode(ergo.New("invalid CSRF token"), apperr.PermissionDenied))
+				return
+			}
+			if err := h.sessions.Revoke(r.Context(), s.ID); err != nil {
+				writeHTTPError(w, r, err)
+				return
+			}
+		}
+	}
+	for _, c := range h.cookies.ClearCookies() {
+		http.SetCookie(w, c)
+	}
+	http.Redirect(w, r, h.client.LogoutURL(r.Context()), http.StatusSeeOther)
+}
+
+func readLoginState(r *http.Request) (*loginState, error) {
+	c, err := r.Cookie(loginCookieName)
+	if err != nil {
+		return nil, ergo.New("login session not found or expired")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(c.Value)
+	if err != nil {
+		return nil, ergo.Wrap(err, "decode login cookie")
+	}
+	var st loginState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, ergo.Wrap(err, "parse login cookie")
+	}
+	return &st, nil
+}
+
+// safeReturnTo accepts only same-origin absolute paths (no open redirects).
+// Browsers strip tabs and newlines from URLs and treat a backslash as "/", so
+// "/\t/evil.example" would become the protocol-relative "//evil.example";
+// control characters and backslashes are therefore rejected outright.
+func safeReturnTo(s string) string {
+	if strings.ContainsRune(s, '\\') || strings.ContainsFunc(s, unicode.IsControl) {
+		return ""
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.User != nil || u.Opaque != "" {
+		return ""
+	}
+	if !strings.HasPrefix(s, "/") || strings.HasPrefix(s, "//") || strings.HasPrefix(u.Path, "//") {
+		return ""
+	}
+	return s
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// writeHTTPError writes a plain-text error for the apperr code; details are logged, not returned.
+func writeHTTPError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	switch ergo.CodeOf(err) {
+	case apperr.InvalidArgument, apperr.FailedPrecondition:
+		status = http.StatusBadRequest
+	case apperr.Unauthenticated:
+		status = http.StatusUnauthorized
+	case apperr.PermissionDenied:
+		status = http.StatusForbidden
+	case apperr.NotFound:
+		status = http.StatusNotFound
+	case apperr.Conflict, apperr.Aborted:
+		status = http.StatusConflict
+	case apperr.Unavailable:
+		status = http.StatusServiceUnavailable
+	case apperr.DeadlineExceeded:
+		status = http.StatusGatewayTimeout
+	}
+	slog.WarnContext(r.Context(), "auth endpoint failed", slog.String("path", r.URL.Path), slog.Int("status", status), slog.String("error", err.Error()))
+	http.Error(w, http.StatusText(status), status)
+}
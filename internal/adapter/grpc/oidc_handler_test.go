@@ -0,0 +1,361 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// fakeOIDCProvider is a minimal OpenID provider: discovery, JWKS, token and end-session.
+type fakeOIDCProvider struct {
+	t   *testing.T
+	key *rsa.PrivateKey
+	srv *httptest.Server
+
+	mu    sync.Mutex
+	codes map[string]fakeGrant
+}
+
+type fakeGrant struct {
+	challenge string
+	nonce     string
+	sub       string
+}
+
+func newFakeOIDCProvider(t *testing.T) *fakeOIDCProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa key: %v", err)
+	}
+	p := &fakeOIDCProvider{t: t, key: key, codes: map[string]fakeGrant{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.srv.URL,
+			"authorization_endpoint": p.srv.URL + "/authorize",
+			"token_endpoint":         p.srv.URL + "/token",
+			"jwks_uri":               p.srv.URL + "/jwks",
+			"end_session_endpoint":   p.srv.URL + "/logout",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		g, ok := p.codes[r.PostFormValue("code")]
+		delete(p.codes, r.PostFormValue("code"))
+		p.mu.Unlock()
+		if !ok || auth.PKCEChallenge(r.PostFormValue("code_verifier")) != g.challenge || r.PostFormValue("client_id") != "bff" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		tok := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":   p.srv.URL,
+			"aud":   "bff",
+			"sub":   g.sub,
+			"email": g.sub + "@example.com",
+			"name":  "Test User",
+			"nonce": g.nonce,
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(5 * time.Minute).Unix(),
+		})
+		tok.Header["kid"] = "k1"
+		s, err := tok.SignedString(key)
+		if err != nil {
+			t.Errorf("sign: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": s, "access_token": "at", "token_type": "Bearer"})
+	})
+	p.srv = httptest.NewServer(mux)
+	t.Cleanup(p.srv.Close)
+	return p
+}
+
+// authorize plays the user logging in at the IdP: it issues a code bound to
+// the challenge and nonce from the authorization URL.
+func (p *fakeOIDCProvider) authorize(authURL *url.URL, sub, nonce string) string {
+	code, _ := auth.RandomString(16)
+	if nonce == "" {
+		nonce = authURL.Query().Get("nonce")
+	}
+	p.mu.Lock()
+	p.codes[code] = fakeGrant{challenge: authURL.Query().Get("code_challenge"), nonce: nonce, sub: sub}
+	p.mu.Unlock()
+	return code
+}
+
+// memIdentities is an in-memory UserIdentityRepository.
+type memIdentities struct {
+	mu    sync.Mutex
+	items map[string]*entity.UserIdentity
+}
+
+func (m *memIdentities) FindByIssuerSubject(ctx context.Context, issuer, subject string) (*entity.UserIdentity, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.items[issuer+"|"+subject]; ok {
+		cp := *v
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (m *memIdentities) Provision(ctx context.Context, user *entity.User, identity *entity.UserIdentity) (*entity.UserIdentity, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *identity
+	cp.ID = int64(len(m.items) + 1)
+	cp.UserID = 100 + cp.ID
+	m.items[cp.Issuer+"|"+cp.Subject] = &cp
+	return &cp, nil
+}
+
+func (m *memIdentities) TouchLogin(ctx context.Context, identity *entity.UserIdentity, emailAtProvider string, at time.Time) error {
+	return nil
+}
+
+type oidcTestEnv struct {
+	provider *fakeOIDCProvider
+	sessions memSessions
+	app      *httptest.Server
+	client   *http.Client
+}
+
+func newOIDCTestEnv(t *testing.T) *oidcTestEnv {
+	t.Helper()
+	t.Setenv("SESSION_COOKIE_SECURE", "0")
+	cookies, err := auth.LoadSessionConfigFromEnv()
+	if err != nil {
+		t.Fatalf("session config: %v", err)
+	}
+	env := &oidcTestEnv{provider: newFakeOIDCProvider(t), sessions: memSessions{}}
+	mux := http.NewServeMux()
+	env.app = httptest.NewServer(mux)
+	t.Cleanup(env.app.Close)
+	client := auth.NewOIDCClient(auth.OIDCClientConfig{
+		Provider:           "fake",
+		Issuer:             env.provider.srv.URL,
+		ClientID:           "bff",
+		RedirectURL:        env.app.URL + "/auth/callback",
+		Scopes:             []string{"openid", "email"},
+		PostLoginRedirect:  "/",
+		PostLogoutRedirect: "/",
+	}, auth.ClaimMapping{})
+	NewOIDCHandler(client,
+		usecase.NewIdentityUsecase(&memIdentities{items: map[string]*entity.UserIdentity{}}),
+		usecase.NewSessionUsecase(env.sessions, time.Hour, 0),
+		cookies,
+	).Register(mux)
+	env.client = &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	return env
+}
+
+// login runs /auth/login and returns the IdP authorization URL and login cookie.
+func (e *oidcTestEnv) login(t *testing.T, returnTo string) (*url.URL, *http.Cookie) {
+	t.Helper()
+	res, err := e.client.Get(e.app.URL + "/auth/login?return_to=" + url.QueryEscape(returnTo))
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusFound {
+		t.Fatalf("login status = %d", res.StatusCode)
+	}
+	loc, err := url.Parse(res.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("location: %v", err)
+	}
+	return loc, cookieNamed(res, loginCookieName)
+}
+
+func (e *oidcTestEnv) callback(t *testing.T, code, state string, loginCookie *http.Cookie) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, e.app.URL+"/auth/callback?"+url.Values{"code": {code}, "state": {state}}.Encode(), nil)
+	if loginCookie != nil {
+		req.AddCookie(loginCookie)
+	}
+	res, err := e.client.Do(req)
+	if err != nil {
+		t.Fatalf("callback: %v", err)
+	}
+	_ = res.Body.Close()
+	return res
+}
+
+func cookieNamed(res *http.Response, name string) *http.Cookie {
+	for _, c := range res.Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestOIDCHandler_LoginCallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		returnTo   string
+		tamper     func(authURL *url.URL, code, state *string, loginCookie **http.Cookie)
+		idpNonce   string
+		wantStatus int
+		wantDest   string
+	}{
+		{name: "正常系: ログイン後にセッションが作成されreturn_toへ戻ること", returnTo: "/app", wantStatus: http.StatusSeeOther, wantDest: "/app"},
+		{name: "正常系: 外部へのreturn_toは無視されること", returnTo: "//evil.example", wantStatus: http.StatusSeeOther, wantDest: "/"},
+		{name: "正常系: タブを挟んだ外部へのreturn_toは無視されること", returnTo: "/\t/evil.example", wantStatus: http.StatusSeeOther, wantDest: "/"},
+		{
+			name:       "異常系: stateが一致しない場合は400になること",
+			tamper:     func(_ *url.URL, _, state *string, _ **http.Cookie) { *state = "forged" },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "異常系: ログインCookieが無い場合は400になること",
+			tamper:     func(_ *url.URL, _, _ *string, c **http.Cookie) { *c = nil },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "異常系: 未発行のコードは401になること",
+			tamper:     func(_ *url.URL, code, _ *string, _ **http.Cookie) { *code = "unknown" },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "異常系: IDトークンのnonceが一致しない場合は401になること",
+			idpNonce:   "other-nonce",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newOIDCTestEnv(t)
+			authURL, loginCookie := env.login(t, tt.returnTo)
+			if !strings.HasPrefix(authURL.String(), env.provider.srv.URL+"/authorize?") || authURL.Query().Get("code_challenge_method") != "S256" {
+				t.Fatalf("unexpected authorization URL: %s", authURL)
+			}
+			code := env.provider.authorize(authURL, "user-1", tt.idpNonce)
+			state := authURL.Query().Get("state")
+			if tt.tamper != nil {
+				tt.tamper(authURL, &code, &state, &loginCookie)
+			}
+			res := env.callback(t, code, state, loginCookie)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("callback status = %d, want %d", res.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusSeeOther {
+				if len(env.sessions) != 0 {
+					t.Fatalf("no session must be created on failure")
+				}
+				return
+			}
+			if got := res.Header.Get("Location"); got != tt.wantDest {
+				t.Fatalf("Location = %q, want %q", got, tt.wantDest)
+			}
+			sc := cookieNamed(res, "session")
+			if sc == nil || !sc.HttpOnly {
+				t.Fatalf("session cookie missing or not HttpOnly: %+v", sc)
+			}
+			s, ok := env.sessions.byCookie(sc.Value)
+			if !ok || s.UserID != 101 {
+				t.Fatalf("session = %+v, want user 101", s)
+			}
+			if c := cookieNamed(res, "csrf_token"); c == nil || c.Value != s.CSRFToken {
+				t.Fatalf("csrf cookie = %+v", c)
+			}
+		})
+	}
+}
+
+func TestSafeReturnTo(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"正常系: 同一オリジンのパスはそのまま返ること", "/app/items?id=1#top", "/app/items?id=1#top"},
+		{"異常系: 空文字列は拒否されること", "", ""},
+		{"異常系: 相対パスは拒否されること", "app", ""},
+		{"異常系: 絶対URLは拒否されること", "https://evil.example/", ""},
+		{"異常系: スキームのみのURLは拒否されること", "javascript:alert(1)", ""},
+		{"異常系: プロトコル相対URLは拒否されること", "//evil.example", ""},
+		{"異常系: バックスラッシュを含むパスは拒否されること", "/\\evil.example", ""},
+		{"異常系: タブを挟んだプロトコル相対URLは拒否されること", "/\t/evil.example", ""},
+		{"異常系: 改行を含むパスは拒否されること", "/\n/evil.example", ""},
+		{"異常系: エンコードされたスラッシュで始まるパスは拒否されること", "/%2F/evil.example", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := safeReturnTo(tt.in); got != tt.want {
+				t.Fatalf("safeReturnTo(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOIDCHandler_Logout(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	authURL, loginCookie := env.login(t, "")
+	res := env.callback(t, env.provider.authorize(authURL, "user-1", ""), authURL.Query().Get("state"), loginCookie)
+	sc := cookieNamed(res, "session")
+	if sc == nil {
+		t.Fatal("session cookie missing")
+	}
+	logout := func(csrf string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, env.app.URL+"/auth/logout", nil)
+		req.AddCookie(&http.Cookie{Name: "session", Value: sc.Value})
+		if csrf != "" {
+			req.Header.Set("X-CSRF-Token", csrf)
+		}
+		res, err := env.client.Do(req)
+		if err != nil {
+			t.Fatalf("logout: %v", err)
+		}
+		_ = res.Body.Close()
+		return res
+	}
+
+	t.Run("異常系: CSRFトークンが無いログアウトは403になること", func(t *testing.T) {
+		if res := logout(""); res.StatusCode != http.StatusForbidden {
+			t.Fatalf("status = %d, want 403", res.StatusCode)
+		}
+		if s, _ := env.sessions.byCookie(sc.Value); s.RevokedAt != nil {
+			t.Fatal("session must not be revoked")
+		}
+	})
+
+	t.Run("正常系: セッションを失効させIdPのログアウトへリダイレクトすること", func(t *testing.T) {
+		s, _ := env.sessions.byCookie(sc.Value)
+		res := logout(s.CSRFToken)
+		if res.StatusCode != http.StatusSeeOther || !strings.HasPrefix(res.Header.Get("Location"), env.provider.srv.URL+"/logout?") {
+			t.Fatalf("status = %d, Location = %q", res.StatusCode, res.Header.Get("Location"))
+		}
+		if s, _ := env.sessions.byCookie(sc.Value); s.RevokedAt == nil {
+			t.Fatal("session must be revoked")
+		}
+		if c := cookieNamed(res, "session"); c == nil || c.MaxAge >= 0 {
+			t.Fatalf("session cookie must be cleared: %+v", c)
+		}
+	})
+}
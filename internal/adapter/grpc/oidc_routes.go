@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+)
+
+func init() { Add(registerOIDC) }
+
+// registerOIDC mounts /auth/login, /auth/callback and /auth/logout when OIDC_ISSUER is set.
+func registerOIDC(mux *http.ServeMux, deps Deps) {
+	cfg, err := auth.LoadOIDCClientConfigFromEnv()
+	if err != nil {
+		log.Fatalf("oidc config: %v", err)
+	}
+	if cfg == nil || deps.Gorm == nil {
+		return
+	}
+	claims, err := auth.LoadClaimMappingFromEnv()
+	if err != nil {
+		log.Fatalf("oidc claim mapping: %v", err)
+	}
+	cookies, err := auth.SessionConfigFromEnv()
+	if err != nil {
+		log.Fatalf("session config: %v", err)
+	}
+	a := deps.authDeps()
+	h := NewOIDCHandler(auth.NewOIDCClient(*cfg, claims), a.identities, a.sessions, cookies)
+	h.Register(mux)
+}
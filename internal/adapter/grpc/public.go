@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	authpb "github.com/xiao1203/go-onion-grpc-template/gen/auth"
+)
+
+// IsPublic reports whether the method is marked (auth.public) = true or
+// belongs to a service marked (auth.public_service) = true.
+func IsPublic(md protoreflect.MethodDescriptor) bool {
+	if md == nil {
+		return false
+	}
+	if v, _ := proto.GetExtension(md.Options(), authpb.E_Public).(bool); v {
+		return true
+	}
+	sd, ok := md.Parent().(protoreflect.ServiceDescriptor)
+	if !ok {
+		return false
+	}
+	v, _ := proto.GetExtension(sd.Options(), authpb.E_PublicService).(bool)
+	return v
+}
+
+// procedureOf returns the connect procedure path, e.g. "/sample.v1.SampleService/GetSample".
+func procedureOf(md protoreflect.MethodDescriptor) string {
+	return "/" + string(md.Parent().FullName()) + "/" + string(md.Name())
+}
+
+// servicePublicProcedures returns the public procedures of a single service.
+func servicePublicProcedures(sd protoreflect.ServiceDescriptor) []string {
+	var out []string
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		if md := methods.Get(i); IsPublic(md) {
+			out = append(out, procedureOf(md))
+		}
+	}
+	return out
+}
+
+// isMounted reports whether mux routes the procedures of the service.
+// Generated handlers are mounted on "/<service full name>/".
+func isMounted(mux *http.ServeMux, sd protoreflect.ServiceDescriptor) bool {
+	path := "/" + string(sd.FullName()) + "/"
+	_, pattern := mux.Handler(&http.Request{Method: http.MethodPost, URL: &url.URL{Path: path}})
+	return pattern == path
+}
+
+// PublicAllowlist returns the procedures that skip authentication, derived
+// from the (auth.public) / (auth.public_service) proto options of the
+// services mounted on mux. Generated code registers the descriptors of every
+// service linked into the binary in protoregistry.GlobalFiles, including
+// services that are not served (e.g. LocalAuthService without AUTH_LOCAL=1);
+// those are left out.
+func PublicAllowlist(mux *http.ServeMux) map[string]struct{} {
+	out := map[string]struct{}{}
+	protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		services := fd.Services()
+		for i := 0; i < services.Len(); i++ {
+			if sd := services.Get(i); isMounted(mux, sd) {
+				for _, p := range servicePublicProcedures(sd) {
+					out[p] = struct{}{}
+				}
+			}
+		}
+		return true
+	})
+	return out
+}
+
+// PublicProcedures returns PublicAllowlist as a sorted slice (for startup logs).
+func PublicProcedures(mux *http.ServeMux) []string {
+	allow := PublicAllowlist(mux)
+	out := make([]string, 0, len(allow))
+	for p := range allow {
+		out = append(out, p)
+	}
+	slices.Sort(out)
+	return out
+}
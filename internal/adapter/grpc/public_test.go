@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	authpb "github.com/xiao1203/go-onion-grpc-template/gen/auth"
+	samplev1connect "github.com/xiao1203/go-onion-grpc-template/gen/sample/v1/samplev1connect"
+	userv1connect "github.com/xiao1203/go-onion-grpc-template/gen/user/v1/userv1connect"
+)
+
+func TestPublicAllowlist(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(samplev1connect.NewSampleServiceHandler(samplev1connect.UnimplementedSampleServiceHandler{}))
+	mux.Handle(userv1connect.NewUserServiceHandler(userv1connect.UnimplementedUserServiceHandler{}))
+	allow := PublicAllowlist(mux)
+	tests := []struct {
+		name      string
+		procedure string
+		want      bool
+	}{
+		{"正常系: public_serviceのサービスは全RPCが含まれること", samplev1connect.SampleServiceGetSampleProcedure, true},
+		{"正常系: public_serviceのサービスは更新系RPCも含まれること", samplev1connect.SampleServiceDeleteSampleProcedure, true},
+		{"正常系: オプションの無いサービスは含まれないこと", userv1connect.UserServiceGetMeProcedure, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := allow[tt.procedure]; got != tt.want {
+				t.Fatalf("allowlist[%s] = %v, want %v", tt.procedure, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("正常系: リンクされていてもマウントされていないサービスの公開RPCは含まれないこと", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.Handle(userv1connect.NewUserServiceHandler(userv1connect.UnimplementedUserServiceHandler{}))
+		if allow := PublicAllowlist(mux); len(allow) != 0 {
+			t.Fatalf("allowlist = %v, want empty", allow)
+		}
+	})
+}
+
+func TestAuth_PublicMethodOption(t *testing.T) {
+	public := &descriptorpb.MethodOptions{}
+	proto.SetExtension(public, authpb.E_Public, true)
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test/v1/public.proto"),
+		Package:    proto.String("test.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/empty.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("PublicTestService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Open"), InputType: proto.String(".google.protobuf.Empty"), OutputType: proto.String(".google.protobuf.Empty"), Options: public},
+				{Name: proto.String("Closed"), InputType: proto.String(".google.protobuf.Empty"), OutputType: proto.String(".google.protobuf.Empty")},
+			},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("build descriptor: %v", err)
+	}
+	methods := fd.Services().Get(0).Methods()
+
+	tests := []struct {
+		name   string
+		method string
+		want   connect.Code
+	}{
+		{"正常系: (auth.public)のRPCは認証なしで呼べること", "Open", 0},
+		{"異常系: オプションの無いRPCは認証が必要なこと", "Closed", connect.CodeUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := methods.ByName(protoreflect.Name(tt.method))
+			procedure := procedureOf(md)
+			mux := http.NewServeMux()
+			mux.Handle(procedure, connect.NewUnaryHandler(procedure,
+				func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+					return connect.NewResponse(&emptypb.Empty{}), nil
+				},
+				connect.WithSchema(md),
+				connect.WithInterceptors(AuthUnaryInterceptor(nil)),
+			))
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+			client := connect.NewClient[emptypb.Empty, emptypb.Empty](srv.Client(), srv.URL+procedure)
+			_, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+			if tt.want == 0 {
+				if err != nil {
+					t.Fatalf("want success, got %v", err)
+				}
+				return
+			}
+			if connect.CodeOf(err) != tt.want {
+				t.Fatalf("want %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
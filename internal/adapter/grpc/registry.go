@@ -1,10 +1,21 @@
 package grpc
 
 import (
+	"context"
 	"database/sql"
+	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"connectrpc.com/connect"
 	"gorm.io/gorm"
+
+	mysqlrepo "github.com/xiao1203/go-onion-grpc-template/internal/adapter/repository/mysql"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
 )
 
 // Deps holds shared dependencies used by service registrars.
@@ -14,6 +25,102 @@ type Deps struct {
 	MySQL *sql.DB
 	// Preferred ORM handle for MySQL-backed repositories.
 	Gorm *gorm.DB
+	// PasswordResetNotifier delivers password reset tokens of LocalAuthService
+	// (AUTH_LOCAL=1). When nil, tokens are only logged with APP_ENV=dev.
+	PasswordResetNotifier usecase.PasswordResetNotifier
+
+	// auth is shared by every service so that in-memory state (e.g. the
+	// revocation cache) is consistent across them. Set by RegisterAll.
+	auth *authDeps
+}
+
+// authDeps are the usecases behind the auth interceptor.
+type authDeps struct {
+	identities  *usecase.IdentityUsecase
+	sessions    *usecase.SessionUsecase
+	apiKeys     *usecase.APIKeyUsecase
+	revocations *usecase.TokenRevocationUsecase
+	roles       *usecase.RoleUsecase
+	// rolesMode is AUTH_DB_ROLES (off, merge or replace).
+	rolesMode string
+	// impersonation requires AUTH_IMPERSONATION_ROLE (default admin).
+	impersonation *usecase.ImpersonationUsecase
+	// events is written in the background; see AUTH_EVENTS.
+	events *usecase.AuthEventUsecase
+}
+
+func newAuthDeps(db *gorm.DB) *authDeps {
+	// A broken session config is reported by the interceptor on each request.
+	var ttl, maxLifetime time.Duration
+	if scfg, err := auth.SessionConfigFromEnv(); err == nil {
+		ttl, maxLifetime = scfg.TTL, scfg.MaxLifetime
+	}
+	cacheTTL := durationFromEnv("AUTH_REVOCATION_CACHE_TTL")
+	bufferSize, _ := strconv.Atoi(os.Getenv("AUTH_EVENTS_BUFFER"))
+	rolesMode, err := auth.DBRolesModeFromEnv()
+	if err != nil {
+		log.Fatalf("db roles: %v", err)
+	}
+	return &authDeps{
+		identities:  usecase.NewIdentityUsecase(mysqlrepo.NewUserIdentityRepository(db)),
+		sessions:    usecase.NewSessionUsecase(mysqlrepo.NewSessionRepository(db), ttl, maxLifetime),
+		apiKeys:     usecase.NewAPIKeyUsecase(mysqlrepo.NewAPIKeyRepository(db)),
+		revocations: usecase.NewTokenRevocationUsecase(mysqlrepo.NewTokenRevocationRepository(db), cacheTTL),
+		roles:       usecase.NewRoleUsecase(mysqlrepo.NewRoleRepository(db), durationFromEnv("AUTH_DB_ROLES_CACHE_TTL")),
+		rolesMode:   rolesMode,
+		impersonation: usecase.NewImpersonationUsecase(
+			mysqlrepo.NewUserRepository(db),
+			mysqlrepo.NewImpersonationLogRepository(db),
+			strings.TrimSpace(os.Getenv("AUTH_IMPERSONATION_ROLE")),
+		),
+		events: usecase.NewAuthEventUsecase(mysqlrepo.NewAuthEventRepository(db), bufferSize),
+	}
+}
+
+// durationFromEnv parses a duration env; unset or invalid values yield 0 (the usecase default).
+func durationFromEnv(name string) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(name))
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+func (d Deps) authDeps() *authDeps {
+	if d.auth == nil {
+		return newAuthDeps(d.Gorm)
+	}
+	return d.auth
+}
+
+// AuthInterceptors returns the handler option every service is registered with:
+// error logging / redaction, Accept-Language error messages, authentication
+// (skipping procedures marked public in the proto; the options are read from
+// the schema of each procedure, so only mounted services are considered),
+// (auth.authz) authorization and then buf.validate request validation. Pass
+// it to the generated
+// New*ServiceHandler.
+func (d Deps) AuthInterceptors() connect.HandlerOption {
+	var opts []AuthOption
+	if d.Gorm != nil {
+		a := d.authDeps()
+		opts = append(opts,
+			WithIdentityProvisioning(a.identities),
+			WithSessions(a.sessions),
+			WithAPIKeys(a.apiKeys),
+			WithTokenRevocation(a.revocations),
+			WithDatabaseRoles(a.roles, a.rolesMode),
+			WithImpersonation(a.impersonation),
+			WithAuthEvents(a.events),
+		)
+	}
+	return connect.WithInterceptors(
+		LoggingUnaryInterceptor(nil),
+		LocalizationUnaryInterceptor(nil),
+		AuthUnaryInterceptor(nil, opts...),
+		AuthzUnaryInterceptor(nil),
+		ValidationUnaryInterceptor(nil),
+	)
 }
 
 // Registrar registers handlers onto the mux using provided deps.
@@ -24,9 +131,20 @@ var registrars []Registrar
 // Add registers a Registrar to be called by RegisterAll.
 func Add(r Registrar) { registrars = append(registrars, r) }
 
-// RegisterAll invokes all registered Registrars.
-func RegisterAll(mux *http.ServeMux, deps Deps) {
+// RegisterAll invokes all registered Registrars. The returned function flushes
+// the background work of the shared dependencies (queued auth events); call it
+// after http.Server.Shutdown so that nothing is lost on restart.
+func RegisterAll(mux *http.ServeMux, deps Deps) (closeDeps func(context.Context) error) {
+	if deps.auth == nil && deps.Gorm != nil {
+		deps.auth = newAuthDeps(deps.Gorm)
+	}
 	for _, r := range registrars {
 		r(mux, deps)
 	}
+	return func(ctx context.Context) error {
+		if deps.auth == nil {
+			return nil
+		}
+		return deps.auth.events.Close(ctx)
+	}
 }
@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	rolev1 "github.com/xiao1203/go-onion-grpc-template/gen/role/v1"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// RoleHandler implements RoleService. Changes invalidate the role cache of the
+// auth interceptor on this instance; other instances pick them up within
+// AUTH_DB_ROLES_CACHE_TTL.
+type RoleHandler struct {
+	roles *usecase.RoleUsecase
+}
+
+func NewRoleHandler(roles *usecase.RoleUsecase) *RoleHandler {
+	return &RoleHandler{roles: roles}
+}
+
+func (h *RoleHandler) GrantRole(ctx context.Context, req *connect.Request[rolev1.GrantRoleRequest]) (*connect.Response[rolev1.GrantRoleResponse], error) {
+	if err := h.roles.GrantRole(ctx, int64(req.Msg.GetUserId()), req.Msg.GetRole()); err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	return connect.NewResponse(&rolev1.GrantRoleResponse{}), nil
+}
+
+func (h *RoleHandler) RevokeRole(ctx context.Context, req *connect.Request[rolev1.RevokeRoleRequest]) (*connect.Response[rolev1.RevokeRoleResponse], error) {
+	if err := h.roles.RevokeRole(ctx, int64(req.Msg.GetUserId()), req.Msg.GetRole()); err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	return connect.NewResponse(&rolev1.RevokeRoleResponse{}), nil
+}
+
+func (h *RoleHandler) ListUserRoles(ctx context.Context, req *connect.Request[rolev1.ListUserRolesRequest]) (*connect.Response[rolev1.ListUserRolesResponse], error) {
+	roles, err := h.roles.RolesForUser(ctx, int64(req.Msg.GetUserId()))
+	if err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	return connect.NewResponse(&rolev1.ListUserRolesResponse{Roles: roles}), nil
+}
@@ -0,0 +1,15 @@
+package grpc
+
+import (
+	"net/http"
+
+	rolev1connect "github.com/xiao1203/go-onion-grpc-template/gen/role/v1/rolev1connect"
+)
+
+func init() { Add(registerRole) }
+
+func registerRole(mux *http.ServeMux, deps Deps) {
+	h := NewRoleHandler(deps.authDeps().roles)
+	path, handler := rolev1connect.NewRoleServiceHandler(h, deps.AuthInterceptors())
+	mux.Handle(path, handler)
+}
@@ -128,6 +128,12 @@ func TestSampleHandler_GetSample(t *testing.T) {
 	if diff := cmp.Diff(want, got.Msg.GetSample(), protocmp.Transform()); diff != "" {
 		t.Errorf("GetSample mismatch (-want +got):\n%s", diff)
 	}
+
+	// 存在しないIDは空レスポンスではなくNotFoundになること
+	_, err = client.GetSample(ctx, connect.NewRequest(&samplev1.GetSampleRequest{Id: 999}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Fatalf("GetSample(999) code = %v, want CodeNotFound (%v)", connect.CodeOf(err), err)
+	}
 }
 
 func TestSampleHandler_ListSamples(t *testing.T) {
@@ -213,9 +219,10 @@ func TestSampleHandler_UpdateSample(t *testing.T) {
 		req *connect.Request[samplev1.UpdateSampleRequest]
 	}
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name     string
+		args     args
+		wantErr  bool
+		wantCode connect.Code
 	}{
 		{
 			name:    "正常系: 指定したIDのSampleレコードの更新に成功すること",
@@ -228,7 +235,14 @@ func TestSampleHandler_UpdateSample(t *testing.T) {
 				long := strings.Repeat("y", 300)
 				return connect.NewRequest(&samplev1.UpdateSampleRequest{Id: 1, Name: long, Content: "ok", Count: 1})
 			}()},
-			wantErr: true,
+			wantErr:  true,
+			wantCode: connect.CodeInternal,
+		},
+		{
+			name:     "異常系: 存在しないIDの場合はNotFoundエラーになること",
+			args:     args{req: connect.NewRequest(&samplev1.UpdateSampleRequest{Id: 999, Name: "updated_name", Content: "updated_content", Count: 100})},
+			wantErr:  true,
+			wantCode: connect.CodeNotFound,
 		},
 	}
 	for _, tt := range tests {
@@ -238,8 +252,8 @@ func TestSampleHandler_UpdateSample(t *testing.T) {
 				if !tt.wantErr {
 					t.Errorf("UpdateSample() failed: %v", gotErr)
 				}
-				if connect.CodeOf(gotErr) != connect.CodeInternal {
-					t.Fatalf("want CodeInternal, got %v", connect.CodeOf(gotErr))
+				if connect.CodeOf(gotErr) != tt.wantCode {
+					t.Fatalf("want %v, got %v", tt.wantCode, connect.CodeOf(gotErr))
 				}
 				return
 			}
@@ -279,15 +293,22 @@ func TestSampleHandler_DeleteSample(t *testing.T) {
 		req *connect.Request[samplev1.DeleteSampleRequest]
 	}
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name     string
+		args     args
+		wantErr  bool
+		wantCode connect.Code
 	}{
 		{
 			name:    "正常系: 指定したIDのSampleレコードの削除に成功すること",
 			args:    args{req: connect.NewRequest(&samplev1.DeleteSampleRequest{Id: 1})},
 			wantErr: false,
 		},
+		{
+			name:     "異常系: 存在しないIDの場合はNotFoundエラーになること",
+			args:     args{req: connect.NewRequest(&samplev1.DeleteSampleRequest{Id: 999})},
+			wantErr:  true,
+			wantCode: connect.CodeNotFound,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -296,6 +317,9 @@ func TestSampleHandler_DeleteSample(t *testing.T) {
 				if !tt.wantErr {
 					t.Errorf("DeleteSample() failed: %v", gotErr)
 				}
+				if connect.CodeOf(gotErr) != tt.wantCode {
+					t.Fatalf("want %v, got %v", tt.wantCode, connect.CodeOf(gotErr))
+				}
 				return
 			}
 			if tt.wantErr {
@@ -303,12 +327,9 @@ func TestSampleHandler_DeleteSample(t *testing.T) {
 			} else {
 				// ensure deleted
 				getReq := connect.NewRequest(&samplev1.GetSampleRequest{Id: tt.args.req.Msg.GetId()})
-				got, err := client.GetSample(ctx, getReq)
-				if err != nil {
-					t.Fatalf("GetSample() after delete error = %v", err)
-				}
-				if got.Msg.GetSample() != nil {
-					t.Fatalf("GetSample() after delete = %#v, want nil Sample", got.Msg.GetSample())
+				_, err := client.GetSample(ctx, getReq)
+				if connect.CodeOf(err) != connect.CodeNotFound {
+					t.Fatalf("GetSample() after delete code = %v, want CodeNotFound (%v)", connect.CodeOf(err), err)
 				}
 			}
 		})
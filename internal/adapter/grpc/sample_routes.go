@@ -14,6 +14,7 @@ func registerSample(mux *http.ServeMux, deps Deps) {
 	repo := mysqlrepo.NewSampleRepository(deps.Gorm)
 	uc := usecase.NewSampleUsecase(repo)
 	h := NewSampleHandler(uc)
-	path, handler := samplev1connect.NewSampleServiceHandler(h)
+	// SampleService is (auth.public_service) in the proto, so the interceptors let it through
+	path, handler := samplev1connect.NewSampleServiceHandler(h, deps.AuthInterceptors())
 	mux.Handle(path, handler)
 }
@@ -0,0 +1,89 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// WithSessions enables cookie authentication (BFF mode): a request without an
+// Authorization header is authenticated by its session cookie, and mutating
+// RPCs (anything not marked idempotency_level = NO_SIDE_EFFECTS) must echo the
+// session's CSRF token in the X-CSRF-Token header.
+func WithSessions(uc *usecase.SessionUsecase) AuthOption {
+	return func(c *authConfig) { c.sessions = uc }
+}
+
+// sessionCookie returns the session ID cookie value, if any.
+func sessionCookie(h http.Header, name string) string {
+	c, err := (&http.Request{Header: h}).Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return c.Value
+}
+
+// sessionAuthenticator authenticates requests without an Authorization
+// header by their session cookie, checks CSRF, and re-sets the cookies on the
+// response when the session slid.
+type sessionAuthenticator struct{ sessions *usecase.SessionUsecase }
+
+func (a sessionAuthenticator) Authenticate(ctx context.Context, req connect.AnyRequest) (*Authentication, error) {
+	if req.Header().Get("Authorization") != "" {
+		return nil, nil
+	}
+	scfg, err := auth.SessionConfigFromEnv()
+	if err != nil {
+		return nil, ergo.WithCode(ergo.Wrap(err, "load session config"), apperr.Internal)
+	}
+	id := sessionCookie(req.Header(), scfg.CookieName)
+	if id == "" {
+		return nil, nil
+	}
+	s, slid, err := a.sessions.Validate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !isReadOnly(req.Spec()) {
+		got := req.Header().Get(scfg.CSRFHeader)
+		if got == "" || subtle.ConstantTimeCompare([]byte(got), []byte(s.CSRFToken)) != 1 {
+			return nil, ergo.WithCode(ergo.New("invalid CSRF token"), apperr.PermissionDenied)
+		}
+	}
+	res := &Authentication{Principal: &auth.Principal{
+		UserID:    s.UserID,
+		Subject:   strconv.FormatInt(s.UserID, 10),
+		Provider:  "session",
+		SessionID: s.ID,
+	}}
+	if slid {
+		res.OnSuccess = func(resp connect.AnyResponse) {
+			resp.Header().Add("Set-Cookie", scfg.SessionCookie(s.ID, s.ExpiresAt).String())
+			resp.Header().Add("Set-Cookie", scfg.CSRFCookie(s.CSRFToken, s.ExpiresAt).String())
+		}
+	}
+	return res, nil
+}
+
+// isReadOnly reports whether the RPC is declared idempotency_level = NO_SIDE_EFFECTS.
+func isReadOnly(spec connect.Spec) bool {
+	if spec.IdempotencyLevel == connect.IdempotencyNoSideEffects {
+		return true
+	}
+	md, ok := spec.Schema.(protoreflect.MethodDescriptor)
+	if !ok {
+		return false
+	}
+	opts, _ := md.Options().(*descriptorpb.MethodOptions)
+	return opts.GetIdempotencyLevel() == descriptorpb.MethodOptions_NO_SIDE_EFFECTS
+}
@@ -0,0 +1,148 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	iauth "github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// memSessions is an in-memory SessionRepository.
+type memSessions map[string]*entity.Session
+
+func (m memSessions) Create(ctx context.Context, s *entity.Session) error {
+	cp := *s
+	m[s.ID] = &cp
+	return nil
+}
+
+func (m memSessions) FindByID(ctx context.Context, id string) (*entity.Session, error) {
+	if v, ok := m[id]; ok {
+		cp := *v
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+// byCookie returns the session stored for a session cookie value; the
+// repository is keyed by the SHA-256 of the session ID.
+func (m memSessions) byCookie(value string) (*entity.Session, bool) {
+	sum := sha256.Sum256([]byte(value))
+	s, ok := m[hex.EncodeToString(sum[:])]
+	return s, ok
+}
+
+func (m memSessions) Extend(ctx context.Context, id string, expiresAt time.Time) error {
+	m[id].ExpiresAt = expiresAt
+	return nil
+}
+
+func (m memSessions) Revoke(ctx context.Context, id string, at time.Time) error {
+	m[id].RevokedAt = &at
+	return nil
+}
+
+func (m memSessions) RevokeAllForUser(ctx context.Context, userID int64, at time.Time) error {
+	return nil
+}
+
+func TestAuth_SessionCookie(t *testing.T) {
+	t.Setenv("SESSION_COOKIE_SECURE", "0")
+	readOnly := &descriptorpb.MethodOptions{IdempotencyLevel: descriptorpb.MethodOptions_NO_SIDE_EFFECTS.Enum()}
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test/v1/session.proto"),
+		Package:    proto.String("test.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/empty.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("SessionTestService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Read"), InputType: proto.String(".google.protobuf.Empty"), OutputType: proto.String(".google.protobuf.Empty"), Options: readOnly},
+				{Name: proto.String("Write"), InputType: proto.String(".google.protobuf.Empty"), OutputType: proto.String(".google.protobuf.Empty")},
+			},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("build descriptor: %v", err)
+	}
+	methods := fd.Services().Get(0).Methods()
+
+	sessions := usecase.NewSessionUsecase(memSessions{}, time.Hour, 0)
+	s, err := sessions.Create(context.Background(), 7, "", "")
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	revoked, err := sessions.Create(context.Background(), 8, "", "")
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if err := sessions.Revoke(context.Background(), revoked.ID); err != nil {
+		t.Fatalf("Revoke() failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		cookie  string
+		headers map[string]string
+		want    connect.Code
+	}{
+		{"正常系: 参照系RPCはセッションCookieだけで呼べること", "Read", s.ID, nil, 0},
+		{"正常系: 変更系RPCはCSRFトークン付きで呼べること", "Write", s.ID, map[string]string{"X-CSRF-Token": s.CSRFToken}, 0},
+		{"異常系: 変更系RPCでCSRFトークンが無い場合はPermissionDeniedになること", "Write", s.ID, nil, connect.CodePermissionDenied},
+		{"異常系: CSRFトークンが一致しない場合はPermissionDeniedになること", "Write", s.ID, map[string]string{"X-CSRF-Token": "wrong"}, connect.CodePermissionDenied},
+		{"異常系: 失効したセッションはUnauthenticatedになること", "Read", revoked.ID, nil, connect.CodeUnauthenticated},
+		{"異常系: 未知のセッションはUnauthenticatedになること", "Read", "unknown", nil, connect.CodeUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := methods.ByName(protoreflect.Name(tt.method))
+			procedure := procedureOf(md)
+			mux := http.NewServeMux()
+			mux.Handle(procedure, connect.NewUnaryHandler(procedure,
+				func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+					p, ok := iauth.FromContext(ctx)
+					if !ok || p.UserID != 7 || p.SessionID != s.ID {
+						return nil, connect.NewError(connect.CodeInternal, nil)
+					}
+					return connect.NewResponse(&emptypb.Empty{}), nil
+				},
+				connect.WithSchema(md),
+				connect.WithInterceptors(AuthUnaryInterceptor(nil, WithSessions(sessions))),
+			))
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+			client := connect.NewClient[emptypb.Empty, emptypb.Empty](srv.Client(), srv.URL+procedure)
+			req := connect.NewRequest(&emptypb.Empty{})
+			req.Header().Set("Cookie", (&http.Cookie{Name: "session", Value: tt.cookie}).String())
+			for k, v := range tt.headers {
+				req.Header().Set(k, v)
+			}
+			_, err := client.CallUnary(context.Background(), req)
+			if tt.want == 0 {
+				if err != nil {
+					t.Fatalf("want success, got %v", err)
+				}
+				return
+			}
+			if connect.CodeOf(err) != tt.want {
+				t.Fatalf("want %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
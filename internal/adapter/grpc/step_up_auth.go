@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	authpb "github.com/xiao1203/go-onion-grpc-template/gen/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+)
+
+// stepUpRuleFor returns the step-up rule of the procedure: the (auth.step_up)
+// option, falling back to the "step_up" section of AUTHZ_POLICY(_FILE).
+func stepUpRuleFor(spec connect.Spec) (auth.StepUpRule, error) {
+	if md, ok := spec.Schema.(protoreflect.MethodDescriptor); ok && proto.HasExtension(md.Options(), authpb.E_StepUp) {
+		r, _ := proto.GetExtension(md.Options(), authpb.E_StepUp).(*authpb.StepUpRule)
+		return auth.StepUpRule{ACR: r.GetAcr(), AMR: r.GetAmr(), MaxAgeSeconds: r.GetMaxAgeSeconds()}, nil
+	}
+	pol, err := auth.PolicyFromEnv()
+	if err != nil {
+		return auth.StepUpRule{}, ergo.WithCode(ergo.Wrap(err, "load authz policy"), apperr.Internal)
+	}
+	r, _ := pol.StepUpFor(spec.Procedure)
+	return r, nil
+}
+
+// checkStepUp returns a structured Unauthenticated error when the principal
+// does not meet the step-up rule of the procedure. The error carries a
+// StepUpRequired detail and an RFC 9470 WWW-Authenticate header.
+func checkStepUp(spec connect.Spec, p *auth.Principal, now time.Time) error {
+	rule, err := stepUpRuleFor(spec)
+	if err != nil {
+		return apperr.ToConnect(err)
+	}
+	if rule.IsZero() {
+		return nil
+	}
+	reason := rule.Unmet(p, now)
+	if reason == "" {
+		return nil
+	}
+	return stepUpError(rule, reason)
+}
+
+func stepUpError(rule auth.StepUpRule, reason string) error {
+	msg := "step-up authentication required: " + reason
+	err := apperr.ToConnect(ergo.WithCode(ergo.New(msg, apperr.Reason(apperr.ReasonStepUpRequired), apperr.ClientMessage(msg)), apperr.Unauthenticated))
+	var ce *connect.Error
+	if !errors.As(err, &ce) {
+		return err
+	}
+	if d, derr := connect.NewErrorDetail(&authpb.StepUpRequired{
+		Reason:        reason,
+		AcrValues:     rule.ACR,
+		Amr:           rule.AMR,
+		MaxAgeSeconds: rule.MaxAgeSeconds,
+	}); derr == nil {
+		ce.AddDetail(d)
+	}
+	challenge := []string{`Bearer error="insufficient_user_authentication"`, `error_description="step-up required: ` + reason + `"`}
+	if len(rule.ACR) > 0 {
+		challenge = append(challenge, `acr_values="`+strings.Join(rule.ACR, " ")+`"`)
+	}
+	if rule.MaxAgeSeconds > 0 {
+		challenge = append(challenge, "max_age="+strconv.FormatInt(rule.MaxAgeSeconds, 10))
+	}
+	ce.Meta().Set("WWW-Authenticate", strings.Join(challenge, ", "))
+	return ce
+}
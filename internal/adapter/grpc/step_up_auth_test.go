@@ -0,0 +1,157 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	authpb "github.com/xiao1203/go-onion-grpc-template/gen/auth"
+)
+
+// stepUpService builds "stepup.v1.StepUpService" with Sensitive (acr, amr mfa
+// and auth_time within 5 minutes) and Plain (no option).
+func stepUpService(t *testing.T) protoreflect.ServiceDescriptor {
+	t.Helper()
+	opts := &descriptorpb.MethodOptions{}
+	proto.SetExtension(opts, authpb.E_StepUp, &authpb.StepUpRule{Acr: []string{"urn:mace:incommon:iap:silver"}, Amr: []string{"mfa"}, MaxAgeSeconds: 300})
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("stepup/v1/stepup.proto"),
+		Package:    proto.String("stepup.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/empty.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("StepUpService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Sensitive"), InputType: proto.String(".google.protobuf.Empty"), OutputType: proto.String(".google.protobuf.Empty"), Options: opts},
+				{Name: proto.String("Plain"), InputType: proto.String(".google.protobuf.Empty"), OutputType: proto.String(".google.protobuf.Empty")},
+			},
+		}},
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("build descriptor: %v", err)
+	}
+	return fd.Services().Get(0)
+}
+
+// stepUpClients serves the methods of stepUpService through AuthUnaryInterceptor.
+func stepUpClients(t *testing.T) map[string]*connect.Client[emptypb.Empty, emptypb.Empty] {
+	t.Helper()
+	methods := stepUpService(t).Methods()
+	mux := http.NewServeMux()
+	var procedures []string
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		procedure := procedureOf(md)
+		procedures = append(procedures, procedure)
+		mux.Handle(procedure, connect.NewUnaryHandler(procedure,
+			func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+				return connect.NewResponse(&emptypb.Empty{}), nil
+			},
+			connect.WithSchema(md),
+			connect.WithInterceptors(AuthUnaryInterceptor(nil)),
+		))
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	clients := map[string]*connect.Client[emptypb.Empty, emptypb.Empty]{}
+	for _, p := range procedures {
+		clients[p] = connect.NewClient[emptypb.Empty, emptypb.Empty](srv.Client(), srv.URL+p)
+	}
+	return clients
+}
+
+func callWithToken(client *connect.Client[emptypb.Empty, emptypb.Empty], claims jwt.MapClaims) error {
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	if err != nil {
+		return err
+	}
+	req := connect.NewRequest(&emptypb.Empty{})
+	req.Header().Set("Authorization", "Bearer "+s)
+	_, err = client.CallUnary(context.Background(), req)
+	return err
+}
+
+func TestAuth_StepUp(t *testing.T) {
+	t.Setenv("DEV_AUTH_BYPASS", "")
+	t.Setenv("AUTH_JWKS_URL", "")
+	t.Setenv("AUTH_ISSUERS", "")
+	t.Setenv("AUTH_ISSUERS_FILE", "")
+	t.Setenv("AUTH_HS256_SECRET", "secret")
+
+	t.Setenv("AUTHZ_POLICY", `{"step_up": {"/stepup.v1.StepUpService/Plain": {"max_age_seconds": 60}}}`)
+	t.Setenv("AUTHZ_POLICY_FILE", "")
+	clients := stepUpClients(t)
+
+	now := time.Now()
+	tests := []struct {
+		name       string
+		claims     jwt.MapClaims
+		wantReason string
+	}{
+		{"正常系: acr/amr/auth_timeを満たせば通ること", jwt.MapClaims{"acr": "urn:mace:incommon:iap:silver", "amr": []string{"pwd", "mfa"}, "auth_time": now.Add(-time.Minute).Unix()}, ""},
+		{"異常系: acrが異なる場合はacrのステップアップを要求すること", jwt.MapClaims{"acr": "0", "amr": []string{"mfa"}, "auth_time": now.Unix()}, "acr"},
+		{"異常系: amrにmfaが無い場合はamrのステップアップを要求すること", jwt.MapClaims{"acr": "urn:mace:incommon:iap:silver", "amr": []string{"pwd"}, "auth_time": now.Unix()}, "amr"},
+		{"異常系: auth_timeが古い場合は再認証を要求すること", jwt.MapClaims{"acr": "urn:mace:incommon:iap:silver", "amr": []string{"mfa"}, "auth_time": now.Add(-10 * time.Minute).Unix()}, "max_age"},
+		{"異常系: auth_timeが無い場合は再認証を要求すること", jwt.MapClaims{"acr": "urn:mace:incommon:iap:silver", "amr": []string{"mfa"}}, "max_age"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.claims["sub"] = "1"
+			tt.claims["exp"] = now.Add(5 * time.Minute).Unix()
+			err := callWithToken(clients["/stepup.v1.StepUpService/Sensitive"], tt.claims)
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Fatalf("err: %v", err)
+				}
+				return
+			}
+			var ce *connect.Error
+			if !errors.As(err, &ce) || ce.Code() != connect.CodeUnauthenticated {
+				t.Fatalf("want Unauthenticated, got %v", err)
+			}
+			var got *authpb.StepUpRequired
+			for _, d := range ce.Details() {
+				if v, derr := d.Value(); derr == nil {
+					if m, ok := v.(*authpb.StepUpRequired); ok {
+						got = m
+					}
+				}
+			}
+			if got == nil || got.GetReason() != tt.wantReason || got.GetMaxAgeSeconds() != 300 || got.GetAmr()[0] != "mfa" {
+				t.Fatalf("StepUpRequired detail = %v", got)
+			}
+			if h := ce.Meta().Get("WWW-Authenticate"); !strings.Contains(h, `error="insufficient_user_authentication"`) || !strings.Contains(h, "max_age=300") {
+				t.Fatalf("WWW-Authenticate = %q", h)
+			}
+		})
+	}
+
+	t.Run("正常系: AUTHZ_POLICYのstep_upを満たせば通ること", func(t *testing.T) {
+		claims := jwt.MapClaims{"sub": "1", "auth_time": now.Unix(), "exp": now.Add(5 * time.Minute).Unix()}
+		if err := callWithToken(clients["/stepup.v1.StepUpService/Plain"], claims); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	})
+
+	t.Run("異常系: AUTHZ_POLICYのstep_upでも再認証を要求すること", func(t *testing.T) {
+		claims := jwt.MapClaims{"sub": "1", "auth_time": now.Add(-time.Hour).Unix(), "exp": now.Add(5 * time.Minute).Unix()}
+		err := callWithToken(clients["/stepup.v1.StepUpService/Plain"], claims)
+		if connect.CodeOf(err) != connect.CodeUnauthenticated {
+			t.Fatalf("want Unauthenticated, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	tokenv1 "github.com/xiao1203/go-onion-grpc-template/gen/token/v1"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// TokenHandler implements TokenService. Revoking all tokens of a user also
+// revokes the user's cookie sessions when sessions is set.
+type TokenHandler struct {
+	revocations *usecase.TokenRevocationUsecase
+	sessions    *usecase.SessionUsecase
+}
+
+func NewTokenHandler(revocations *usecase.TokenRevocationUsecase, sessions *usecase.SessionUsecase) *TokenHandler {
+	return &TokenHandler{revocations: revocations, sessions: sessions}
+}
+
+func (h *TokenHandler) RevokeToken(ctx context.Context, req *connect.Request[tokenv1.RevokeTokenRequest]) (*connect.Response[tokenv1.RevokeTokenResponse], error) {
+	var exp time.Time
+	if v := req.Msg.GetExpiresAt(); v > 0 {
+		exp = time.Unix(v, 0)
+	}
+	if err := h.revocations.RevokeToken(ctx, req.Msg.GetJti(), int64(req.Msg.GetUserId()), exp); err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	return connect.NewResponse(&tokenv1.RevokeTokenResponse{}), nil
+}
+
+func (h *TokenHandler) RevokeUserTokens(ctx context.Context, req *connect.Request[tokenv1.RevokeUserTokensRequest]) (*connect.Response[tokenv1.RevokeUserTokensResponse], error) {
+	if err := h.revokeUser(ctx, int64(req.Msg.GetUserId())); err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	return connect.NewResponse(&tokenv1.RevokeUserTokensResponse{}), nil
+}
+
+func (h *TokenHandler) RevokeMyTokens(ctx context.Context, req *connect.Request[tokenv1.RevokeMyTokensRequest]) (*connect.Response[tokenv1.RevokeMyTokensResponse], error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.UserID == 0 {
+		return nil, apperr.ToConnect(ergo.WithCode(ergo.New("unauthenticated"), apperr.Unauthenticated))
+	}
+	if err := h.revokeUser(ctx, p.UserID); err != nil {
+		return nil, apperr.ToConnect(err)
+	}
+	return connect.NewResponse(&tokenv1.RevokeMyTokensResponse{}), nil
+}
+
+func (h *TokenHandler) revokeUser(ctx context.Context, userID int64) error {
+	if err := h.revocations.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+	if h.sessions != nil {
+		return h.sessions.RevokeAll(ctx, userID)
+	}
+	return nil
+}
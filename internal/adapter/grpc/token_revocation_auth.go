@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// WithTokenRevocation rejects verified JWTs whose jti is revoked or that were
+// issued before the user's revoke-all timestamp.
+func WithTokenRevocation(uc *usecase.TokenRevocationUsecase) AuthOption {
+	return func(c *authConfig) { c.revocations = uc }
+}
+
+// checkRevocation runs after the token is verified and Principal.UserID resolved.
+func checkRevocation(ctx context.Context, cfg *authConfig, p *auth.Principal) error {
+	if cfg.revocations == nil {
+		return nil
+	}
+	jti, _ := p.Claims["jti"].(string)
+	var iat time.Time
+	if v, ok := p.Claims["iat"].(float64); ok {
+		iat = time.Unix(int64(v), 0)
+	}
+	return cfg.revocations.CheckToken(ctx, jti, p.UserID, iat)
+}
@@ -0,0 +1,89 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// memRevocations is an in-memory TokenRevocationRepository.
+type memRevocations struct {
+	jtis  map[string]bool
+	users map[int64]time.Time
+}
+
+func (m *memRevocations) RevokeJTI(ctx context.Context, jti string, userID int64, expiresAt, at time.Time) error {
+	m.jtis[jti] = true
+	return nil
+}
+
+func (m *memRevocations) IsJTIRevoked(ctx context.Context, jti string) (bool, error) {
+	return m.jtis[jti], nil
+}
+
+func (m *memRevocations) RevokeUserBefore(ctx context.Context, userID int64, before time.Time) error {
+	m.users[userID] = before
+	return nil
+}
+
+func (m *memRevocations) RevokedBefore(ctx context.Context, userID int64) (*time.Time, error) {
+	if v, ok := m.users[userID]; ok {
+		return &v, nil
+	}
+	return nil, nil
+}
+
+func TestAuth_TokenRevocation(t *testing.T) {
+	t.Setenv("DEV_AUTH_BYPASS", "")
+	t.Setenv("AUTH_JWKS_URL", "")
+	t.Setenv("AUTH_ISSUERS", "")
+	t.Setenv("AUTH_ISSUERS_FILE", "")
+	t.Setenv("AUTH_HS256_SECRET", "secret")
+	ctx := context.Background()
+	uc := usecase.NewTokenRevocationUsecase(&memRevocations{jtis: map[string]bool{}, users: map[int64]time.Time{}}, time.Minute)
+	if err := uc.RevokeToken(ctx, "revoked-jti", 1, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("RevokeToken() failed: %v", err)
+	}
+	if err := uc.RevokeAllForUser(ctx, 2); err != nil {
+		t.Fatalf("RevokeAllForUser() failed: %v", err)
+	}
+	old := time.Now().Add(-time.Hour).Unix()
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   connect.Code
+	}{
+		{"正常系: 失効していないトークンは通ること", jwt.MapClaims{"sub": "1", "jti": "ok-jti", "iat": old}, 0},
+		{"異常系: 失効済みjtiはUnauthenticatedになること", jwt.MapClaims{"sub": "1", "jti": "revoked-jti", "iat": old}, connect.CodeUnauthenticated},
+		{"異常系: 全トークン失効前に発行されたトークンはUnauthenticatedになること", jwt.MapClaims{"sub": "2", "jti": "x", "iat": old}, connect.CodeUnauthenticated},
+		{"正常系: 全トークン失効後に発行されたトークンは通ること", jwt.MapClaims{"sub": "2", "jti": "y", "iat": time.Now().Add(2 * time.Second).Unix()}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.claims["exp"] = time.Now().Add(5 * time.Minute).Unix()
+			s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tt.claims).SignedString([]byte("secret"))
+			if err != nil {
+				t.Fatalf("sign err: %v", err)
+			}
+			req := connect.NewRequest(&pingReq{})
+			req.Header().Set("Authorization", "Bearer "+s)
+			next := func(ctx context.Context, r connect.AnyRequest) (connect.AnyResponse, error) { return nil, nil }
+			_, err = AuthUnaryInterceptor(nil, WithTokenRevocation(uc))(next)(ctx, req)
+			if tt.want == 0 {
+				if err != nil {
+					t.Fatalf("err: %v", err)
+				}
+				return
+			}
+			if connect.CodeOf(err) != tt.want {
+				t.Fatalf("want %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
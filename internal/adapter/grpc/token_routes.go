@@ -0,0 +1,16 @@
+package grpc
+
+import (
+	"net/http"
+
+	tokenv1connect "github.com/xiao1203/go-onion-grpc-template/gen/token/v1/tokenv1connect"
+)
+
+func init() { Add(registerToken) }
+
+func registerToken(mux *http.ServeMux, deps Deps) {
+	a := deps.authDeps()
+	h := NewTokenHandler(a.revocations, a.sessions)
+	path, handler := tokenv1connect.NewTokenServiceHandler(h, deps.AuthInterceptors())
+	mux.Handle(path, handler)
+}
@@ -3,7 +3,6 @@ package grpc
 import (
 	"net/http"
 
-	"connectrpc.com/connect"
 	userv1connect "github.com/xiao1203/go-onion-grpc-template/gen/user/v1/userv1connect"
 	mysqlrepo "github.com/xiao1203/go-onion-grpc-template/internal/adapter/repository/mysql"
 	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
@@ -15,8 +14,6 @@ func registerUser(mux *http.ServeMux, deps Deps) {
 	repo := mysqlrepo.NewUserRepository(deps.Gorm)
 	uc := usecase.NewUserUsecase(repo)
 	h := NewUserHandler(uc)
-	// attach auth interceptor (public allowlist currently empty)
-	opts := connect.WithInterceptors(AuthUnaryInterceptor(PublicAllowlist()))
-	path, handler := userv1connect.NewUserServiceHandler(h, opts)
+	path, handler := userv1connect.NewUserServiceHandler(h, deps.AuthInterceptors())
 	mux.Handle(path, handler)
 }
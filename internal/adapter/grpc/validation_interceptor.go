@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"buf.build/go/protovalidate"
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+)
+
+// ValidationUnaryInterceptor checks every request message against its
+// buf.validate constraints before the handler runs. Violations are returned as
+// InvalidArgument with one google.rpc.BadRequest field violation per field.
+// A nil validator uses protovalidate.GlobalValidator.
+func ValidationUnaryInterceptor(v protovalidate.Validator) connect.UnaryInterceptorFunc {
+	if v == nil {
+		v = protovalidate.GlobalValidator
+	}
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			msg, ok := req.Any().(proto.Message)
+			if !ok {
+				return next(ctx, req)
+			}
+			if err := v.Validate(msg); err != nil {
+				return nil, apperr.ToConnect(validationError(err))
+			}
+			return next(ctx, req)
+		}
+	})
+}
+
+// validationError converts a protovalidate error into an app error. Several
+// violations on the same field are joined into one description.
+func validationError(err error) error {
+	var verr *protovalidate.ValidationError
+	if !errors.As(err, &verr) {
+		return ergo.WithCode(ergo.Wrap(err, "validate request"), apperr.Internal)
+	}
+	var fields []string
+	msgs := map[string][]string{}
+	for _, v := range verr.Violations {
+		field := protovalidate.FieldPathString(v.Proto.GetField())
+		if _, ok := msgs[field]; !ok {
+			fields = append(fields, field)
+		}
+		msgs[field] = append(msgs[field], v.Proto.GetMessage())
+	}
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		attrs = append(attrs, apperr.FieldViolation(f, strings.Join(msgs[f], "; ")))
+	}
+	return ergo.WithCode(ergo.New("invalid request", attrs...), apperr.InvalidArgument)
+}
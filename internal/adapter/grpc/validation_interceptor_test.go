@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	samplev1 "github.com/xiao1203/go-onion-grpc-template/gen/sample/v1"
+	samplev1connect "github.com/xiao1203/go-onion-grpc-template/gen/sample/v1/samplev1connect"
+)
+
+type validationSampleHandler struct {
+	samplev1connect.UnimplementedSampleServiceHandler
+}
+
+func (validationSampleHandler) CreateSample(_ context.Context, req *connect.Request[samplev1.CreateSampleRequest]) (*connect.Response[samplev1.CreateSampleResponse], error) {
+	return connect.NewResponse(&samplev1.CreateSampleResponse{Sample: &samplev1.Sample{Id: 1, Name: req.Msg.GetName()}}), nil
+}
+
+func TestValidationInterceptor(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(samplev1connect.NewSampleServiceHandler(validationSampleHandler{},
+		connect.WithInterceptors(ValidationUnaryInterceptor(nil))))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	client := samplev1connect.NewSampleServiceClient(srv.Client(), srv.URL)
+
+	tests := []struct {
+		name       string
+		req        *samplev1.CreateSampleRequest
+		wantCode   connect.Code
+		wantFields []string
+	}{
+		{"正常系: 制約を満たすリクエストはハンドラに届くこと", &samplev1.CreateSampleRequest{Name: "sample", Content: "ok"}, 0, nil},
+		{"異常系: nameが空の場合はInvalidArgumentになること", &samplev1.CreateSampleRequest{Content: "ok"}, connect.CodeInvalidArgument, []string{"name"}},
+		{"異常系: 複数フィールドの違反がまとめて返ること", &samplev1.CreateSampleRequest{Name: strings.Repeat("x", 256), Content: strings.Repeat("x", 256)}, connect.CodeInvalidArgument, []string{"content", "name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CreateSample(context.Background(), connect.NewRequest(tt.req))
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Fatalf("CreateSample() error = %v", err)
+				}
+				return
+			}
+			if got := connect.CodeOf(err); got != tt.wantCode {
+				t.Fatalf("code = %v, want %v (%v)", got, tt.wantCode, err)
+			}
+			var ce *connect.Error
+			if !errors.As(err, &ce) {
+				t.Fatalf("not a connect error: %v", err)
+			}
+			var fields []string
+			for _, d := range ce.Details() {
+				v, derr := d.Value()
+				if derr != nil {
+					t.Fatalf("decode detail: %v", derr)
+				}
+				if br, ok := v.(*errdetails.BadRequest); ok {
+					for _, fv := range br.GetFieldViolations() {
+						if fv.GetDescription() == "" {
+							t.Errorf("field %s has no description", fv.GetField())
+						}
+						fields = append(fields, fv.GetField())
+					}
+				}
+			}
+			if diff := cmp.Diff(tt.wantFields, fields); diff != "" {
+				t.Errorf("field violations mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+type APIKeyModel struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement"`
+	Name        string     `gorm:"column:name;size:255;not null"`
+	Prefix      string     `gorm:"column:prefix;size:16;not null"`
+	KeyHash     string     `gorm:"column:key_hash;size:64;not null"`
+	OwnerUserID int64      `gorm:"column:owner_user_id;not null"`
+	Scopes      string     `gorm:"column:scopes;size:1024;not null"`
+	ExpiresAt   *time.Time `gorm:"column:expires_at"`
+	LastUsedAt  *time.Time `gorm:"column:last_used_at"`
+	RevokedAt   *time.Time `gorm:"column:revoked_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (APIKeyModel) TableName() string { return "api_keys" }
+
+type APIKeyRepository struct{ db *gorm.DB }
+
+func NewAPIKeyRepository(db *gorm.DB) domainrepo.APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, k *entity.APIKey) (*entity.APIKey, error) {
+	m := APIKeyModel{
+		Name:        k.Name,
+		Prefix:      k.Prefix,
+		KeyHash:     k.KeyHash,
+		OwnerUserID: k.OwnerUserID,
+		Scopes:      strings.Join(k.Scopes, " "),
+		ExpiresAt:   k.ExpiresAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&m).Error; err != nil {
+		return nil, wrapDBError(err, "gorm Create api_keys", slog.String("name", k.Name))
+	}
+	return toAPIKeyEntity(m), nil
+}
+
+func (r *APIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	var m APIKeyModel
+	if err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, wrapDBError(err, "gorm First api_keys")
+	}
+	return toAPIKeyEntity(m), nil
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]*entity.APIKey, error) {
+	var ms []APIKeyModel
+	if err := r.db.WithContext(ctx).Order("id DESC").Find(&ms).Error; err != nil {
+		return nil, wrapDBError(err, "gorm Find api_keys")
+	}
+	out := make([]*entity.APIKey, 0, len(ms))
+	for _, m := range ms {
+		out = append(out, toAPIKeyEntity(m))
+	}
+	return out, nil
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, id int64, at time.Time) error {
+	res := r.db.WithContext(ctx).Model(&APIKeyModel{}).Where("id = ?", id).Update("revoked_at", gorm.Expr("COALESCE(revoked_at, ?)", at))
+	if res.Error != nil {
+		return wrapDBError(res.Error, "gorm Update api_keys.revoked_at", slog.Int64("id", id))
+	}
+	if res.RowsAffected == 0 {
+		var n int64
+		if err := r.db.WithContext(ctx).Model(&APIKeyModel{}).Where("id = ?", id).Count(&n).Error; err != nil {
+			return wrapDBError(err, "gorm Count api_keys", slog.Int64("id", id))
+		}
+		if n == 0 {
+			return notFoundError("api_key", id)
+		}
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&APIKeyModel{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error; err != nil {
+		return wrapDBError(err, "gorm Update api_keys.last_used_at", slog.Int64("id", id))
+	}
+	return nil
+}
+
+func toAPIKeyEntity(m APIKeyModel) *entity.APIKey {
+	return &entity.APIKey{
+		ID:          m.ID,
+		Name:        m.Name,
+		Prefix:      m.Prefix,
+		KeyHash:     m.KeyHash,
+		OwnerUserID: m.OwnerUserID,
+		Scopes:      strings.Fields(m.Scopes),
+		ExpiresAt:   m.ExpiresAt,
+		LastUsedAt:  m.LastUsedAt,
+		RevokedAt:   m.RevokedAt,
+		CreatedAt:   m.CreatedAt,
+	}
+}
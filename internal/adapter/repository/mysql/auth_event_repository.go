@@ -0,0 +1,138 @@
+package mysql
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+type AuthEventModel struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	Outcome    string    `gorm:"column:outcome;size:16;not null"`
+	Reason     string    `gorm:"column:reason;size:64;not null"`
+	Method     string    `gorm:"column:method;size:32;not null"`
+	Provider   string    `gorm:"column:provider;size:64;not null"`
+	Issuer     string    `gorm:"column:issuer;size:255;not null"`
+	Subject    string    `gorm:"column:subject;size:255;not null"`
+	UserID     *int64    `gorm:"column:user_id"`
+	Procedure  string    `gorm:"column:procedure;size:255;not null"`
+	IPAddress  string    `gorm:"column:ip_address;size:64;not null"`
+	UserAgent  string    `gorm:"column:user_agent;size:255;not null"`
+	OccurredAt time.Time `gorm:"column:occurred_at;not null"`
+}
+
+func (AuthEventModel) TableName() string { return "auth_events" }
+
+type AuthEventRepository struct{ db *gorm.DB }
+
+func NewAuthEventRepository(db *gorm.DB) domainrepo.AuthEventRepository {
+	return &AuthEventRepository{db: db}
+}
+
+func (r *AuthEventRepository) CreateBatch(ctx context.Context, events []*entity.AuthEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	ms := make([]AuthEventModel, 0, len(events))
+	for _, e := range events {
+		m := AuthEventModel{
+			Outcome:    e.Outcome,
+			Reason:     e.Reason,
+			Method:     e.Method,
+			Provider:   truncate(e.Provider, 64),
+			Issuer:     truncate(e.Issuer, 255),
+			Subject:    truncate(e.Subject, 255),
+			Procedure:  truncate(e.Procedure, 255),
+			IPAddress:  truncate(e.IP, 64),
+			UserAgent:  truncate(e.UserAgent, 255),
+			OccurredAt: e.OccurredAt,
+		}
+		if e.UserID > 0 {
+			uid := e.UserID
+			m.UserID = &uid
+		}
+		ms = append(ms, m)
+	}
+	err := r.db.WithContext(ctx).Create(&ms).Error
+	if err == nil {
+		for i := range ms {
+			events[i].ID = ms[i].ID
+		}
+		return nil
+	}
+	if len(ms) == 1 {
+		return wrapDBError(err, "gorm Create auth_events", slog.Int("count", 1))
+	}
+	// One bad row fails the whole multi-row INSERT; retry the rows one at a
+	// time so that the rest of the batch is still recorded.
+	var failed int
+	var firstErr error
+	for i := range ms {
+		ms[i].ID = 0
+		if err := r.db.WithContext(ctx).Create(&ms[i]).Error; err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed++
+			continue
+		}
+		events[i].ID = ms[i].ID
+	}
+	if failed > 0 {
+		return wrapDBError(firstErr, "gorm Create auth_events", slog.Int("count", len(ms)), slog.Int("failed", failed))
+	}
+	return nil
+}
+
+func (r *AuthEventRepository) Search(ctx context.Context, f domainrepo.AuthEventFilter) ([]*entity.AuthEvent, error) {
+	q := r.db.WithContext(ctx).Model(&AuthEventModel{})
+	if f.UserID > 0 {
+		q = q.Where("user_id = ?", f.UserID)
+	}
+	for _, c := range []struct{ col, v string }{
+		{"subject", f.Subject}, {"issuer", f.Issuer}, {"outcome", f.Outcome}, {"reason", f.Reason}, {"ip_address", f.IP},
+	} {
+		if c.v != "" {
+			q = q.Where(c.col+" = ?", c.v)
+		}
+	}
+	if !f.Since.IsZero() {
+		q = q.Where("occurred_at >= ?", f.Since)
+	}
+	if !f.Until.IsZero() {
+		q = q.Where("occurred_at < ?", f.Until)
+	}
+	if f.BeforeID > 0 {
+		q = q.Where("id < ?", f.BeforeID)
+	}
+	var ms []AuthEventModel
+	if err := q.Order("id DESC").Limit(f.Limit).Find(&ms).Error; err != nil {
+		return nil, wrapDBError(err, "gorm Find auth_events")
+	}
+	out := make([]*entity.AuthEvent, 0, len(ms))
+	for _, m := range ms {
+		e := &entity.AuthEvent{
+			ID:         m.ID,
+			Outcome:    m.Outcome,
+			Reason:     m.Reason,
+			Method:     m.Method,
+			Provider:   m.Provider,
+			Issuer:     m.Issuer,
+			Subject:    m.Subject,
+			Procedure:  m.Procedure,
+			IP:         m.IPAddress,
+			UserAgent:  m.UserAgent,
+			OccurredAt: m.OccurredAt,
+		}
+		if m.UserID != nil {
+			e.UserID = *m.UserID
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
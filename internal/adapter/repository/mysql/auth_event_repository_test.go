@@ -0,0 +1,59 @@
+package mysql_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/adapter/repository/mysql"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+	"github.com/xiao1203/go-onion-grpc-template/util/testhelper"
+)
+
+func TestAuthEventRepository_CreateBatch(t *testing.T) {
+	testhelper.Lock(t)
+	testhelper.EnsureTestDBEnv(t)
+	testDB := testhelper.OpenGormTestDB(t)
+	if err := testDB.Exec("DELETE FROM auth_events").Error; err != nil {
+		t.Fatalf("clean auth_events: %v", err)
+	}
+	repository := mysql.NewAuthEventRepository(testDB)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	t.Run("正常系: 不正なUTF-8や長すぎる値は置換・切り詰めて記録されること", func(t *testing.T) {
+		e := &entity.AuthEvent{Outcome: "failure", Reason: "expired", Method: "bearer",
+			Subject: strings.Repeat("日", 300), UserAgent: "curl\xff/8.0", IP: "192.0.2.1", OccurredAt: now}
+		if err := repository.CreateBatch(ctx, []*entity.AuthEvent{e}); err != nil {
+			t.Fatalf("CreateBatch() failed: %v", err)
+		}
+		got, err := repository.Search(ctx, domainrepo.AuthEventFilter{IP: "192.0.2.1", Limit: 1})
+		if err != nil || len(got) != 1 {
+			t.Fatalf("Search() = %v, %v", got, err)
+		}
+		if got[0].UserAgent != "curl\uFFFD/8.0" || got[0].Subject != strings.Repeat("日", 255) {
+			t.Fatalf("event = %+v", got[0])
+		}
+	})
+
+	t.Run("異常系: 1件が書き込めなくても残りのイベントは記録されること", func(t *testing.T) {
+		events := []*entity.AuthEvent{
+			{Outcome: "success", Method: "bearer", IP: "192.0.2.2", OccurredAt: now},
+			// outcome is VARCHAR(16) and not truncated; strict mode rejects the row.
+			{Outcome: strings.Repeat("x", 17), Method: "bearer", IP: "192.0.2.2", OccurredAt: now},
+			{Outcome: "success", Method: "bearer", IP: "192.0.2.2", OccurredAt: now},
+		}
+		if err := repository.CreateBatch(ctx, events); err == nil {
+			t.Fatal("CreateBatch() succeeded unexpectedly")
+		}
+		if events[0].ID == 0 || events[1].ID != 0 || events[2].ID == 0 {
+			t.Fatalf("IDs = %d, %d, %d", events[0].ID, events[1].ID, events[2].ID)
+		}
+		got, err := repository.Search(ctx, domainrepo.AuthEventFilter{IP: "192.0.2.2", Limit: 10})
+		if err != nil || len(got) != 2 {
+			t.Fatalf("Search() = %v, %v", got, err)
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package mysql
+
+import "strings"
+
+// truncate replaces invalid UTF-8 in s with U+FFFD and cuts it to at most n
+// characters. Values from clients (headers, token claims) may hold arbitrary
+// bytes; strict-mode MySQL rejects both invalid UTF-8 and a multi-byte
+// character split at a byte offset as an incorrect string value, and VARCHAR(n)
+// limits characters, not bytes.
+func truncate(s string, n int) string {
+	s = strings.ToValidUTF8(s, "\uFFFD")
+	runes := 0
+	for i := range s {
+		if runes == n {
+			return s[:i]
+		}
+		runes++
+	}
+	return s
+}
@@ -0,0 +1,35 @@
+package mysql
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		n    int
+		want string
+	}{
+		{"正常系: 上限以内の文字列はそのまま返ること", "curl/8.0", 255, "curl/8.0"},
+		{"正常系: ASCIIは上限の文字数で切られること", "abcdef", 3, "abc"},
+		{"正常系: マルチバイト文字は文字単位で切られること", "あいうえお", 3, "あいう"},
+		{"正常系: マルチバイト文字を途中で分割しないこと", strings.Repeat("a", 254) + "日本", 255, strings.Repeat("a", 254) + "日"},
+		{"正常系: 空文字列は空のまま返ること", "", 10, ""},
+		{"正常系: 不正なUTF-8は置換文字になること", "curl\xff\xfe/8", 255, "curl\uFFFD/8"},
+		{"正常系: 置換文字も1文字として数えること", "\xffabc", 2, "\uFFFDa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.s, tt.n)
+			if got != tt.want {
+				t.Fatalf("truncate() = %q, want %q", got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Fatalf("truncate() = %q is not valid UTF-8", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+type UserCredentialModel struct {
+	UserID            int64      `gorm:"primaryKey;column:user_id;autoIncrement:false"`
+	PasswordHash      string     `gorm:"column:password_hash;size:255;not null"`
+	FailedAttempts    int        `gorm:"column:failed_attempts;not null"`
+	LockedUntil       *time.Time `gorm:"column:locked_until"`
+	PasswordChangedAt time.Time  `gorm:"column:password_changed_at;not null"`
+	CreatedAt         time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (UserCredentialModel) TableName() string { return "user_credentials" }
+
+type PasswordResetTokenModel struct {
+	TokenHash string     `gorm:"primaryKey;column:token_hash;size:64"`
+	UserID    int64      `gorm:"column:user_id;not null"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;not null"`
+}
+
+func (PasswordResetTokenModel) TableName() string { return "password_reset_tokens" }
+
+type CredentialRepository struct{ db *gorm.DB }
+
+func NewCredentialRepository(db *gorm.DB) domainrepo.CredentialRepository {
+	return &CredentialRepository{db: db}
+}
+
+// credentialRow is user_credentials joined with the owning user.
+type credentialRow struct {
+	UserCredentialModel
+	Email string
+}
+
+func (r *CredentialRepository) Create(ctx context.Context, user *entity.User, passwordHash string, at time.Time) (*entity.Credential, error) {
+	m := UserCredentialModel{PasswordHash: passwordHash, PasswordChangedAt: at}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		u := UserModel{Email: user.Email, DisplayName: user.DisplayName}
+		if err := tx.Create(&u).Error; err != nil {
+			return ergo.Wrap(err, "gorm Create users")
+		}
+		m.UserID = u.ID
+		if err := tx.Create(&m).Error; err != nil {
+			return ergo.Wrap(err, "gorm Create user_credentials")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, ergo.WithCode(err, dbErrorCode(err))
+	}
+	return toCredentialEntity(credentialRow{UserCredentialModel: m, Email: user.Email}), nil
+}
+
+func (r *CredentialRepository) FindByEmail(ctx context.Context, email string) (*entity.Credential, error) {
+	return r.find(ctx, "u.email = ?", email)
+}
+
+func (r *CredentialRepository) FindByUserID(ctx context.Context, userID int64) (*entity.Credential, error) {
+	return r.find(ctx, "c.user_id = ?", userID)
+}
+
+func (r *CredentialRepository) find(ctx context.Context, cond string, arg any) (*entity.Credential, error) {
+	var row credentialRow
+	err := r.db.WithContext(ctx).Table("user_credentials c").
+		Joins("JOIN users u ON u.id = c.user_id").
+		Where(cond, arg).
+		Where("u.status = 'active' AND u.deleted_at IS NULL").
+		Select("c.*, u.email AS email").
+		Take(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, wrapDBError(err, "gorm Take user_credentials")
+	}
+	return toCredentialEntity(row), nil
+}
+
+func (r *CredentialRepository) RecordFailure(ctx context.Context, userID int64, maxFailures int, lockUntil time.Time) error {
+	// Raw SQL keeps the SET order: MySQL evaluates assignments left to right
+	// with the updated values, so locked_until must come before failed_attempts.
+	err := r.db.WithContext(ctx).Exec(
+		"UPDATE user_credentials SET "+
+			"locked_until = IF(failed_attempts + 1 >= ?, ?, locked_until), "+
+			"failed_attempts = IF(failed_attempts + 1 >= ?, 0, failed_attempts + 1), "+
+			"updated_at = ? WHERE user_id = ?",
+		maxFailures, lockUntil, maxFailures, time.Now(), userID,
+	).Error
+	if err != nil {
+		return wrapDBError(err, "gorm Exec user_credentials failure", slog.Int64("user_id", userID))
+	}
+	return nil
+}
+
+func (r *CredentialRepository) RecordSuccess(ctx context.Context, userID int64, at time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&UserCredentialModel{}).Where("user_id = ?", userID).Updates(map[string]any{
+			"failed_attempts": 0,
+			"locked_until":    nil,
+		}).Error; err != nil {
+			return ergo.Wrap(err, "gorm Updates user_credentials success", slog.Int64("user_id", userID))
+		}
+		if err := tx.Model(&UserModel{}).Where("id = ?", userID).Update("last_login_at", at).Error; err != nil {
+			return ergo.Wrap(err, "gorm Update users.last_login_at", slog.Int64("user_id", userID))
+		}
+		return nil
+	})
+	if err != nil {
+		return ergo.WithCode(err, dbErrorCode(err))
+	}
+	return nil
+}
+
+func (r *CredentialRepository) UpdatePassword(ctx context.Context, userID int64, passwordHash string, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&UserCredentialModel{}).Where("user_id = ?", userID).Updates(map[string]any{
+		"password_hash":       passwordHash,
+		"password_changed_at": at,
+		"failed_attempts":     0,
+		"locked_until":        nil,
+	}).Error
+	if err != nil {
+		return wrapDBError(err, "gorm Updates user_credentials password", slog.Int64("user_id", userID))
+	}
+	return nil
+}
+
+func (r *CredentialRepository) CreateResetToken(ctx context.Context, userID int64, tokenHash string, expiresAt, at time.Time) error {
+	m := PasswordResetTokenModel{TokenHash: tokenHash, UserID: userID, ExpiresAt: expiresAt, CreatedAt: at}
+	if err := r.db.WithContext(ctx).Create(&m).Error; err != nil {
+		return wrapDBError(err, "gorm Create password_reset_tokens", slog.Int64("user_id", userID))
+	}
+	return nil
+}
+
+func (r *CredentialRepository) ConsumeResetToken(ctx context.Context, tokenHash string, at time.Time) (int64, error) {
+	var userID int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&PasswordResetTokenModel{}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, at).
+			Update("used_at", at)
+		if res.Error != nil {
+			return ergo.Wrap(res.Error, "gorm Update password_reset_tokens")
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		var m PasswordResetTokenModel
+		if err := tx.Where("token_hash = ?", tokenHash).First(&m).Error; err != nil {
+			return ergo.Wrap(err, "gorm First password_reset_tokens")
+		}
+		userID = m.UserID
+		return nil
+	})
+	if err != nil {
+		return 0, ergo.WithCode(err, dbErrorCode(err))
+	}
+	return userID, nil
+}
+
+func toCredentialEntity(row credentialRow) *entity.Credential {
+	return &entity.Credential{
+		UserID:            row.UserID,
+		Email:             row.Email,
+		PasswordHash:      row.PasswordHash,
+		FailedAttempts:    row.FailedAttempts,
+		LockedUntil:       row.LockedUntil,
+		PasswordChangedAt: row.PasswordChangedAt,
+	}
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log/slog"
+	"net"
+	"strconv"
+
+	gomysql "github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+)
+
+// MySQL server error numbers translated by dbErrorCode.
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+const (
+	erDupEntry                = 1062
+	erDupEntryWithKeyName     = 1586
+	erLockWaitTimeout         = 1205
+	erLockDeadlock            = 1213
+	erNoReferencedRow         = 1216
+	erRowIsReferenced         = 1217
+	erRowIsReferenced2        = 1451
+	erNoReferencedRow2        = 1452
+	erConCount                = 1040
+	erServerShutdown          = 1053
+	erOptionPreventsStatement = 1290 // e.g. --read-only during a failover
+	erQueryInterrupted        = 1317
+	erQueryTimeout            = 3024
+)
+
+// wrapDBError wraps a GORM / driver error with msg and the app error code of
+// the failure (see dbErrorCode).
+func wrapDBError(err error, msg string, attrs ...slog.Attr) error {
+	return ergo.WithCode(ergo.Wrap(err, msg, attrs...), dbErrorCode(err))
+}
+
+// notFoundError is the NotFound error of a missing row. resource names the
+// entity (e.g. "sample") in the google.rpc.ResourceInfo sent to the client.
+func notFoundError(resource string, id int64) error {
+	return ergo.WithCode(ergo.New(resource+" not found", slog.Int64("id", id), apperr.Resource(resource, strconv.FormatInt(id, 10))), apperr.NotFound)
+}
+
+// dbErrorCode maps a GORM / MySQL failure to an app error code so that
+// expected database conditions are not reported as server bugs:
+//
+//   - duplicate key → Conflict
+//   - deadlock / lock wait timeout → Aborted (retry the transaction)
+//   - foreign key violation → FailedPrecondition
+//   - lost or refused connections, read-only server → Unavailable
+//   - query timeout / context deadline → DeadlineExceeded, context canceled → Canceled
+//   - missing row (gorm.ErrRecordNotFound) → NotFound
+//
+// Anything else is Internal.
+func dbErrorCode(err error) ergo.Code {
+	var me *gomysql.MySQLError
+	if errors.As(err, &me) {
+		switch me.Number {
+		case erDupEntry, erDupEntryWithKeyName:
+			return apperr.Conflict
+		case erLockDeadlock, erLockWaitTimeout:
+			return apperr.Aborted
+		case erNoReferencedRow, erNoReferencedRow2, erRowIsReferenced, erRowIsReferenced2:
+			return apperr.FailedPrecondition
+		case erConCount, erServerShutdown, erOptionPreventsStatement:
+			return apperr.Unavailable
+		case erQueryTimeout:
+			return apperr.DeadlineExceeded
+		case erQueryInterrupted:
+			return apperr.Canceled
+		}
+		return apperr.Internal
+	}
+	var netErr net.Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return apperr.NotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return apperr.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return apperr.Canceled
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, gomysql.ErrInvalidConn), errors.As(err, &netErr):
+		return apperr.Unavailable
+	}
+	return apperr.Internal
+}
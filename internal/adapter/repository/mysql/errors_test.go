@@ -0,0 +1,46 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"net"
+	"testing"
+
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/newmo-oss/ergo"
+	"gorm.io/gorm"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+)
+
+func TestDBErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ergo.Code
+	}{
+		{"正常系: 一意制約違反(1062)はConflictになること", &gomysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@example.com' for key 'uk_users_email'"}, apperr.Conflict},
+		{"正常系: デッドロック(1213)はAbortedになること", &gomysql.MySQLError{Number: 1213}, apperr.Aborted},
+		{"正常系: ロック待ちタイムアウト(1205)はAbortedになること", &gomysql.MySQLError{Number: 1205}, apperr.Aborted},
+		{"正常系: 外部キー違反(1452)はFailedPreconditionになること", &gomysql.MySQLError{Number: 1452}, apperr.FailedPrecondition},
+		{"正常系: 参照中の行の削除(1451)はFailedPreconditionになること", &gomysql.MySQLError{Number: 1451}, apperr.FailedPrecondition},
+		{"正常系: 接続数超過(1040)はUnavailableになること", &gomysql.MySQLError{Number: 1040}, apperr.Unavailable},
+		{"正常系: 実行時間超過(3024)はDeadlineExceededになること", &gomysql.MySQLError{Number: 3024}, apperr.DeadlineExceeded},
+		{"正常系: 切断された接続はUnavailableになること", driver.ErrBadConn, apperr.Unavailable},
+		{"正常系: 不正な接続はUnavailableになること", gomysql.ErrInvalidConn, apperr.Unavailable},
+		{"正常系: 接続拒否はUnavailableになること", &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "refused"}}, apperr.Unavailable},
+		{"正常系: コンテキストの期限切れはDeadlineExceededになること", context.DeadlineExceeded, apperr.DeadlineExceeded},
+		{"正常系: コンテキストのキャンセルはCanceledになること", context.Canceled, apperr.Canceled},
+		{"正常系: 行が無い場合はNotFoundになること", gorm.ErrRecordNotFound, apperr.NotFound},
+		{"正常系: 未知のMySQLエラーはInternalになること", &gomysql.MySQLError{Number: 1146}, apperr.Internal},
+		{"正常系: その他のエラーはInternalになること", ergo.New("boom"), apperr.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrapDBError(ergo.Wrap(tt.err, "gorm Create users"), "create user")
+			if got := ergo.CodeOf(err); got != tt.want {
+				t.Fatalf("code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+type ImpersonationLogModel struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement"`
+	ActorUserID  int64     `gorm:"column:actor_user_id;not null"`
+	ActorSubject string    `gorm:"column:actor_subject;size:255;not null"`
+	TargetUserID int64     `gorm:"column:target_user_id;not null"`
+	Procedure    string    `gorm:"column:procedure;size:255;not null"`
+	Reason       string    `gorm:"column:reason;size:255;not null"`
+	Allowed      bool      `gorm:"column:allowed;not null"`
+	DeniedReason string    `gorm:"column:denied_reason;size:255;not null"`
+	CreatedAt    time.Time `gorm:"column:created_at;not null"`
+}
+
+func (ImpersonationLogModel) TableName() string { return "impersonation_logs" }
+
+type ImpersonationLogRepository struct{ db *gorm.DB }
+
+func NewImpersonationLogRepository(db *gorm.DB) domainrepo.ImpersonationLogRepository {
+	return &ImpersonationLogRepository{db: db}
+}
+
+func (r *ImpersonationLogRepository) Create(ctx context.Context, log *entity.ImpersonationLog) error {
+	m := ImpersonationLogModel{
+		ActorUserID:  log.ActorUserID,
+		ActorSubject: log.ActorSubject,
+		TargetUserID: log.TargetUserID,
+		Procedure:    log.Procedure,
+		Reason:       log.Reason,
+		Allowed:      log.Allowed,
+		DeniedReason: truncate(log.DeniedReason, 255),
+		CreatedAt:    log.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&m).Error; err != nil {
+		return wrapDBError(err, "gorm Create impersonation_logs", slog.Int64("actor_user_id", log.ActorUserID))
+	}
+	log.ID = m.ID
+	return nil
+}
@@ -0,0 +1,61 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+type RoleRepository struct{ db *gorm.DB }
+
+func NewRoleRepository(db *gorm.DB) domainrepo.RoleRepository { return &RoleRepository{db: db} }
+
+func (r *RoleRepository) RolesForUser(ctx context.Context, userID int64) ([]string, error) {
+	roles, err := loadRoles(ctx, r.db, userID)
+	if err != nil {
+		return nil, wrapDBError(err, "gorm load roles", slog.Int64("user_id", userID))
+	}
+	return roles, nil
+}
+
+func (r *RoleRepository) Grant(ctx context.Context, userID int64, role string, at time.Time) error {
+	id, err := r.roleID(ctx, role)
+	if err != nil {
+		return err
+	}
+	m := UserRoleModel{UserID: userID, RoleID: id, CreatedAt: at}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&m).Error; err != nil {
+		return wrapDBError(err, "gorm Create user_roles", slog.Int64("user_id", userID), slog.String("role", role))
+	}
+	return nil
+}
+
+func (r *RoleRepository) Revoke(ctx context.Context, userID int64, role string) error {
+	id, err := r.roleID(ctx, role)
+	if err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND role_id = ?", userID, id).Delete(&UserRoleModel{}).Error; err != nil {
+		return wrapDBError(err, "gorm Delete user_roles", slog.Int64("user_id", userID), slog.String("role", role))
+	}
+	return nil
+}
+
+func (r *RoleRepository) roleID(ctx context.Context, role string) (int64, error) {
+	var m RoleModel
+	if err := r.db.WithContext(ctx).Where("name = ?", role).First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ergo.WithCode(ergo.New("role not found", slog.String("role", role), apperr.Resource("role", role)), apperr.NotFound)
+		}
+		return 0, wrapDBError(err, "gorm First roles", slog.String("role", role))
+	}
+	return m.ID, nil
+}
@@ -8,8 +8,6 @@ import (
 
     "gorm.io/gorm"
 
-    "github.com/newmo-oss/ergo"
-    "github.com/xiao1203/go-onion-grpc-template/internal/apperr"
     "github.com/xiao1203/go-onion-grpc-template/internal/domain"
     "github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
     domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
@@ -38,7 +36,7 @@ func (r *SampleRepository) Create(ctx context.Context, in *entity.Sample) (*enti
 		Count:   in.Count,
 	}
     if err := r.db.WithContext(ctx).Create(&m).Error; err != nil {
-        return nil, ergo.WithCode(ergo.Wrap(err, "gorm Create samples"), apperr.Internal)
+        return nil, wrapDBError(err, "gorm Create samples")
     }
 	out := *in
 	out.ID = m.ID
@@ -49,9 +47,9 @@ func (r *SampleRepository) Get(ctx context.Context, id int64) (*entity.Sample, e
 	var m SampleModel
     if err := r.db.WithContext(ctx).First(&m, id).Error; err != nil {
         if errors.Is(err, gorm.ErrRecordNotFound) {
-            return nil, nil
+            return nil, notFoundError("sample", id)
         }
-        return nil, ergo.WithCode(ergo.Wrap(err, "gorm First samples", slog.Int64("id", id)), apperr.Internal)
+        return nil, wrapDBError(err, "gorm First samples", slog.Int64("id", id))
     }
 	return &entity.Sample{
 		ID:      m.ID,
@@ -66,7 +64,7 @@ func (r *SampleRepository) List(ctx context.Context, p domain.ListParams) ([]*en
 	p = p.Sanitize()
 	q := r.db.WithContext(ctx).Order("id DESC").Offset(p.Offset).Limit(p.Limit)
     if err := q.Find(&rows).Error; err != nil {
-        return nil, ergo.WithCode(ergo.Wrap(err, "gorm Find samples"), apperr.Internal)
+        return nil, wrapDBError(err, "gorm Find samples")
     }
 	out := make([]*entity.Sample, 0, len(rows))
 	for _, m := range rows {
@@ -89,14 +87,19 @@ func (r *SampleRepository) Update(ctx context.Context, in *entity.Sample) (*enti
 		"updated_at": time.Now(),
 	}
     if err := r.db.WithContext(ctx).Model(&SampleModel{}).Where("id = ?", in.ID).Updates(updates).Error; err != nil {
-        return nil, ergo.WithCode(ergo.Wrap(err, "gorm Updates samples", slog.Int64("id", in.ID)), apperr.Internal)
+        return nil, wrapDBError(err, "gorm Updates samples", slog.Int64("id", in.ID))
     }
+    // RowsAffected is 0 for unchanged rows too, so Get reports a missing ID.
 	return r.Get(ctx, in.ID)
 }
 
 func (r *SampleRepository) Delete(ctx context.Context, id int64) error {
-    if err := r.db.WithContext(ctx).Delete(&SampleModel{}, id).Error; err != nil {
-        return ergo.WithCode(ergo.Wrap(err, "gorm Delete samples", slog.Int64("id", id)), apperr.Internal)
+    res := r.db.WithContext(ctx).Delete(&SampleModel{}, id)
+    if res.Error != nil {
+        return wrapDBError(res.Error, "gorm Delete samples", slog.Int64("id", id))
+    }
+    if res.RowsAffected == 0 {
+        return notFoundError("sample", id)
     }
     return nil
 }
@@ -7,7 +7,9 @@ import (
 	"github.com/go-testfixtures/testfixtures/v3"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/newmo-oss/ergo"
 	"github.com/xiao1203/go-onion-grpc-template/internal/adapter/repository/mysql"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
 	"github.com/xiao1203/go-onion-grpc-template/internal/domain"
 	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
 	"github.com/xiao1203/go-onion-grpc-template/util/testhelper"
@@ -79,9 +81,10 @@ func TestSampleRepository_Get(t *testing.T) {
 	tests := []struct {
 		name string // description of this test case
 		// Named input parameters for target function.
-		id      int64
-		want    *entity.Sample
-		wantErr bool
+		id       int64
+		want     *entity.Sample
+		wantErr  bool
+		wantCode ergo.Code
 	}{
 		{
 			name: "正常系: IDに対応するSampleデータを取得できること",
@@ -94,6 +97,12 @@ func TestSampleRepository_Get(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:     "異常系: 存在しないIDの場合はNotFoundになること",
+			id:       999,
+			wantErr:  true,
+			wantCode: apperr.NotFound,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -102,6 +111,9 @@ func TestSampleRepository_Get(t *testing.T) {
 				if !tt.wantErr {
 					t.Errorf("Get() failed: %v", gotErr)
 				}
+				if code := ergo.CodeOf(gotErr); code != tt.wantCode {
+					t.Errorf("Get() code = %v, want %v", code, tt.wantCode)
+				}
 				return
 			}
 			if tt.wantErr {
@@ -206,9 +218,10 @@ func TestSampleRepository_Update(t *testing.T) {
 	tests := []struct {
 		name string // description of this test case
 		// Named input parameters for receiver constructor.
-		in      *entity.Sample
-		want    *entity.Sample
-		wantErr bool
+		in       *entity.Sample
+		want     *entity.Sample
+		wantErr  bool
+		wantCode ergo.Code
 	}{
 		{
 			name: "正常系: 指定したIDのSampleレコードの更新に成功すること",
@@ -226,6 +239,17 @@ func TestSampleRepository_Update(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "異常系: 存在しないIDの場合はNotFoundになること",
+			in: &entity.Sample{
+				ID:      999,
+				Name:    "updated_name",
+				Content: "updated_content",
+				Count:   100,
+			},
+			wantErr:  true,
+			wantCode: apperr.NotFound,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -234,6 +258,9 @@ func TestSampleRepository_Update(t *testing.T) {
 				if !tt.wantErr {
 					t.Errorf("Update() failed: %v", gotErr)
 				}
+				if code := ergo.CodeOf(gotErr); code != tt.wantCode {
+					t.Errorf("Update() code = %v, want %v", code, tt.wantCode)
+				}
 				return
 			}
 			if tt.wantErr {
@@ -260,8 +287,9 @@ func TestSampleRepository_Delete(t *testing.T) {
 		// Named input parameters for receiver constructor.
 		db *gorm.DB
 		// Named input parameters for target function.
-		id      int64
-		wantErr bool
+		id       int64
+		wantErr  bool
+		wantCode ergo.Code
 	}{
 		{
 			name:    "正常系: 指定したIDのSampleレコードの削除に成功すること",
@@ -269,6 +297,20 @@ func TestSampleRepository_Delete(t *testing.T) {
 			id:      1,
 			wantErr: false,
 		},
+		{
+			name:     "異常系: 削除済みのIDの場合はNotFoundになること",
+			db:       testDB,
+			id:       1,
+			wantErr:  true,
+			wantCode: apperr.NotFound,
+		},
+		{
+			name:     "異常系: 存在しないIDの場合はNotFoundになること",
+			db:       testDB,
+			id:       999,
+			wantErr:  true,
+			wantCode: apperr.NotFound,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -277,6 +319,9 @@ func TestSampleRepository_Delete(t *testing.T) {
 				if !tt.wantErr {
 					t.Errorf("Delete() failed: %v", gotErr)
 				}
+				if code := ergo.CodeOf(gotErr); code != tt.wantCode {
+					t.Errorf("Delete() code = %v, want %v", code, tt.wantCode)
+				}
 				return
 			}
 			if tt.wantErr {
@@ -0,0 +1,105 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+type SessionModel struct {
+	ID        string     `gorm:"primaryKey;column:id;size:64"`
+	UserID    int64      `gorm:"column:user_id;not null"`
+	UserAgent *string    `gorm:"column:user_agent;size:255"`
+	IPAddress *string    `gorm:"column:ip_address;size:64"`
+	CSRFToken string     `gorm:"column:csrf_token;size:64;not null"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (SessionModel) TableName() string { return "sessions" }
+
+type SessionRepository struct{ db *gorm.DB }
+
+func NewSessionRepository(db *gorm.DB) domainrepo.SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, s *entity.Session) error {
+	m := SessionModel{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		UserAgent: optionalString(truncate(s.UserAgent, 255)),
+		IPAddress: optionalString(truncate(s.IPAddress, 64)),
+		CSRFToken: s.CSRFToken,
+		ExpiresAt: s.ExpiresAt,
+		CreatedAt: s.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&m).Error; err != nil {
+		return wrapDBError(err, "gorm Create sessions", slog.Int64("user_id", s.UserID))
+	}
+	return nil
+}
+
+func (r *SessionRepository) FindByID(ctx context.Context, id string) (*entity.Session, error) {
+	var m SessionModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, wrapDBError(err, "gorm First sessions")
+	}
+	return toSessionEntity(m), nil
+}
+
+func (r *SessionRepository) Extend(ctx context.Context, id string, expiresAt time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&SessionModel{}).Where("id = ? AND revoked_at IS NULL", id).Update("expires_at", expiresAt).Error; err != nil {
+		return wrapDBError(err, "gorm Update sessions.expires_at")
+	}
+	return nil
+}
+
+func (r *SessionRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&SessionModel{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", at).Error; err != nil {
+		return wrapDBError(err, "gorm Update sessions.revoked_at")
+	}
+	return nil
+}
+
+func (r *SessionRepository) RevokeAllForUser(ctx context.Context, userID int64, at time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&SessionModel{}).Where("user_id = ? AND revoked_at IS NULL", userID).Update("revoked_at", at).Error; err != nil {
+		return wrapDBError(err, "gorm Update sessions.revoked_at", slog.Int64("user_id", userID))
+	}
+	return nil
+}
+
+func toSessionEntity(m SessionModel) *entity.Session {
+	out := &entity.Session{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		CSRFToken: m.CSRFToken,
+		ExpiresAt: m.ExpiresAt,
+		RevokedAt: m.RevokedAt,
+		CreatedAt: m.CreatedAt,
+	}
+	if m.UserAgent != nil {
+		out.UserAgent = *m.UserAgent
+	}
+	if m.IPAddress != nil {
+		out.IPAddress = *m.IPAddress
+	}
+	return out
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
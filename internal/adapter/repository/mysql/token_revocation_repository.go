@@ -0,0 +1,80 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+type RevokedTokenModel struct {
+	JTI       string    `gorm:"primaryKey;column:jti;size:255"`
+	UserID    *int64    `gorm:"column:user_id"`
+	ExpiresAt time.Time `gorm:"column:expires_at;not null"`
+	RevokedAt time.Time `gorm:"column:revoked_at;not null"`
+}
+
+func (RevokedTokenModel) TableName() string { return "revoked_tokens" }
+
+type UserTokenRevocationModel struct {
+	UserID        int64     `gorm:"primaryKey;column:user_id;autoIncrement:false"`
+	RevokedBefore time.Time `gorm:"column:revoked_before;not null"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (UserTokenRevocationModel) TableName() string { return "user_token_revocations" }
+
+type TokenRevocationRepository struct{ db *gorm.DB }
+
+func NewTokenRevocationRepository(db *gorm.DB) domainrepo.TokenRevocationRepository {
+	return &TokenRevocationRepository{db: db}
+}
+
+func (r *TokenRevocationRepository) RevokeJTI(ctx context.Context, jti string, userID int64, expiresAt, at time.Time) error {
+	m := RevokedTokenModel{JTI: jti, ExpiresAt: expiresAt, RevokedAt: at}
+	if userID > 0 {
+		m.UserID = &userID
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&m).Error; err != nil {
+		return wrapDBError(err, "gorm Create revoked_tokens")
+	}
+	return nil
+}
+
+func (r *TokenRevocationRepository) IsJTIRevoked(ctx context.Context, jti string) (bool, error) {
+	var n int64
+	if err := r.db.WithContext(ctx).Model(&RevokedTokenModel{}).Where("jti = ?", jti).Count(&n).Error; err != nil {
+		return false, wrapDBError(err, "gorm Count revoked_tokens")
+	}
+	return n > 0, nil
+}
+
+func (r *TokenRevocationRepository) RevokeUserBefore(ctx context.Context, userID int64, before time.Time) error {
+	m := UserTokenRevocationModel{UserID: userID, RevokedBefore: before}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"revoked_before": gorm.Expr("GREATEST(revoked_before, VALUES(revoked_before))"),
+			"updated_at":     gorm.Expr("VALUES(updated_at)"),
+		}),
+	}).Create(&m).Error
+	if err != nil {
+		return wrapDBError(err, "gorm Upsert user_token_revocations", slog.Int64("user_id", userID))
+	}
+	return nil
+}
+
+func (r *TokenRevocationRepository) RevokedBefore(ctx context.Context, userID int64) (*time.Time, error) {
+	var m UserTokenRevocationModel
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, wrapDBError(err, "gorm First user_token_revocations", slog.Int64("user_id", userID))
+	}
+	return &m.RevokedBefore, nil
+}
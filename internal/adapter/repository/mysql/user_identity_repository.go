@@ -0,0 +1,114 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+type UserIdentityModel struct {
+	ID              int64      `gorm:"primaryKey;autoIncrement"`
+	UserID          int64      `gorm:"column:user_id;not null"`
+	Provider        string     `gorm:"column:provider;size:64;not null"`
+	Issuer          string     `gorm:"column:issuer;size:255;not null"`
+	Subject         string     `gorm:"column:subject;size:255;not null"`
+	EmailAtProvider *string    `gorm:"column:email_at_provider;size:255"`
+	ConnectedAt     time.Time  `gorm:"column:connected_at;not null"`
+	LastLoginAt     *time.Time `gorm:"column:last_login_at"`
+	CreatedAt       time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (UserIdentityModel) TableName() string { return "user_identities" }
+
+type UserIdentityRepository struct{ db *gorm.DB }
+
+func NewUserIdentityRepository(db *gorm.DB) domainrepo.UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+func (r *UserIdentityRepository) FindByIssuerSubject(ctx context.Context, issuer, subject string) (*entity.UserIdentity, error) {
+	var m UserIdentityModel
+	if err := r.db.WithContext(ctx).Where("issuer = ? AND subject = ?", issuer, subject).First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, wrapDBError(err, "gorm First user_identities", slog.String("issuer", issuer))
+	}
+	return toUserIdentityEntity(m), nil
+}
+
+func (r *UserIdentityRepository) Provision(ctx context.Context, user *entity.User, identity *entity.UserIdentity) (*entity.UserIdentity, error) {
+	now := time.Now()
+	m := UserIdentityModel{
+		Provider:    identity.Provider,
+		Issuer:      identity.Issuer,
+		Subject:     identity.Subject,
+		ConnectedAt: now,
+		LastLoginAt: &now,
+	}
+	if identity.EmailAtProvider != "" {
+		m.EmailAtProvider = &identity.EmailAtProvider
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		u := UserModel{Email: user.Email, DisplayName: user.DisplayName, PictureURL: user.PictureURL}
+		if err := tx.Create(&u).Error; err != nil {
+			return ergo.Wrap(err, "gorm Create users")
+		}
+		if err := tx.Model(&UserModel{}).Where("id = ?", u.ID).Update("last_login_at", now).Error; err != nil {
+			return ergo.Wrap(err, "gorm Update users.last_login_at")
+		}
+		m.UserID = u.ID
+		if err := tx.Create(&m).Error; err != nil {
+			return ergo.Wrap(err, "gorm Create user_identities")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, ergo.WithCode(err, dbErrorCode(err))
+	}
+	return toUserIdentityEntity(m), nil
+}
+
+func (r *UserIdentityRepository) TouchLogin(ctx context.Context, identity *entity.UserIdentity, emailAtProvider string, at time.Time) error {
+	updates := map[string]any{"last_login_at": at}
+	if emailAtProvider != "" {
+		updates["email_at_provider"] = emailAtProvider
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&UserIdentityModel{}).Where("id = ?", identity.ID).Updates(updates).Error; err != nil {
+			return ergo.Wrap(err, "gorm Updates user_identities", slog.Int64("id", identity.ID))
+		}
+		if err := tx.Model(&UserModel{}).Where("id = ?", identity.UserID).Update("last_login_at", at).Error; err != nil {
+			return ergo.Wrap(err, "gorm Update users.last_login_at", slog.Int64("user_id", identity.UserID))
+		}
+		return nil
+	})
+	if err != nil {
+		return ergo.WithCode(err, dbErrorCode(err))
+	}
+	return nil
+}
+
+func toUserIdentityEntity(m UserIdentityModel) *entity.UserIdentity {
+	out := &entity.UserIdentity{
+		ID:          m.ID,
+		UserID:      m.UserID,
+		Provider:    m.Provider,
+		Issuer:      m.Issuer,
+		Subject:     m.Subject,
+		ConnectedAt: m.ConnectedAt,
+		LastLoginAt: m.LastLoginAt,
+	}
+	if m.EmailAtProvider != nil {
+		out.EmailAtProvider = *m.EmailAtProvider
+	}
+	return out
+}
@@ -3,20 +3,21 @@ package mysql
 import (
     "context"
     "errors"
+    "time"
 
     "gorm.io/gorm"
 
-    "github.com/newmo-oss/ergo"
-    "github.com/xiao1203/go-onion-grpc-template/internal/apperr"
     "github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
     domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
 )
 
 type UserModel struct {
-	ID          int64  `gorm:"primaryKey;autoIncrement"`
-	Email       string `gorm:"column:email;uniqueIndex:uk_users_email;size:255;not null"`
-	DisplayName string `gorm:"column:display_name;size:255;not null"`
-	PictureURL  string `gorm:"column:picture_url;size:512"`
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	Email       string    `gorm:"column:email;uniqueIndex:uk_users_email;size:255;not null"`
+	DisplayName string    `gorm:"column:display_name;size:255;not null"`
+	PictureURL  string    `gorm:"column:picture_url;size:512"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
 }
 
 func (UserModel) TableName() string { return "users" }
@@ -30,8 +31,9 @@ type RoleModel struct {
 func (RoleModel) TableName() string { return "roles" }
 
 type UserRoleModel struct {
-	UserID int64 `gorm:"column:user_id;primaryKey"`
-	RoleID int64 `gorm:"column:role_id;primaryKey"`
+	UserID    int64     `gorm:"column:user_id;primaryKey"`
+	RoleID    int64     `gorm:"column:role_id;primaryKey"`
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
 }
 
 func (UserRoleModel) TableName() string { return "user_roles" }
@@ -44,13 +46,13 @@ func (r *UserRepository) FindByID(ctx context.Context, id int64) (*entity.User,
 	var u UserModel
     if err := r.db.WithContext(ctx).First(&u, id).Error; err != nil {
         if errors.Is(err, gorm.ErrRecordNotFound) {
-            return nil, nil
+            return nil, notFoundError("user", id)
         }
-        return nil, ergo.WithCode(ergo.Wrap(err, "gorm First users"), apperr.Internal)
+        return nil, wrapDBError(err, "gorm First users")
     }
-	roles, err := r.loadRoles(ctx, id)
+	roles, err := loadRoles(ctx, r.db, id)
     if err != nil {
-        return nil, ergo.WithCode(ergo.Wrap(err, "gorm load roles"), apperr.Internal)
+        return nil, wrapDBError(err, "gorm load roles")
     }
 	return &entity.User{
 		ID:          u.ID,
@@ -66,15 +68,15 @@ func (r *UserRepository) UpdateProfile(ctx context.Context, id int64, displayNam
         "display_name": displayName,
         "picture_url":  pictureURL,
     }).Error; err != nil {
-        return nil, ergo.WithCode(ergo.Wrap(err, "gorm Updates users"), apperr.Internal)
+        return nil, wrapDBError(err, "gorm Updates users")
     }
 	return r.FindByID(ctx, id)
 }
 
-func (r *UserRepository) loadRoles(ctx context.Context, userID int64) ([]string, error) {
+func loadRoles(ctx context.Context, db *gorm.DB, userID int64) ([]string, error) {
 	type row struct{ Name string }
 	var rows []row
-	q := r.db.WithContext(ctx).Table("user_roles ur").
+	q := db.WithContext(ctx).Table("user_roles ur").
 		Joins("JOIN roles r ON r.id = ur.role_id").
 		Where("ur.user_id = ?", userID).
 		Select("r.name as name")
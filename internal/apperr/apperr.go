@@ -1,6 +1,8 @@
 package apperr
 
 import (
+    "errors"
+
     "connectrpc.com/connect"
     "github.com/newmo-oss/ergo"
 )
@@ -8,43 +10,78 @@ import (
 // アプリ内で共通利用するエラーコード定義
 var (
     // 認証関連
-    Unauthenticated   = ergo.NewCode("Unauthenticated", "unauthenticated")
-    PermissionDenied  = ergo.NewCode("PermissionDenied", "permission denied")
+    Unauthenticated    = ergo.NewCode("Unauthenticated", "unauthenticated")
+    PermissionDenied   = ergo.NewCode("PermissionDenied", "permission denied")
 
     // バリデーション/入力
-    InvalidArgument   = ergo.NewCode("InvalidArgument", "invalid argument")
+    InvalidArgument    = ergo.NewCode("InvalidArgument", "invalid argument")
 
     // リソース関連
-    NotFound          = ergo.NewCode("NotFound", "not found")
-    Conflict          = ergo.NewCode("Conflict", "conflict")
+    NotFound           = ergo.NewCode("NotFound", "not found")
+    Conflict           = ergo.NewCode("Conflict", "conflict")
+    OutOfRange         = ergo.NewCode("OutOfRange", "out of range")
+
+    // 状態・並行性（Aborted はリトライ可能、FailedPrecondition は状態が変わるまで不可）
+    FailedPrecondition = ergo.NewCode("FailedPrecondition", "failed precondition")
+    Aborted            = ergo.NewCode("Aborted", "aborted")
+    ResourceExhausted  = ergo.NewCode("ResourceExhausted", "resource exhausted")
+
+    // 通信・実行時間（Unavailable は一時的な障害でリトライ可能）
+    Unavailable        = ergo.NewCode("Unavailable", "unavailable")
+    DeadlineExceeded   = ergo.NewCode("DeadlineExceeded", "deadline exceeded")
+    Canceled           = ergo.NewCode("Canceled", "canceled")
 
     // その他
-    Internal          = ergo.NewCode("Internal", "internal error")
+    Unimplemented      = ergo.NewCode("Unimplemented", "unimplemented")
+    DataLoss           = ergo.NewCode("DataLoss", "data loss")
+    Internal           = ergo.NewCode("Internal", "internal error")
 )
 
 // Connectのステータスコードに変換
+// エラー詳細（google.rpc.ErrorInfo など。details.go 参照）も付与する
+// クライアントへのメッセージは redact.go 参照（内部エラーは汎用メッセージ + error_id）
 func ToConnect(err error) error {
     if err == nil {
         return nil
     }
-    code := ergo.CodeOf(err)
-    if code.IsZero() {
-        return connect.NewError(connect.CodeInternal, err)
+    var ce *connect.Error
+    if errors.As(err, &ce) {
+        return redactConnect(ce)
     }
+    return redact(err)
+}
 
+func connectCode(code ergo.Code) connect.Code {
     switch code {
     case Unauthenticated:
-        return connect.NewError(connect.CodeUnauthenticated, err)
+        return connect.CodeUnauthenticated
     case PermissionDenied:
-        return connect.NewError(connect.CodePermissionDenied, err)
+        return connect.CodePermissionDenied
     case InvalidArgument:
-        return connect.NewError(connect.CodeInvalidArgument, err)
+        return connect.CodeInvalidArgument
     case NotFound:
-        return connect.NewError(connect.CodeNotFound, err)
+        return connect.CodeNotFound
     case Conflict:
-        return connect.NewError(connect.CodeAlreadyExists, err)
+        return connect.CodeAlreadyExists
+    case OutOfRange:
+        return connect.CodeOutOfRange
+    case FailedPrecondition:
+        return connect.CodeFailedPrecondition
+    case Aborted:
+        return connect.CodeAborted
+    case ResourceExhausted:
+        return connect.CodeResourceExhausted
+    case Unavailable:
+        return connect.CodeUnavailable
+    case DeadlineExceeded:
+        return connect.CodeDeadlineExceeded
+    case Canceled:
+        return connect.CodeCanceled
+    case Unimplemented:
+        return connect.CodeUnimplemented
+    case DataLoss:
+        return connect.CodeDataLoss
     default:
-        return connect.NewError(connect.CodeInternal, err)
+        return connect.CodeInternal
     }
 }
-
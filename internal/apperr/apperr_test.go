@@ -0,0 +1,179 @@
+package apperr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+)
+
+// detailsOf decodes the details of a connect error by type.
+func detailsOf(t *testing.T, err error) map[string]any {
+	t.Helper()
+	var ce *connect.Error
+	if !errors.As(err, &ce) {
+		t.Fatalf("not a connect error: %v", err)
+	}
+	out := map[string]any{}
+	for _, d := range ce.Details() {
+		v, derr := d.Value()
+		if derr != nil {
+			t.Fatalf("decode detail %s: %v", d.Type(), derr)
+		}
+		out[d.Type()] = v
+	}
+	return out
+}
+
+func TestToConnect(t *testing.T) {
+	tests := []struct {
+		name       string
+		code       ergo.Code
+		want       connect.Code
+		wantReason string
+	}{
+		{"正常系: Unauthenticatedはunauthenticatedになること", apperr.Unauthenticated, connect.CodeUnauthenticated, "UNAUTHENTICATED"},
+		{"正常系: PermissionDeniedはpermission_deniedになること", apperr.PermissionDenied, connect.CodePermissionDenied, "PERMISSION_DENIED"},
+		{"正常系: InvalidArgumentはinvalid_argumentになること", apperr.InvalidArgument, connect.CodeInvalidArgument, "INVALID_ARGUMENT"},
+		{"正常系: NotFoundはnot_foundになること", apperr.NotFound, connect.CodeNotFound, "NOT_FOUND"},
+		{"正常系: Conflictはalready_existsになること", apperr.Conflict, connect.CodeAlreadyExists, "CONFLICT"},
+		{"正常系: FailedPreconditionはfailed_preconditionになること", apperr.FailedPrecondition, connect.CodeFailedPrecondition, "FAILED_PRECONDITION"},
+		{"正常系: Abortedはabortedになること", apperr.Aborted, connect.CodeAborted, "ABORTED"},
+		{"正常系: Unavailableはunavailableになること", apperr.Unavailable, connect.CodeUnavailable, "UNAVAILABLE"},
+		{"正常系: DeadlineExceededはdeadline_exceededになること", apperr.DeadlineExceeded, connect.CodeDeadlineExceeded, "DEADLINE_EXCEEDED"},
+		{"正常系: Canceledはcanceledになること", apperr.Canceled, connect.CodeCanceled, "CANCELED"},
+		{"正常系: ResourceExhaustedはresource_exhaustedになること", apperr.ResourceExhausted, connect.CodeResourceExhausted, "RESOURCE_EXHAUSTED"},
+		{"正常系: OutOfRangeはout_of_rangeになること", apperr.OutOfRange, connect.CodeOutOfRange, "OUT_OF_RANGE"},
+		{"正常系: Unimplementedはunimplementedになること", apperr.Unimplemented, connect.CodeUnimplemented, "UNIMPLEMENTED"},
+		{"正常系: DataLossはdata_lossになること", apperr.DataLoss, connect.CodeDataLoss, "DATA_LOSS"},
+		{"正常系: コードが無い場合はinternalになること", ergo.Code{}, connect.CodeInternal, "INTERNAL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ergo.New("boom")
+			if !tt.code.IsZero() {
+				err = ergo.WithCode(err, tt.code)
+			}
+			got := apperr.ToConnect(err)
+			if connect.CodeOf(got) != tt.want {
+				t.Fatalf("code = %v, want %v", connect.CodeOf(got), tt.want)
+			}
+			info, _ := detailsOf(t, got)["google.rpc.ErrorInfo"].(*errdetails.ErrorInfo)
+			if info == nil || info.GetReason() != tt.wantReason || info.GetDomain() != apperr.Domain {
+				t.Fatalf("ErrorInfo = %v", info)
+			}
+		})
+	}
+
+	t.Run("正常系: nilはnilのままであること", func(t *testing.T) {
+		if apperr.ToConnect(nil) != nil {
+			t.Fatal("want nil")
+		}
+	})
+
+	t.Run("正常系: 属性から詳細が組み立てられること", func(t *testing.T) {
+		base := ergo.New("password is too short",
+			apperr.Reason(apperr.ReasonPasswordTooWeak),
+			apperr.FieldViolation("password", "must be at least 8 characters"),
+			apperr.Metadata("min_length", "8"),
+		)
+		err := ergo.WithCode(ergo.Wrap(base, "sign up",
+			apperr.FieldViolation("email", "must be a valid email address"),
+			apperr.Resource("user", "alice@example.com"),
+			apperr.RetryAfter(90*time.Second),
+			apperr.LocalizedMessage("ja-JP", "パスワードが短すぎます"),
+		), apperr.InvalidArgument)
+
+		d := detailsOf(t, apperr.ToConnect(err))
+		info := d["google.rpc.ErrorInfo"].(*errdetails.ErrorInfo)
+		if info.GetReason() != "PASSWORD_TOO_WEAK" || info.GetMetadata()["min_length"] != "8" {
+			t.Fatalf("ErrorInfo = %v", info)
+		}
+		br := d["google.rpc.BadRequest"].(*errdetails.BadRequest)
+		if v := br.GetFieldViolations(); len(v) != 2 || v[0].GetField() != "email" || v[1].GetField() != "password" {
+			t.Fatalf("BadRequest = %v", br)
+		}
+		if ri := d["google.rpc.ResourceInfo"].(*errdetails.ResourceInfo); ri.GetResourceType() != "user" || ri.GetResourceName() != "alice@example.com" {
+			t.Fatalf("ResourceInfo = %v", ri)
+		}
+		if rt := d["google.rpc.RetryInfo"].(*errdetails.RetryInfo); rt.GetRetryDelay().AsDuration() != 90*time.Second {
+			t.Fatalf("RetryInfo = %v", rt)
+		}
+		if lm := d["google.rpc.LocalizedMessage"].(*errdetails.LocalizedMessage); lm.GetLocale() != "ja-JP" || lm.GetMessage() != "パスワードが短すぎます" {
+			t.Fatalf("LocalizedMessage = %v", lm)
+		}
+	})
+
+	t.Run("正常系: 子のReasonが親より優先されること", func(t *testing.T) {
+		err := ergo.Wrap(ergo.New("revoked", apperr.Reason(apperr.ReasonTokenRevoked)), "login", apperr.Reason(apperr.ReasonInvalidCredentials))
+		if got := apperr.ReasonOf(ergo.WithCode(err, apperr.Unauthenticated)); got != apperr.ReasonInvalidCredentials {
+			t.Fatalf("reason = %v", got)
+		}
+	})
+}
+
+func TestToConnect_Redaction(t *testing.T) {
+	t.Run("正常系: 内部エラーは汎用メッセージとerror_idだけを返すこと", func(t *testing.T) {
+		cause := ergo.Wrap(ergo.New("Error 1146: Table 'app.samples' doesn't exist"), "gorm First samples")
+		err := apperr.ToConnect(cause)
+		var ce *connect.Error
+		if !errors.As(err, &ce) {
+			t.Fatalf("not a connect error: %v", err)
+		}
+		id := apperr.ErrorIDOf(err)
+		if id == "" {
+			t.Fatal("error_id is empty")
+		}
+		if want := "internal error (error_id: " + id + ")"; ce.Message() != want {
+			t.Fatalf("message = %q, want %q", ce.Message(), want)
+		}
+		info := detailsOf(t, err)["google.rpc.ErrorInfo"].(*errdetails.ErrorInfo)
+		if info.GetMetadata()["error_id"] != id {
+			t.Fatalf("ErrorInfo = %v", info)
+		}
+		if apperr.CauseOf(err) != cause {
+			t.Fatalf("cause = %v", apperr.CauseOf(err))
+		}
+		if got := apperr.ErrorIDOf(apperr.ToConnect(errors.New("other"))); got == id {
+			t.Fatal("error_id should differ per error")
+		}
+	})
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"正常系: ClientMessageの無いドメインエラーはコードの汎用メッセージになること",
+			ergo.WithCode(ergo.Wrap(ergo.New("duplicate entry 'a@example.com'"), "gorm Create users"), apperr.Conflict), "conflict"},
+		{"正常系: ClientMessageを付けたドメインエラーはそのメッセージになること",
+			ergo.WithCode(ergo.New("account is locked", apperr.ClientMessage("account is locked")), apperr.Unauthenticated), "account is locked"},
+		{"正常系: 内部エラーのClientMessageは無視されること",
+			ergo.WithCode(ergo.New("boom", apperr.ClientMessage("leak")), apperr.Internal), "internal error"},
+		{"正常系: 明示的なconnectエラーはそのまま返ること",
+			connect.NewError(connect.CodeUnimplemented, errors.New("not implemented")), "not implemented"},
+		{"正常系: 内部コードのconnectエラーは秘匿されること",
+			connect.NewError(connect.CodeUnknown, errors.New("dial tcp 10.0.0.1:3306")), "internal error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := apperr.ToConnect(tt.err)
+			var ce *connect.Error
+			if !errors.As(err, &ce) {
+				t.Fatalf("not a connect error: %v", err)
+			}
+			if !strings.HasPrefix(ce.Message(), tt.want) {
+				t.Fatalf("message = %q, want prefix %q", ce.Message(), tt.want)
+			}
+			if again := apperr.ToConnect(err); again != err {
+				t.Fatalf("ToConnect should be idempotent: %v", again)
+			}
+		})
+	}
+}
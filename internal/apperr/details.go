@@ -0,0 +1,212 @@
+package apperr
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	apperrv1 "github.com/xiao1203/go-onion-grpc-template/gen/apperr/v1"
+)
+
+// Application reasons (proto/apperr/v1/apperr.proto), re-exported so that
+// usecases do not depend on generated code.
+const (
+	ReasonInvalidCredentials     = apperrv1.Reason_INVALID_CREDENTIALS
+	ReasonAccountLocked          = apperrv1.Reason_ACCOUNT_LOCKED
+	ReasonTokenRevoked           = apperrv1.Reason_TOKEN_REVOKED
+	ReasonStepUpRequired         = apperrv1.Reason_STEP_UP_REQUIRED
+	ReasonResetTokenInvalid      = apperrv1.Reason_RESET_TOKEN_INVALID
+	ReasonIdentityEmailInUse     = apperrv1.Reason_IDENTITY_EMAIL_IN_USE
+	ReasonEmailAlreadyRegistered = apperrv1.Reason_EMAIL_ALREADY_REGISTERED
+	ReasonPasswordTooWeak        = apperrv1.Reason_PASSWORD_TOO_WEAK
+	ReasonImpersonationDenied    = apperrv1.Reason_IMPERSONATION_DENIED
+)
+
+// Domain is the google.rpc.ErrorInfo domain of errors returned by ToConnect.
+var Domain = "go-onion-grpc-template"
+
+// Attribute keys of error details. They are ordinary ergo attributes, so the
+// details are logged along with the error.
+const (
+	reasonKey      = "apperr.reason"
+	metadataPrefix = "apperr.metadata."
+	fieldPrefix    = "apperr.field_violation."
+	resourceKey    = "apperr.resource"
+	retryAfterKey  = "apperr.retry_after"
+	localizedKey   = "apperr.localized_message"
+)
+
+// FieldViolationDetail is one invalid request field (google.rpc.BadRequest).
+type FieldViolationDetail struct {
+	Field       string
+	Description string
+}
+
+// ResourceDetail names the resource an error is about (google.rpc.ResourceInfo).
+type ResourceDetail struct {
+	Type string
+	Name string
+}
+
+// LocalizedMessageDetail is a message safe to show to end users (google.rpc.LocalizedMessage).
+type LocalizedMessageDetail struct {
+	Locale  string
+	Message string
+}
+
+// Reason sets the ErrorInfo reason clients switch on. Without it ToConnect
+// uses the generic reason of the error code.
+//
+//	ergo.WithCode(ergo.New("account locked", apperr.Reason(apperr.ReasonAccountLocked)), apperr.Unauthenticated)
+func Reason(r apperrv1.Reason) slog.Attr {
+	return slog.Any(reasonKey, r)
+}
+
+// Metadata adds a key/value pair to ErrorInfo.metadata. Only use it for
+// values that may be shown to the client.
+func Metadata(key, value string) slog.Attr {
+	return slog.String(metadataPrefix+key, value)
+}
+
+// FieldViolation reports an invalid request field (e.g. "email" or
+// "profile.display_name"). Several violations can be attached to one error.
+func FieldViolation(field, description string) slog.Attr {
+	return slog.Any(fieldPrefix+field, FieldViolationDetail{Field: field, Description: description})
+}
+
+// Resource names the resource that was not found or conflicted, e.g.
+// Resource("user", "alice@example.com").
+func Resource(typ, name string) slog.Attr {
+	return slog.Any(resourceKey, ResourceDetail{Type: typ, Name: name})
+}
+
+// RetryAfter tells the client when the request may be retried.
+func RetryAfter(d time.Duration) slog.Attr {
+	return slog.Duration(retryAfterKey, d)
+}
+
+// LocalizedMessage attaches a user-facing message in the given BCP 47 locale.
+func LocalizedMessage(locale, message string) slog.Attr {
+	return slog.Any(localizedKey, LocalizedMessageDetail{Locale: locale, Message: message})
+}
+
+// ReasonOf returns the reason attached to err, or the generic reason of its code.
+func ReasonOf(err error) apperrv1.Reason {
+	for attr := range ergo.AttrsAll(err) {
+		if attr.Key == reasonKey {
+			if r, ok := attr.Value.Any().(apperrv1.Reason); ok {
+				return r
+			}
+		}
+	}
+	return reasonForCode(ergo.CodeOf(err))
+}
+
+func reasonForCode(code ergo.Code) apperrv1.Reason {
+	switch code {
+	case Unauthenticated:
+		return apperrv1.Reason_UNAUTHENTICATED
+	case PermissionDenied:
+		return apperrv1.Reason_PERMISSION_DENIED
+	case InvalidArgument:
+		return apperrv1.Reason_INVALID_ARGUMENT
+	case NotFound:
+		return apperrv1.Reason_NOT_FOUND
+	case Conflict:
+		return apperrv1.Reason_CONFLICT
+	case OutOfRange:
+		return apperrv1.Reason_OUT_OF_RANGE
+	case FailedPrecondition:
+		return apperrv1.Reason_FAILED_PRECONDITION
+	case Aborted:
+		return apperrv1.Reason_ABORTED
+	case ResourceExhausted:
+		return apperrv1.Reason_RESOURCE_EXHAUSTED
+	case Unavailable:
+		return apperrv1.Reason_UNAVAILABLE
+	case DeadlineExceeded:
+		return apperrv1.Reason_DEADLINE_EXCEEDED
+	case Canceled:
+		return apperrv1.Reason_CANCELED
+	case Unimplemented:
+		return apperrv1.Reason_UNIMPLEMENTED
+	case DataLoss:
+		return apperrv1.Reason_DATA_LOSS
+	default:
+		return apperrv1.Reason_INTERNAL
+	}
+}
+
+// Details builds the google.rpc details of err: always an ErrorInfo, then
+// BadRequest, ResourceInfo, RetryInfo and LocalizedMessage when attached.
+func Details(err error) []proto.Message {
+	info := &errdetails.ErrorInfo{Reason: ReasonOf(err).String(), Domain: Domain}
+	var (
+		violations []*errdetails.BadRequest_FieldViolation
+		resource   *errdetails.ResourceInfo
+		retry      *errdetails.RetryInfo
+		localized  *errdetails.LocalizedMessage
+	)
+	for attr := range ergo.AttrsAll(err) {
+		switch v := attr.Value.Any().(type) {
+		case FieldViolationDetail:
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: v.Field, Description: v.Description})
+		case ResourceDetail:
+			resource = &errdetails.ResourceInfo{ResourceType: v.Type, ResourceName: v.Name}
+		case LocalizedMessageDetail:
+			localized = &errdetails.LocalizedMessage{Locale: v.Locale, Message: v.Message}
+		case time.Duration:
+			if attr.Key == retryAfterKey {
+				retry = &errdetails.RetryInfo{RetryDelay: durationpb.New(v)}
+			}
+		case string:
+			if k, ok := strings.CutPrefix(attr.Key, metadataPrefix); ok {
+				if info.Metadata == nil {
+					info.Metadata = map[string]string{}
+				}
+				info.Metadata[k] = v
+			}
+		}
+	}
+	out := []proto.Message{info}
+	if len(violations) > 0 {
+		// AttrsAll walks the error chain; sort for a stable response.
+		sort.Slice(violations, func(i, j int) bool { return violations[i].Field < violations[j].Field })
+		out = append(out, &errdetails.BadRequest{FieldViolations: violations})
+	}
+	if resource != nil {
+		out = append(out, resource)
+	}
+	if retry != nil {
+		out = append(out, retry)
+	}
+	if localized != nil {
+		out = append(out, localized)
+	}
+	return out
+}
+
+// withDetails attaches Details(err) to ce, adding the error ID of internal
+// errors to ErrorInfo.metadata.
+func withDetails(ce *connect.Error, err error, errorID string) *connect.Error {
+	details := Details(err)
+	if info, ok := details[0].(*errdetails.ErrorInfo); ok && errorID != "" {
+		if info.Metadata == nil {
+			info.Metadata = map[string]string{}
+		}
+		info.Metadata["error_id"] = errorID
+	}
+	for _, m := range details {
+		if d, derr := connect.NewErrorDetail(m); derr == nil {
+			ce.AddDetail(d)
+		}
+	}
+	return ce
+}
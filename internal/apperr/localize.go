@@ -0,0 +1,146 @@
+package apperr
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	"golang.org/x/text/language"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// locales holds one bundle per language, named by BCP 47 tag (ja.json,
+// en.json, ...). Each bundle maps an apperr.v1.Reason value name to a
+// message; "{key}" is replaced with ErrorInfo.metadata[key].
+//
+//go:embed locales/*.json
+var locales embed.FS
+
+// DefaultLanguage is the language used when Accept-Language matches no bundle.
+const DefaultLanguage = "en"
+
+// Catalog is a set of localized messages keyed by error reason.
+type Catalog struct {
+	tags     []language.Tag
+	bundles  []map[string]string
+	matcher  language.Matcher
+	fallback int
+}
+
+// LoadCatalog reads every <tag>.json bundle in fsys. fallback names the
+// bundle used for unmatched languages and for reasons a bundle lacks.
+func LoadCatalog(fsys fs.FS, fallback string) (*Catalog, error) {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{fallback: -1}
+	for _, name := range names {
+		tag, err := language.Parse(strings.TrimSuffix(path.Base(name), ".json"))
+		if err != nil {
+			return nil, ergo.Wrap(err, "apperr: bundle is not named by a language tag", slog.String("file", name))
+		}
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, ergo.Wrap(err, "apperr: read bundle", slog.String("file", name))
+		}
+		var msgs map[string]string
+		if err := json.Unmarshal(b, &msgs); err != nil {
+			return nil, ergo.Wrap(err, "apperr: parse bundle", slog.String("file", name))
+		}
+		if tag.String() == fallback {
+			c.fallback = len(c.tags)
+		}
+		c.tags = append(c.tags, tag)
+		c.bundles = append(c.bundles, msgs)
+	}
+	if c.fallback < 0 {
+		return nil, ergo.New("apperr: no bundle for the fallback language", slog.String("language", fallback))
+	}
+	// The first tag is what the matcher returns when nothing matches.
+	c.tags[0], c.tags[c.fallback] = c.tags[c.fallback], c.tags[0]
+	c.bundles[0], c.bundles[c.fallback] = c.bundles[c.fallback], c.bundles[0]
+	c.fallback = 0
+	c.matcher = language.NewMatcher(c.tags)
+	return c, nil
+}
+
+var defaultCatalog = sync.OnceValue(func() *Catalog {
+	sub, err := fs.Sub(locales, "locales")
+	if err == nil {
+		var c *Catalog
+		if c, err = LoadCatalog(sub, DefaultLanguage); err == nil {
+			return c
+		}
+	}
+	panic(err)
+})
+
+// DefaultCatalog returns the catalog built from the bundles in locales/.
+func DefaultCatalog() *Catalog { return defaultCatalog() }
+
+var placeholder = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// Message returns the message for reason in the language that best matches
+// acceptLanguage (an Accept-Language header value), and that language's tag.
+func (c *Catalog) Message(acceptLanguage, reason string, metadata map[string]string) (locale, message string, ok bool) {
+	prefs, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	_, i, _ := c.matcher.Match(prefs...)
+	msg, ok := c.bundles[i][reason]
+	if !ok {
+		i = c.fallback
+		if msg, ok = c.bundles[i][reason]; !ok {
+			return "", "", false
+		}
+	}
+	msg = placeholder.ReplaceAllStringFunc(msg, func(m string) string {
+		if v, ok := metadata[m[1:len(m)-1]]; ok {
+			return v
+		}
+		return m
+	})
+	return c.tags[i].String(), msg, true
+}
+
+// Localize attaches a google.rpc.LocalizedMessage for the ErrorInfo reason of
+// a connect error built by ToConnect. Errors that already carry a
+// LocalizedMessage (see LocalizedMessage) or no ErrorInfo of this Domain are
+// returned unchanged; the code and the ErrorInfo are never modified.
+func (c *Catalog) Localize(err error, acceptLanguage string) error {
+	var ce *connect.Error
+	if !errors.As(err, &ce) {
+		return err
+	}
+	var info *errdetails.ErrorInfo
+	for _, d := range ce.Details() {
+		v, derr := d.Value()
+		if derr != nil {
+			continue
+		}
+		switch v := v.(type) {
+		case *errdetails.LocalizedMessage:
+			return err
+		case *errdetails.ErrorInfo:
+			info = v
+		}
+	}
+	if info == nil || info.GetDomain() != Domain {
+		return err
+	}
+	locale, msg, ok := c.Message(acceptLanguage, info.GetReason(), info.GetMetadata())
+	if !ok {
+		return err
+	}
+	if d, derr := connect.NewErrorDetail(&errdetails.LocalizedMessage{Locale: locale, Message: msg}); derr == nil {
+		ce.AddDetail(d)
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package apperr_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	apperrv1 "github.com/xiao1203/go-onion-grpc-template/gen/apperr/v1"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+)
+
+func TestCatalog_Coverage(t *testing.T) {
+	c := apperr.DefaultCatalog()
+	for _, lang := range []string{"en", "ja"} {
+		for num, name := range apperrv1.Reason_name {
+			if num == int32(apperrv1.Reason_REASON_UNSPECIFIED) {
+				continue
+			}
+			if locale, _, ok := c.Message(lang, name, nil); !ok || locale != lang {
+				t.Errorf("%s: no message for %s (got locale %q)", lang, name, locale)
+			}
+		}
+	}
+}
+
+func TestCatalog_Message(t *testing.T) {
+	c := apperr.DefaultCatalog()
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		wantLocale     string
+	}{
+		{"正常系: ja-JPは日本語になること", "ja-JP,ja;q=0.9,en;q=0.8", "ja"},
+		{"正常系: q値の高い言語が選ばれること", "ja;q=0.5, en-US", "en"},
+		{"正常系: 未対応の言語は英語になること", "fr-FR", "en"},
+		{"正常系: ヘッダが無い場合は英語になること", "", "en"},
+		{"正常系: 不正なヘッダは英語になること", "!!!", "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale, msg, ok := c.Message(tt.acceptLanguage, "ACCOUNT_LOCKED", nil)
+			if !ok || locale != tt.wantLocale || msg == "" {
+				t.Fatalf("Message() = %q, %q, %v; want locale %q", locale, msg, ok, tt.wantLocale)
+			}
+		})
+	}
+
+	t.Run("正常系: プレースホルダがメタデータで置換されること", func(t *testing.T) {
+		_, msg, _ := c.Message("ja", "INTERNAL", map[string]string{"error_id": "ABC123"})
+		if want := "予期しないエラーが発生しました。エラーID ABC123 を添えてお問い合わせください。"; msg != want {
+			t.Fatalf("message = %q, want %q", msg, want)
+		}
+	})
+}
+
+func TestLoadCatalog(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.json": {Data: []byte(`{"NOT_FOUND": "Not found.", "CONFLICT": "Already exists."}`)},
+		"fr.json": {Data: []byte(`{"NOT_FOUND": "Introuvable."}`)},
+	}
+	c, err := apperr.LoadCatalog(fsys, "en")
+	if err != nil {
+		t.Fatalf("LoadCatalog() error = %v", err)
+	}
+	t.Run("正常系: バンドルを追加すると言語が増えること", func(t *testing.T) {
+		if locale, msg, _ := c.Message("fr-CA", "NOT_FOUND", nil); locale != "fr" || msg != "Introuvable." {
+			t.Fatalf("Message() = %q, %q", locale, msg)
+		}
+	})
+	t.Run("正常系: バンドルに無い理由はフォールバック言語になること", func(t *testing.T) {
+		if locale, msg, _ := c.Message("fr", "CONFLICT", nil); locale != "en" || msg != "Already exists." {
+			t.Fatalf("Message() = %q, %q", locale, msg)
+		}
+	})
+	t.Run("異常系: フォールバック言語のバンドルが無い場合はエラーになること", func(t *testing.T) {
+		if _, err := apperr.LoadCatalog(fsys, "ja"); err == nil {
+			t.Fatal("want error")
+		}
+	})
+	t.Run("異常系: 言語タグでないファイル名はエラーになること", func(t *testing.T) {
+		if _, err := apperr.LoadCatalog(fstest.MapFS{"messages.json": {Data: []byte(`{}`)}}, "en"); err == nil {
+			t.Fatal("want error")
+		}
+	})
+}
+
+func TestCatalog_Localize(t *testing.T) {
+	c := apperr.DefaultCatalog()
+
+	t.Run("正常系: 理由に応じたLocalizedMessageが付きコードとErrorInfoは変わらないこと", func(t *testing.T) {
+		err := apperr.ToConnect(ergo.WithCode(ergo.New("account is locked", apperr.Reason(apperr.ReasonAccountLocked)), apperr.Unauthenticated))
+		got := c.Localize(err, "ja-JP")
+		if connect.CodeOf(got) != connect.CodeUnauthenticated {
+			t.Fatalf("code = %v", connect.CodeOf(got))
+		}
+		d := detailsOf(t, got)
+		if info := d["google.rpc.ErrorInfo"].(*errdetails.ErrorInfo); info.GetReason() != "ACCOUNT_LOCKED" {
+			t.Fatalf("ErrorInfo = %v", info)
+		}
+		lm, _ := d["google.rpc.LocalizedMessage"].(*errdetails.LocalizedMessage)
+		if lm.GetLocale() != "ja" || lm.GetMessage() != "ログインの失敗が続いたため、アカウントが一時的にロックされています。" {
+			t.Fatalf("LocalizedMessage = %v", lm)
+		}
+	})
+
+	t.Run("正常系: 内部エラーのメッセージにerror_idが入ること", func(t *testing.T) {
+		err := c.Localize(apperr.ToConnect(ergo.New("boom")), "en")
+		lm, _ := detailsOf(t, err)["google.rpc.LocalizedMessage"].(*errdetails.LocalizedMessage)
+		if want := "An unexpected error occurred. Please contact support with error ID " + apperr.ErrorIDOf(err) + "."; lm.GetMessage() != want {
+			t.Fatalf("message = %q, want %q", lm.GetMessage(), want)
+		}
+	})
+
+	t.Run("正常系: 明示的なLocalizedMessageは上書きされないこと", func(t *testing.T) {
+		err := apperr.ToConnect(ergo.WithCode(ergo.New("too short", apperr.LocalizedMessage("ja-JP", "パスワードが短すぎます")), apperr.InvalidArgument))
+		lm, _ := detailsOf(t, c.Localize(err, "en"))["google.rpc.LocalizedMessage"].(*errdetails.LocalizedMessage)
+		if lm.GetLocale() != "ja-JP" || lm.GetMessage() != "パスワードが短すぎます" {
+			t.Fatalf("LocalizedMessage = %v", lm)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package apperr
+
+import (
+	"crypto/rand"
+	"errors"
+	"log/slog"
+
+	"connectrpc.com/connect"
+	"github.com/newmo-oss/ergo"
+)
+
+const clientMessageKey = "apperr.client_message"
+
+// ClientMessage opts a domain error into sending msg to the client. Without it
+// ToConnect sends only the generic message of the error code, because error
+// strings often carry wrapped context ("gorm First samples: ...") or driver
+// output. It is ignored on internal errors.
+//
+//	ergo.WithCode(ergo.New("account locked", apperr.ClientMessage("account is locked")), apperr.Unauthenticated)
+func ClientMessage(msg string) slog.Attr {
+	return slog.String(clientMessageKey, msg)
+}
+
+// clientError is the cause of every connect error built by ToConnect. Error
+// returns what the client sees; Unwrap keeps the original chain for logging.
+type clientError struct {
+	msg string
+	id  string
+	err error
+}
+
+func (e *clientError) Error() string { return e.msg }
+func (e *clientError) Unwrap() error { return e.err }
+
+// ErrorIDOf returns the ID ToConnect assigned to an internal error, or "".
+// The same ID is sent to the client in the message and in
+// ErrorInfo.metadata["error_id"].
+func ErrorIDOf(err error) string {
+	var ce *clientError
+	if errors.As(err, &ce) {
+		return ce.id
+	}
+	return ""
+}
+
+// CauseOf returns the error ToConnect converted, i.e. the full chain that was
+// hidden from the client. Other errors are returned as is.
+func CauseOf(err error) error {
+	var ce *clientError
+	if errors.As(err, &ce) {
+		return ce.err
+	}
+	return err
+}
+
+// redact builds the connect error of err with a client-safe message.
+func redact(err error) *connect.Error {
+	code := connectCode(ergo.CodeOf(err))
+	if code == connect.CodeInternal || code == connect.CodeDataLoss {
+		id := rand.Text()
+		ce := connect.NewError(code, &clientError{msg: "internal error (error_id: " + id + ")", id: id, err: err})
+		return withDetails(ce, err, id)
+	}
+	msg := ergo.CodeOf(err).Message()
+	for attr := range ergo.AttrsAll(err) {
+		if attr.Key == clientMessageKey {
+			msg = attr.Value.String()
+		}
+	}
+	return withDetails(connect.NewError(code, &clientError{msg: msg, err: err}), err, "")
+}
+
+// redactConnect handles errors that are already connect errors: those built by
+// ToConnect and explicit non-internal ones pass through, internal and unknown
+// ones (e.g. a plain error returned from a handler) are replaced.
+func redactConnect(ce *connect.Error) *connect.Error {
+	var cl *clientError
+	if errors.As(ce, &cl) {
+		return ce
+	}
+	switch ce.Code() {
+	case connect.CodeInternal, connect.CodeUnknown, connect.CodeDataLoss:
+		return redact(ce)
+	}
+	return ce
+}
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// Rule declares the roles / permissions required to call a procedure.
+type Rule struct {
+	// Roles: the caller must have at least one of them.
+	Roles []string `json:"roles"`
+	// Permissions: the caller must hold all of them.
+	Permissions []string `json:"permissions"`
+}
+
+// IsZero reports whether the rule has no requirement.
+func (r Rule) IsZero() bool { return len(r.Roles) == 0 && len(r.Permissions) == 0 }
+
+// Policy is the config-side authorization policy.
+// Procedures is the fallback for RPCs without an (auth.authz) option;
+// RolePermissions grants permissions to roles ("*" grants every permission);
+// StepUp is the fallback for RPCs without an (auth.step_up) option;
+// Sensitive lists procedures treated as (auth.sensitive) = true.
+type Policy struct {
+	Procedures      map[string]Rule       `json:"procedures"`
+	RolePermissions map[string][]string   `json:"role_permissions"`
+	StepUp          map[string]StepUpRule `json:"step_up"`
+	Sensitive       []string              `json:"sensitive"`
+}
+
+// LoadPolicyFromEnv reads the policy from AUTHZ_POLICY_FILE or AUTHZ_POLICY (JSON).
+// It returns an empty policy when neither is set.
+func LoadPolicyFromEnv() (*Policy, error) {
+	raw := os.Getenv("AUTHZ_POLICY")
+	if path := os.Getenv("AUTHZ_POLICY_FILE"); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, ergo.Wrap(err, "read AUTHZ_POLICY_FILE")
+		}
+		raw = string(b)
+	}
+	p := &Policy{}
+	if strings.TrimSpace(raw) == "" {
+		return p, nil
+	}
+	if err := json.Unmarshal([]byte(raw), p); err != nil {
+		return nil, ergo.Wrap(err, "parse authz policy")
+	}
+	return p, nil
+}
+
+var (
+	policyMu  sync.Mutex
+	policy    *Policy
+	policyKey string
+)
+
+// PolicyFromEnv returns a process-wide Policy, reloaded only when the environment changes.
+func PolicyFromEnv() (*Policy, error) {
+	key := os.Getenv("AUTHZ_POLICY_FILE") + "\x00" + os.Getenv("AUTHZ_POLICY")
+	policyMu.Lock()
+	defer policyMu.Unlock()
+	if policy != nil && policyKey == key {
+		return policy, nil
+	}
+	p, err := LoadPolicyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	policy, policyKey = p, key
+	return policy, nil
+}
+
+// RuleFor returns the config rule for the procedure.
+func (p *Policy) RuleFor(procedure string) (Rule, bool) {
+	if p == nil {
+		return Rule{}, false
+	}
+	r, ok := p.Procedures[procedure]
+	return r, ok
+}
+
+// StepUpFor returns the config step-up rule for the procedure.
+func (p *Policy) StepUpFor(procedure string) (StepUpRule, bool) {
+	if p == nil {
+		return StepUpRule{}, false
+	}
+	r, ok := p.StepUp[procedure]
+	return r, ok
+}
+
+// IsSensitive reports whether the config marks the procedure sensitive.
+func (p *Policy) IsSensitive(procedure string) bool {
+	return p != nil && slices.Contains(p.Sensitive, procedure)
+}
+
+// HasPermission reports whether the principal's scopes or any of its roles grant perm.
+func (p *Policy) HasPermission(pr *Principal, perm string) bool {
+	if pr == nil {
+		return false
+	}
+	if slices.Contains(pr.Scopes, perm) {
+		return true
+	}
+	if p == nil {
+		return false
+	}
+	for _, role := range pr.Roles {
+		perms := p.RolePermissions[role]
+		if slices.Contains(perms, "*") || slices.Contains(perms, perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize checks the principal against the rule. The returned error names
+// the missing role set or permission.
+func (p *Policy) Authorize(pr *Principal, r Rule) error {
+	if len(r.Roles) > 0 {
+		ok := false
+		if pr != nil {
+			for _, role := range r.Roles {
+				if slices.Contains(pr.Roles, role) {
+					ok = true
+					break
+				}
+			}
+		}
+		if !ok {
+			return ergo.New("missing role: one of "+strings.Join(r.Roles, ","), slog.String("required_roles", strings.Join(r.Roles, ",")))
+		}
+	}
+	for _, perm := range r.Permissions {
+		if !p.HasPermission(pr, perm) {
+			return ergo.New("missing permission: "+perm, slog.String("permission", perm))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// ClaimMapping declares where Principal fields are read from in verified claims.
+//
+// Each field is a claim path. Nested objects are addressed with dots
+// (e.g. "realm_access.roles"); an optional "$." prefix is accepted. A claim whose
+// literal name contains dots or colons (e.g. "cognito:groups",
+// "https://example.com/roles") is matched before the path is split.
+// Empty fields fall back to sub / email / roles; Tenant is optional.
+type ClaimMapping struct {
+	Subject string `json:"subject"`
+	Email   string `json:"email"`
+	Roles   string `json:"roles"`
+	Tenant  string `json:"tenant"`
+}
+
+// LoadClaimMappingFromEnv reads the shared claim mapping from AUTH_CLAIM_MAPPING (JSON).
+// It is used by the HS256 path and by trusted issuers without their own "claims".
+func LoadClaimMappingFromEnv() (ClaimMapping, error) {
+	var m ClaimMapping
+	raw := os.Getenv("AUTH_CLAIM_MAPPING")
+	if strings.TrimSpace(raw) == "" {
+		return m, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &m); err != nil {
+		return m, ergo.Wrap(err, "parse AUTH_CLAIM_MAPPING")
+	}
+	return m, nil
+}
+
+func (m ClaimMapping) withDefaults() ClaimMapping {
+	if m.Subject == "" {
+		m.Subject = "sub"
+	}
+	if m.Email == "" {
+		m.Email = "email"
+	}
+	if m.Roles == "" {
+		m.Roles = "roles"
+	}
+	return m
+}
+
+// Principal builds a Principal from verified claims.
+// The subject is kept as a string; UserID is set only when it is numeric.
+func (m ClaimMapping) Principal(claims map[string]any) (*Principal, error) {
+	m = m.withDefaults()
+	sub, _ := lookupClaim(claims, m.Subject).(string)
+	if sub == "" {
+		return nil, ergo.New("subject claim missing", slog.String("path", m.Subject))
+	}
+	p := &Principal{Subject: sub, Claims: claims}
+	if v, err := strconv.ParseInt(sub, 10, 64); err == nil {
+		p.UserID = v
+	}
+	p.Issuer, _ = claims["iss"].(string)
+	p.Email, _ = lookupClaim(claims, m.Email).(string)
+	p.Roles = stringsClaim(lookupClaim(claims, m.Roles))
+	if m.Tenant != "" {
+		switch v := lookupClaim(claims, m.Tenant).(type) {
+		case string:
+			p.TenantID = v
+		case float64:
+			p.TenantID = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	return p, nil
+}
+
+// lookupClaim resolves a claim path against claims. It returns nil if not found.
+func lookupClaim(claims map[string]any, path string) any {
+	path = strings.TrimPrefix(path, "$.")
+	if v, ok := claims[path]; ok {
+		return v
+	}
+	var cur any = claims
+	for _, seg := range strings.Split(path, ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if cur, ok = obj[seg]; !ok {
+			return nil
+		}
+	}
+	return cur
+}
+
+// stringsClaim converts an array claim, or a space-delimited string claim
+// (like OAuth2 "scope"), to a string slice.
+func stringsClaim(v any) []string {
+	var out []string
+	switch vv := v.(type) {
+	case []any:
+		for _, r := range vv {
+			if s, ok := r.(string); ok {
+				out = append(out, s)
+			}
+		}
+	case []string:
+		out = append(out, vv...)
+	case string:
+		out = strings.Fields(vv)
+	}
+	return out
+}
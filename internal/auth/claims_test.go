@@ -0,0 +1,81 @@
+package auth_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+)
+
+func decodeClaims(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		t.Fatalf("decode claims: %v", err)
+	}
+	return m
+}
+
+func TestClaimMapping_Principal(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping auth.ClaimMapping
+		claims  string
+		want    *auth.Principal
+		wantErr bool
+	}{
+		{
+			name:    "正常系: 既定のマッピングでは数値のsubがUserIDになること",
+			mapping: auth.ClaimMapping{},
+			claims:  `{"iss":"https://idp","sub":"1","email":"a@example.com","roles":["admin"]}`,
+			want:    &auth.Principal{UserID: 1, Subject: "1", Issuer: "https://idp", Email: "a@example.com", Roles: []string{"admin"}},
+		},
+		{
+			name:    "正常系: Keycloakのrealm_access.rolesと非数値subを扱えること",
+			mapping: auth.ClaimMapping{Roles: "realm_access.roles", Tenant: "$.org.id"},
+			claims:  `{"sub":"f3a1-uuid","email":"s@example.com","realm_access":{"roles":["staff","admin"]},"org":{"id":"acme"}}`,
+			want:    &auth.Principal{Subject: "f3a1-uuid", Email: "s@example.com", Roles: []string{"staff", "admin"}, TenantID: "acme"},
+		},
+		{
+			name:    "正常系: コロンを含むクレーム名（cognito:groups）を扱えること",
+			mapping: auth.ClaimMapping{Roles: "cognito:groups", Email: "email"},
+			claims:  `{"sub":"abc","cognito:groups":["customer"],"email":"c@example.com"}`,
+			want:    &auth.Principal{Subject: "abc", Email: "c@example.com", Roles: []string{"customer"}},
+		},
+		{
+			name:    "正常系: スペース区切りの文字列クレームをロールとして扱えること",
+			mapping: auth.ClaimMapping{Roles: "scope"},
+			claims:  `{"sub":"svc","scope":"read write"}`,
+			want:    &auth.Principal{Subject: "svc", Roles: []string{"read", "write"}},
+		},
+		{
+			name:    "異常系: subjectが無い場合はエラーになること",
+			mapping: auth.ClaimMapping{},
+			claims:  `{"email":"a@example.com"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := decodeClaims(t, tt.claims)
+			got, err := tt.mapping.Principal(claims)
+			if err != nil {
+				if !tt.wantErr {
+					t.Fatalf("Principal() failed: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Fatal("Principal() succeeded unexpectedly")
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(auth.Principal{}, "Claims")); diff != "" {
+				t.Errorf("Principal() mismatch (-want +got):\n%s", diff)
+			}
+			if got.Claims == nil {
+				t.Error("raw claims not kept on Principal")
+			}
+		})
+	}
+}
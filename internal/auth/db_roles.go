@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// How database roles (user_roles) combine with the roles of the token (AUTH_DB_ROLES).
+const (
+	// DBRolesOff keeps the token roles only (default).
+	DBRolesOff = "off"
+	// DBRolesMerge adds the database roles to the token roles.
+	DBRolesMerge = "merge"
+	// DBRolesReplace ignores the token roles and uses the database roles.
+	DBRolesReplace = "replace"
+)
+
+// DBRolesModeFromEnv reads AUTH_DB_ROLES (off, merge or replace; empty is off).
+func DBRolesModeFromEnv() (string, error) {
+	switch m := strings.ToLower(strings.TrimSpace(os.Getenv("AUTH_DB_ROLES"))); m {
+	case "", DBRolesOff:
+		return DBRolesOff, nil
+	case DBRolesMerge, DBRolesReplace:
+		return m, nil
+	default:
+		return "", ergo.New("AUTH_DB_ROLES must be off, merge or replace", slog.String("value", m))
+	}
+}
+
+// CombineRoles returns the roles of a principal given the token roles and the
+// database roles under mode. The inputs are not modified.
+func CombineRoles(mode string, token, db []string) []string {
+	switch mode {
+	case DBRolesReplace:
+		return slices.Clone(db)
+	case DBRolesMerge:
+		out := slices.Clone(token)
+		for _, r := range db {
+			if !slices.Contains(out, r) {
+				out = append(out, r)
+			}
+		}
+		return out
+	default:
+		return token
+	}
+}
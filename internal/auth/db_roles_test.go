@@ -0,0 +1,29 @@
+package auth
+
+import "testing"
+
+func TestDBRolesModeFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{"正常系: 未設定はoffになること", "", DBRolesOff, false},
+		{"正常系: 大文字や空白を含んでも解釈できること", " Merge ", DBRolesMerge, false},
+		{"正常系: replaceを指定できること", "replace", DBRolesReplace, false},
+		{"異常系: 不正な値はエラーになること", "both", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AUTH_DB_ROLES", tt.env)
+			got, err := DBRolesModeFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DBRolesModeFromEnv() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("DBRolesModeFromEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// Headers that override the development principal per request.
+const (
+	DevUserIDHeader = "X-Dev-User-Id"
+	DevEmailHeader  = "X-Dev-Email"
+	DevRolesHeader  = "X-Dev-Roles"
+)
+
+// DevBypass injects a development Principal instead of authenticating.
+// It is only ever active when APP_ENV=dev.
+type DevBypass struct {
+	// Defaults used when the request does not carry X-Dev-* headers.
+	UserID int64
+	Email  string
+	Roles  []string
+}
+
+// LoadDevBypassFromEnv reads DEV_AUTH_BYPASS=1 together with APP_ENV=dev, and
+// the defaults DEV_USER_ID (1), DEV_USER_EMAIL (dev@example.com) and
+// DEV_USER_ROLES (comma separated, default "user"). It returns nil when the
+// bypass is off, and an error when DEV_AUTH_BYPASS is set outside development.
+func LoadDevBypassFromEnv() (*DevBypass, error) {
+	if os.Getenv("DEV_AUTH_BYPASS") != "1" {
+		return nil, nil
+	}
+	if os.Getenv("APP_ENV") != "dev" {
+		return nil, ergo.New("DEV_AUTH_BYPASS requires APP_ENV=dev")
+	}
+	b := &DevBypass{UserID: 1, Email: "dev@example.com", Roles: []string{"user"}}
+	if s := os.Getenv("DEV_USER_ID"); s != "" {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, ergo.Wrap(err, "parse DEV_USER_ID")
+		}
+		b.UserID = v
+	}
+	if s := os.Getenv("DEV_USER_EMAIL"); s != "" {
+		b.Email = s
+	}
+	if s := os.Getenv("DEV_USER_ROLES"); s != "" {
+		b.Roles = splitList(s)
+	}
+	return b, nil
+}
+
+// Principal builds the development Principal, letting X-Dev-User-Id,
+// X-Dev-Email and X-Dev-Roles (comma separated) override the defaults.
+func (b *DevBypass) Principal(h http.Header) (*Principal, error) {
+	p := &Principal{
+		UserID:   b.UserID,
+		Email:    b.Email,
+		Roles:    append([]string(nil), b.Roles...),
+		Provider: "dev",
+	}
+	if s := h.Get(DevUserIDHeader); s != "" {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, ergo.Wrap(err, "parse "+DevUserIDHeader)
+		}
+		p.UserID = v
+	}
+	if s := h.Get(DevEmailHeader); s != "" {
+		p.Email = s
+	}
+	if s, ok := h[http.CanonicalHeaderKey(DevRolesHeader)]; ok {
+		// An empty header means "no roles".
+		p.Roles = splitList(strings.Join(s, ","))
+	}
+	p.Subject = strconv.FormatInt(p.UserID, 10)
+	return p, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+var (
+	devBypassMu  sync.Mutex
+	devBypass    *DevBypass
+	devBypassErr error
+	devBypassKey string
+	devBypassSet bool
+)
+
+// DevBypassFromEnv returns the process-wide DevBypass, reloaded only when the environment changes.
+func DevBypassFromEnv() (*DevBypass, error) {
+	key := strings.Join([]string{
+		os.Getenv("DEV_AUTH_BYPASS"), os.Getenv("APP_ENV"),
+		os.Getenv("DEV_USER_ID"), os.Getenv("DEV_USER_EMAIL"), os.Getenv("DEV_USER_ROLES"),
+	}, "\x00")
+	devBypassMu.Lock()
+	defer devBypassMu.Unlock()
+	if !devBypassSet || devBypassKey != key {
+		devBypass, devBypassErr = LoadDevBypassFromEnv()
+		devBypassKey, devBypassSet = key, true
+	}
+	return devBypass, devBypassErr
+}
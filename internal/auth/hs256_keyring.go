@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// HS256 key states. The active key signs new tokens; verify-only keys are
+// still accepted so that tokens signed before a rotation stay valid.
+const (
+	HS256KeyActive     = "active"
+	HS256KeyVerifyOnly = "verify"
+)
+
+// HS256Key is one HMAC secret in the keyring, selected by the token's kid header.
+type HS256Key struct {
+	ID     string `json:"kid"`
+	Status string `json:"status"`
+	// SecretFile is the path of a file holding the secret (trailing newlines
+	// are trimmed). Secret may be given inline instead, for local use only.
+	SecretFile string `json:"secret_file"`
+	Secret     string `json:"secret"`
+}
+
+// LoadHS256KeysFromEnv reads the keyring from AUTH_HS256_KEYS_FILE (path to a
+// JSON array of HS256Key) or AUTH_HS256_KEYS (the JSON itself) and loads each
+// secret_file. Exactly one key must be active. It returns nil when unset.
+func LoadHS256KeysFromEnv() ([]HS256Key, error) {
+	raw := os.Getenv("AUTH_HS256_KEYS")
+	if path := os.Getenv("AUTH_HS256_KEYS_FILE"); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, ergo.Wrap(err, "read AUTH_HS256_KEYS_FILE")
+		}
+		raw = string(b)
+	}
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var keys []HS256Key
+	if err := json.Unmarshal([]byte(raw), &keys); err != nil {
+		return nil, ergo.Wrap(err, "parse HS256 keyring")
+	}
+	seen := map[string]bool{}
+	active := 0
+	for i := range keys {
+		k := &keys[i]
+		if k.ID == "" || seen[k.ID] {
+			return nil, ergo.New("HS256 key needs a unique kid", slog.Int("index", i))
+		}
+		seen[k.ID] = true
+		switch k.Status {
+		case HS256KeyActive:
+			active++
+		case HS256KeyVerifyOnly:
+		default:
+			return nil, ergo.New("HS256 key status must be active or verify", slog.String("kid", k.ID))
+		}
+		if k.SecretFile != "" {
+			secret, err := readSecretFile(k.SecretFile)
+			if err != nil {
+				return nil, ergo.Wrap(err, "read HS256 secret_file", slog.String("kid", k.ID))
+			}
+			k.Secret = secret
+		}
+		if k.Secret == "" {
+			return nil, ergo.New("HS256 key has no secret", slog.String("kid", k.ID))
+		}
+	}
+	if active != 1 {
+		return nil, ergo.New("HS256 keyring needs exactly one active key", slog.Int("active", active))
+	}
+	return keys, nil
+}
+
+// ActiveHS256Key returns the key new tokens should be signed with.
+func ActiveHS256Key(keys []HS256Key) (HS256Key, bool) {
+	for _, k := range keys {
+		if k.Status == HS256KeyActive {
+			return k, true
+		}
+	}
+	return HS256Key{}, false
+}
+
+func readSecretFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
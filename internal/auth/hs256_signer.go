@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/newmo-oss/ergo"
+)
+
+// HS256Signer signs tokens that HS256Verifier accepts: with the active key of
+// the keyring (kid header) or, without a keyring, with the secret.
+type HS256Signer struct {
+	cfg HS256Config
+}
+
+// NewHS256Signer returns a signer; the config needs a secret or an active key.
+func NewHS256Signer(cfg HS256Config) (*HS256Signer, error) {
+	if _, ok := ActiveHS256Key(cfg.Keys); !ok && cfg.Secret == "" {
+		return nil, ergo.New("HS256 signer needs AUTH_HS256_SECRET or an active key")
+	}
+	return &HS256Signer{cfg: cfg}, nil
+}
+
+// Sign signs claims. iss and aud are set from the config unless present.
+func (s *HS256Signer) Sign(claims map[string]any) (string, error) {
+	c := jwt.MapClaims{}
+	for k, v := range claims {
+		c[k] = v
+	}
+	if _, ok := c["iss"]; !ok && s.cfg.Issuer != "" {
+		c["iss"] = s.cfg.Issuer
+	}
+	if _, ok := c["aud"]; !ok && s.cfg.Audience != "" {
+		c["aud"] = s.cfg.Audience
+	}
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
+	secret := s.cfg.Secret
+	if k, ok := ActiveHS256Key(s.cfg.Keys); ok {
+		t.Header["kid"] = k.ID
+		secret = k.Secret
+	}
+	signed, err := t.SignedString([]byte(secret))
+	if err != nil {
+		return "", ergo.Wrap(err, "sign HS256 token")
+	}
+	return signed, nil
+}
+
+// Claims places the subject, email and roles where the mapping reads them,
+// so that tokens minted by this service map back to the same Principal.
+func (m ClaimMapping) Claims(subject, email string, roles []string) map[string]any {
+	m = m.withDefaults()
+	c := map[string]any{strings.TrimPrefix(m.Subject, "$."): subject}
+	if email != "" {
+		c[strings.TrimPrefix(m.Email, "$.")] = email
+	}
+	if len(roles) > 0 {
+		c[strings.TrimPrefix(m.Roles, "$.")] = roles
+	}
+	return c
+}
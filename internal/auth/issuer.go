@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/newmo-oss/ergo"
+)
+
+const (
+	defaultJWKSTTL   = 5 * time.Minute
+	defaultClockSkew = 60 * time.Second
+)
+
+// Duration is a time.Duration that unmarshals from JSON strings such as "60s".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return ergo.Wrap(err, "duration must be a string like \"60s\"")
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return ergo.Wrap(err, "parse duration")
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// IssuerConfig describes one trusted token issuer (IdP).
+type IssuerConfig struct {
+	// Name is the provider name recorded on Principal (e.g. keycloak, cognito).
+	Name string `json:"name"`
+	// Issuer is the expected iss claim. Empty matches any issuer (legacy single-issuer mode).
+	Issuer    string   `json:"issuer"`
+	JWKSURL   string   `json:"jwks_url"`
+	Audience  string   `json:"audience"`
+	JWKSTTL   Duration `json:"jwks_ttl"`
+	ClockSkew Duration `json:"clock_skew"`
+	// Claims overrides the shared claim mapping (AUTH_CLAIM_MAPPING) for this issuer.
+	Claims *ClaimMapping `json:"claims"`
+}
+
+// LoadIssuerConfigsFromEnv reads trusted issuers from the environment.
+//
+//   - AUTH_ISSUERS_FILE: path to a JSON array of IssuerConfig
+//   - AUTH_ISSUERS: JSON array of IssuerConfig
+//   - otherwise the legacy single-issuer keys (AUTH_JWKS_URL, AUTH_ISSUER, AUTH_AUDIENCE,
+//     AUTH_JWKS_TTL, AUTH_CLOCK_SKEW) are mapped to one issuer.
+//
+// It returns an empty slice when no issuer is configured.
+func LoadIssuerConfigsFromEnv() ([]IssuerConfig, error) {
+	raw := os.Getenv("AUTH_ISSUERS")
+	if path := os.Getenv("AUTH_ISSUERS_FILE"); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, ergo.Wrap(err, "read AUTH_ISSUERS_FILE")
+		}
+		raw = string(b)
+	}
+	if strings.TrimSpace(raw) != "" {
+		var cfgs []IssuerConfig
+		if err := json.Unmarshal([]byte(raw), &cfgs); err != nil {
+			return nil, ergo.Wrap(err, "parse trusted issuers")
+		}
+		for i, c := range cfgs {
+			if c.JWKSURL == "" {
+				return nil, ergo.New("trusted issuer has no jwks_url", slog.Int("index", i), slog.String("name", c.Name))
+			}
+		}
+		return cfgs, nil
+	}
+	jwksURL := os.Getenv("AUTH_JWKS_URL")
+	if jwksURL == "" {
+		return nil, nil
+	}
+	c := IssuerConfig{
+		Name:     "default",
+		Issuer:   os.Getenv("AUTH_ISSUER"),
+		JWKSURL:  jwksURL,
+		Audience: os.Getenv("AUTH_AUDIENCE"),
+	}
+	if s := os.Getenv("AUTH_JWKS_TTL"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil {
+			c.JWKSTTL = Duration(d)
+		}
+	}
+	if s := os.Getenv("AUTH_CLOCK_SKEW"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil {
+			c.ClockSkew = Duration(d)
+		}
+	}
+	return []IssuerConfig{c}, nil
+}
+
+// IssuerSet verifies JWTs against a list of trusted issuers.
+// The verifier is chosen by the (unverified) iss claim of the token.
+type IssuerSet struct {
+	issuers  []*trustedIssuer
+	fallback *trustedIssuer
+}
+
+type trustedIssuer struct {
+	cfg  IssuerConfig
+	jwks *JWKSCache
+}
+
+// NewIssuerSet builds an IssuerSet. Issuers sharing a JWKS URL share one cache.
+// Issuers without their own claim mapping use the given default mapping.
+func NewIssuerSet(cfgs []IssuerConfig, defaultClaims ClaimMapping) *IssuerSet {
+	s := &IssuerSet{}
+	caches := map[string]*JWKSCache{}
+	for _, c := range cfgs {
+		if c.JWKSTTL == 0 {
+			c.JWKSTTL = Duration(defaultJWKSTTL)
+		}
+		if c.ClockSkew == 0 {
+			c.ClockSkew = Duration(defaultClockSkew)
+		}
+		if c.Claims == nil {
+			m := defaultClaims
+			c.Claims = &m
+		}
+		cache, ok := caches[c.JWKSURL]
+		if !ok {
+			cache = NewJWKSCache(c.JWKSURL, time.Duration(c.JWKSTTL))
+			caches[c.JWKSURL] = cache
+		}
+		ti := &trustedIssuer{cfg: c, jwks: cache}
+		if c.Issuer == "" {
+			if s.fallback == nil {
+				s.fallback = ti
+			}
+			continue
+		}
+		s.issuers = append(s.issuers, ti)
+	}
+	return s
+}
+
+// Len returns the number of configured issuers.
+func (s *IssuerSet) Len() int {
+	if s == nil {
+		return 0
+	}
+	n := len(s.issuers)
+	if s.fallback != nil {
+		n++
+	}
+	return n
+}
+
+func (s *IssuerSet) lookup(iss string) (*trustedIssuer, bool) {
+	for _, ti := range s.issuers {
+		if ti.cfg.Issuer == iss {
+			return ti, true
+		}
+	}
+	if s.fallback != nil {
+		return s.fallback, true
+	}
+	return nil, false
+}
+
+// Verify checks the token signature and standard claims with the issuer
+// matching its iss claim, and returns the resulting Principal. HMAC-signed
+// tokens are ErrNotHandled so that the HS256 verifier can take them.
+func (s *IssuerSet) Verify(ctx context.Context, tokenString string) (*Principal, error) {
+	var unverified jwt.MapClaims
+	tok, _, err := jwt.NewParser().ParseUnverified(tokenString, &unverified)
+	if err != nil {
+		return nil, ergo.Wrap(err, "malformed token")
+	}
+	if alg, _ := tok.Header["alg"].(string); strings.HasPrefix(alg, "HS") {
+		return nil, ErrNotHandled
+	}
+	iss, _ := unverified["iss"].(string)
+	ti, ok := s.lookup(iss)
+	if !ok {
+		return nil, ergo.Wrap(ErrUntrustedIssuer, "verify token", slog.String("iss", iss))
+	}
+	rules := jwtRules{
+		methods:   []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"},
+		issuer:    ti.cfg.Issuer,
+		audience:  ti.cfg.Audience,
+		clockSkew: time.Duration(ti.cfg.ClockSkew),
+	}
+	claims, err := parseJWT(tokenString, rules, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return ti.jwks.KeyFor(kid)
+	})
+	if err != nil {
+		return nil, ergo.Wrap(err, "verify token", slog.String("provider", ti.cfg.Name))
+	}
+	p, err := ti.cfg.Claims.Principal(claims)
+	if err != nil {
+		return nil, ergo.Wrap(err, "map claims", slog.String("provider", ti.cfg.Name))
+	}
+	p.Provider = ti.cfg.Name
+	return p, nil
+}
+
+var (
+	issuerSetMu  sync.Mutex
+	issuerSet    *IssuerSet
+	issuerSetKey string
+)
+
+// IssuerSetFromEnv returns a process-wide IssuerSet built from the environment.
+// It is rebuilt only when the relevant environment variables change.
+func IssuerSetFromEnv() (*IssuerSet, error) {
+	key := strings.Join([]string{
+		os.Getenv("AUTH_ISSUERS_FILE"), os.Getenv("AUTH_ISSUERS"),
+		os.Getenv("AUTH_JWKS_URL"), os.Getenv("AUTH_ISSUER"), os.Getenv("AUTH_AUDIENCE"),
+		os.Getenv("AUTH_JWKS_TTL"), os.Getenv("AUTH_CLOCK_SKEW"), os.Getenv("AUTH_CLAIM_MAPPING"),
+	}, "\x00")
+	issuerSetMu.Lock()
+	defer issuerSetMu.Unlock()
+	if issuerSet != nil && issuerSetKey == key {
+		return issuerSet, nil
+	}
+	cfgs, err := LoadIssuerConfigsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	claims, err := LoadClaimMappingFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	issuerSet = NewIssuerSet(cfgs, claims)
+	issuerSetKey = key
+	return issuerSet, nil
+}
@@ -0,0 +1,182 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+)
+
+type testIssuer struct {
+	key *rsa.PrivateKey
+	kid string
+	srv *httptest.Server
+}
+
+func newTestIssuer(t *testing.T, kid string) *testIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa key: %v", err)
+	}
+	ti := &testIssuer{key: key, kid: kid}
+	ti.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": kid,
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(ti.srv.Close)
+	return ti
+}
+
+func (ti *testIssuer) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = ti.kid
+	s, err := tok.SignedString(ti.key)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return s
+}
+
+func TestIssuerSet_Verify(t *testing.T) {
+	staff := newTestIssuer(t, "staff-key")
+	customer := newTestIssuer(t, "customer-key")
+	set := auth.NewIssuerSet([]auth.IssuerConfig{
+		{Name: "keycloak", Issuer: "https://keycloak.example/realms/staff", JWKSURL: staff.srv.URL, Audience: "api"},
+		{Name: "cognito", Issuer: "https://cognito.example/pool", JWKSURL: customer.srv.URL, Claims: &auth.ClaimMapping{Roles: "cognito:groups"}},
+	}, auth.ClaimMapping{})
+	exp := time.Now().Add(5 * time.Minute).Unix()
+
+	tests := []struct {
+		name         string
+		token        string
+		wantProvider string
+		wantRoles    []string
+		wantErr      bool
+	}{
+		{
+			name:         "正常系: issに対応する発行者で検証されプロバイダ名が記録されること",
+			token:        staff.sign(t, jwt.MapClaims{"iss": "https://keycloak.example/realms/staff", "aud": "api", "sub": "10", "roles": []string{"admin"}, "exp": exp}),
+			wantProvider: "keycloak",
+			wantRoles:    []string{"admin"},
+		},
+		{
+			name:         "正常系: 発行者ごとのクレームマッピングでロールを取得できること",
+			token:        customer.sign(t, jwt.MapClaims{"iss": "https://cognito.example/pool", "sub": "20", "cognito:groups": []string{"customer"}, "exp": exp}),
+			wantProvider: "cognito",
+			wantRoles:    []string{"customer"},
+		},
+		{
+			name:    "異常系: 別の発行者の鍵で署名されたトークンは拒否されること",
+			token:   customer.sign(t, jwt.MapClaims{"iss": "https://keycloak.example/realms/staff", "aud": "api", "sub": "10", "exp": exp}),
+			wantErr: true,
+		},
+		{
+			name:    "異常系: audが一致しない場合は拒否されること",
+			token:   staff.sign(t, jwt.MapClaims{"iss": "https://keycloak.example/realms/staff", "aud": "other", "sub": "10", "exp": exp}),
+			wantErr: true,
+		},
+		{
+			name:    "異常系: 信頼していない発行者は拒否されること",
+			token:   staff.sign(t, jwt.MapClaims{"iss": "https://evil.example", "sub": "10", "exp": exp}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := set.Verify(context.Background(), tt.token)
+			if err != nil {
+				if !tt.wantErr {
+					t.Fatalf("Verify() failed: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Fatal("Verify() succeeded unexpectedly")
+			}
+			if p.Provider != tt.wantProvider {
+				t.Errorf("Provider = %q, want %q", p.Provider, tt.wantProvider)
+			}
+			if len(p.Roles) != len(tt.wantRoles) || (len(p.Roles) > 0 && p.Roles[0] != tt.wantRoles[0]) {
+				t.Errorf("Roles = %v, want %v", p.Roles, tt.wantRoles)
+			}
+		})
+	}
+}
+
+func TestIssuerSet_Verify_ES256(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ec key: %v", err)
+	}
+	jwk, err := auth.EncodeJWK("ec-key", "ES256", &key.PublicKey)
+	if err != nil {
+		t.Fatalf("EncodeJWK() failed: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []auth.JWK{jwk}})
+	}))
+	t.Cleanup(srv.Close)
+	set := auth.NewIssuerSet([]auth.IssuerConfig{{Name: "dev", Issuer: "https://dev.example", JWKSURL: srv.URL}}, auth.ClaimMapping{})
+	sign := func(kid string) string {
+		tok := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"iss": "https://dev.example", "sub": "1", "exp": time.Now().Add(time.Minute).Unix()})
+		tok.Header["kid"] = kid
+		s, err := tok.SignedString(key)
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		return s
+	}
+
+	t.Run("正常系: JWKSのECキーでES256トークンを検証できること", func(t *testing.T) {
+		if _, err := set.Verify(context.Background(), sign("ec-key")); err != nil {
+			t.Fatalf("Verify() failed: %v", err)
+		}
+	})
+	t.Run("異常系: 未知のkidは拒否されること", func(t *testing.T) {
+		if _, err := set.Verify(context.Background(), sign("other")); err == nil {
+			t.Fatal("Verify() succeeded unexpectedly")
+		}
+	})
+}
+
+func TestLoadIssuerConfigsFromEnv(t *testing.T) {
+	t.Setenv("AUTH_ISSUERS_FILE", "")
+	t.Setenv("AUTH_ISSUERS", `[{"name":"keycloak","issuer":"https://kc","jwks_url":"https://kc/jwks","clock_skew":"30s"}]`)
+	cfgs, err := auth.LoadIssuerConfigsFromEnv()
+	if err != nil {
+		t.Fatalf("LoadIssuerConfigsFromEnv() failed: %v", err)
+	}
+	if len(cfgs) != 1 || cfgs[0].Name != "keycloak" || time.Duration(cfgs[0].ClockSkew) != 30*time.Second {
+		t.Fatalf("unexpected configs: %+v", cfgs)
+	}
+
+	t.Setenv("AUTH_ISSUERS", "")
+	t.Setenv("AUTH_JWKS_URL", "https://legacy/jwks")
+	t.Setenv("AUTH_ISSUER", "https://legacy")
+	cfgs, err = auth.LoadIssuerConfigsFromEnv()
+	if err != nil {
+		t.Fatalf("LoadIssuerConfigsFromEnv() failed: %v", err)
+	}
+	if len(cfgs) != 1 || cfgs[0].Issuer != "https://legacy" {
+		t.Fatalf("legacy keys not mapped: %+v", cfgs)
+	}
+}
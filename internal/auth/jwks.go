@@ -1,9 +1,14 @@
 package auth
 
 import (
+    "crypto"
+    "crypto/ecdh"
+    "crypto/ecdsa"
+    "crypto/elliptic"
     "crypto/rsa"
     "encoding/base64"
     "encoding/json"
+    "log/slog"
     "math/big"
     "net/http"
     "strings"
@@ -13,15 +18,21 @@ import (
     "github.com/newmo-oss/ergo"
 )
 
-type jwksKey struct {
+// JWK is a public key in a JWKS document. RSA keys use N/E, EC keys Crv/X/Y.
+type JWK struct {
 	Kty string `json:"kty"`
 	Kid string `json:"kid"`
-	N   string `json:"n"`
-	E   string `json:"e"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
 }
 
 type jwksDoc struct {
-	Keys []jwksKey `json:"keys"`
+	Keys []JWK `json:"keys"`
 }
 
 type JWKSCache struct {
@@ -29,7 +40,7 @@ type JWKSCache struct {
 	ttl     time.Duration
 	mu      sync.RWMutex
 	expires time.Time
-	keys    map[string]*rsa.PublicKey
+	keys    map[string]crypto.PublicKey
 	client  *http.Client
 }
 
@@ -37,12 +48,13 @@ func NewJWKSCache(url string, ttl time.Duration) *JWKSCache {
 	return &JWKSCache{
 		url:    url,
 		ttl:    ttl,
-		keys:   map[string]*rsa.PublicKey{},
+		keys:   map[string]crypto.PublicKey{},
 		client: &http.Client{Timeout: 10 * time.Second},
 	}
 }
 
-func (c *JWKSCache) KeyFor(kid string) (*rsa.PublicKey, error) {
+// KeyFor returns the *rsa.PublicKey or *ecdsa.PublicKey for kid, refreshing the JWKS when unknown.
+func (c *JWKSCache) KeyFor(kid string) (crypto.PublicKey, error) {
     c.mu.RLock()
     if k, ok := c.keys[kid]; ok && time.Now().Before(c.expires) {
         c.mu.RUnlock()
@@ -58,7 +70,7 @@ func (c *JWKSCache) KeyFor(kid string) (*rsa.PublicKey, error) {
     if k, ok := c.keys[kid]; ok {
         return k, nil
     }
-    return nil, ergo.NewSentinel("jwks: key not found")
+    return nil, ergo.Wrap(ErrUnknownKID, "jwks: key not found", slog.String("kid", kid))
 }
 
 func (c *JWKSCache) refresh() error {
@@ -71,12 +83,18 @@ func (c *JWKSCache) refresh() error {
 	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
 		return err
 	}
-	m := map[string]*rsa.PublicKey{}
+	m := map[string]crypto.PublicKey{}
 	for _, k := range doc.Keys {
-		if !strings.EqualFold(k.Kty, "RSA") {
+		var pub crypto.PublicKey
+		var err error
+		switch {
+		case strings.EqualFold(k.Kty, "RSA"):
+			pub, err = jwkToRSAPublicKey(k.N, k.E)
+		case strings.EqualFold(k.Kty, "EC"):
+			pub, err = jwkToECPublicKey(k.Crv, k.X, k.Y)
+		default:
 			continue
 		}
-		pub, err := jwkToRSAPublicKey(k.N, k.E)
 		if err != nil {
 			continue
 		}
@@ -105,3 +123,57 @@ func jwkToRSAPublicKey(nB64, eB64 string) (*rsa.PublicKey, error) {
 	n := new(big.Int).SetBytes(nBytes)
 	return &rsa.PublicKey{N: n, E: e}, nil
 }
+
+func jwkToECPublicKey(crv, xB64, yB64 string) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	var point ecdh.Curve
+	switch crv {
+	case "P-256":
+		curve, point = elliptic.P256(), ecdh.P256()
+	case "P-384":
+		curve, point = elliptic.P384(), ecdh.P384()
+	case "P-521":
+		curve, point = elliptic.P521(), ecdh.P521()
+	default:
+		return nil, ergo.New("jwks: unsupported curve")
+	}
+	xBytes, err := base64.RawURLEncoding.DecodeString(xB64)
+	if err != nil {
+		return nil, err
+	}
+	yBytes, err := base64.RawURLEncoding.DecodeString(yB64)
+	if err != nil {
+		return nil, err
+	}
+	// RFC 7518 6.2.1.2: coordinates are the full field size, leading zeros kept.
+	size := (curve.Params().BitSize + 7) / 8
+	if len(xBytes) != size || len(yBytes) != size {
+		return nil, ergo.New("jwks: invalid EC coordinate length", slog.String("crv", crv))
+	}
+	// crypto/ecdh rejects points that are not on the curve (and the point at infinity).
+	uncompressed := append(append([]byte{4}, xBytes...), yBytes...)
+	if _, err := point.NewPublicKey(uncompressed); err != nil {
+		return nil, ergo.Wrap(err, "jwks: point is not on curve", slog.String("crv", crv))
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(xBytes), Y: new(big.Int).SetBytes(yBytes)}, nil
+}
+
+// EncodeJWK returns the JWKS entry for an RSA or ECDSA public key.
+func EncodeJWK(kid, alg string, pub crypto.PublicKey) (JWK, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA", Kid: kid, Use: "sig", Alg: alg,
+			N: base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return JWK{
+			Kty: "EC", Kid: kid, Use: "sig", Alg: alg, Crv: k.Curve.Params().Name,
+			X: base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, size))),
+			Y: base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, size))),
+		}, nil
+	}
+	return JWK{}, ergo.New("jwks: unsupported public key type")
+}
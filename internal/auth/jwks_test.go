@@ -0,0 +1,67 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+)
+
+func TestJWKSCache_ECKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ec key: %v", err)
+	}
+	valid, err := auth.EncodeJWK("valid", "ES256", &key.PublicKey)
+	if err != nil {
+		t.Fatalf("EncodeJWK() failed: %v", err)
+	}
+	offCurve := valid
+	offCurve.Kid = "off-curve"
+	y := new(big.Int).Add(key.Y, big.NewInt(1))
+	offCurve.Y = base64.RawURLEncoding.EncodeToString(y.FillBytes(make([]byte, 32)))
+	short := valid
+	short.Kid = "short"
+	short.X = base64.RawURLEncoding.EncodeToString(key.X.Bytes()[1:])
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []auth.JWK{valid, offCurve, short}})
+	}))
+	t.Cleanup(srv.Close)
+	cache := auth.NewJWKSCache(srv.URL, time.Minute)
+
+	tests := []struct {
+		name    string
+		kid     string
+		wantErr bool
+	}{
+		{"正常系: 曲線上の点のECキーを取得できること", "valid", false},
+		{"異常系: 曲線上に無い点のECキーは使われないこと", "off-curve", true},
+		{"異常系: 座標の長さが曲線と合わないECキーは使われないこと", "short", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cache.KeyFor(tt.kid)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("KeyFor(%q) = %v, want error", tt.kid, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("KeyFor(%q) failed: %v", tt.kid, err)
+			}
+			if pub, ok := got.(*ecdsa.PublicKey); !ok || !pub.Equal(&key.PublicKey) {
+				t.Fatalf("KeyFor(%q) = %v, want the generated key", tt.kid, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// ClientCertIdentity maps one client certificate identity to a Principal.
+// Exactly one of URI, DNS, CommonName and Email selects the certificate.
+type ClientCertIdentity struct {
+	// Name is recorded as Principal.Subject (e.g. billing-api).
+	Name string `json:"name"`
+	// URI matches a URI SAN, e.g. a SPIFFE ID (spiffe://mesh/ns/billing/sa/api).
+	URI        string   `json:"uri"`
+	DNS        string   `json:"dns"`
+	CommonName string   `json:"common_name"`
+	Email      string   `json:"email"`
+	Roles      []string `json:"roles"`
+	// Scopes are granted as permissions, like API key scopes.
+	Scopes   []string `json:"scopes"`
+	TenantID string   `json:"tenant"`
+}
+
+func (c ClientCertIdentity) matches(cert *x509.Certificate) bool {
+	switch {
+	case c.URI != "":
+		return slices.ContainsFunc(cert.URIs, func(u *url.URL) bool { return u.String() == c.URI })
+	case c.DNS != "":
+		return slices.Contains(cert.DNSNames, c.DNS)
+	case c.CommonName != "":
+		return cert.Subject.CommonName == c.CommonName
+	case c.Email != "":
+		return slices.Contains(cert.EmailAddresses, c.Email)
+	}
+	return false
+}
+
+// ClientCertMapping maps verified peer certificates to Principals.
+type ClientCertMapping struct {
+	identities []ClientCertIdentity
+}
+
+// NewClientCertMapping validates the identities; the first matching entry wins.
+func NewClientCertMapping(ids []ClientCertIdentity) (*ClientCertMapping, error) {
+	for i, c := range ids {
+		n := 0
+		for _, v := range []string{c.URI, c.DNS, c.CommonName, c.Email} {
+			if v != "" {
+				n++
+			}
+		}
+		if c.Name == "" || n != 1 {
+			return nil, ergo.New("client certificate identity needs a name and exactly one of uri/dns/common_name/email", slog.Int("index", i))
+		}
+	}
+	return &ClientCertMapping{identities: ids}, nil
+}
+
+// Len returns the number of configured identities.
+func (m *ClientCertMapping) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.identities)
+}
+
+// Principal returns the machine Principal for the certificate, or false when
+// no identity matches. The certificate must already be verified by the TLS stack.
+func (m *ClientCertMapping) Principal(cert *x509.Certificate) (*Principal, bool) {
+	if m == nil || cert == nil {
+		return nil, false
+	}
+	for _, c := range m.identities {
+		if !c.matches(cert) {
+			continue
+		}
+		return &Principal{
+			Subject:  c.Name,
+			Roles:    append([]string(nil), c.Roles...),
+			Scopes:   append([]string(nil), c.Scopes...),
+			TenantID: c.TenantID,
+			Provider: "mtls",
+			Machine:  true,
+			Claims: map[string]any{
+				"cert_subject": cert.Subject.String(),
+				"cert_serial":  cert.SerialNumber.String(),
+			},
+		}, true
+	}
+	return nil, false
+}
+
+// LoadClientCertMappingFromEnv reads AUTH_MTLS_IDENTITIES_FILE (path to a JSON
+// array of ClientCertIdentity) or AUTH_MTLS_IDENTITIES (the JSON itself).
+// It returns an empty mapping when neither is set.
+func LoadClientCertMappingFromEnv() (*ClientCertMapping, error) {
+	raw := os.Getenv("AUTH_MTLS_IDENTITIES")
+	if path := os.Getenv("AUTH_MTLS_IDENTITIES_FILE"); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, ergo.Wrap(err, "read AUTH_MTLS_IDENTITIES_FILE")
+		}
+		raw = string(b)
+	}
+	if strings.TrimSpace(raw) == "" {
+		return &ClientCertMapping{}, nil
+	}
+	var ids []ClientCertIdentity
+	if err := json.Unmarshal([]byte(raw), &ids); err != nil {
+		return nil, ergo.Wrap(err, "parse client certificate identities")
+	}
+	return NewClientCertMapping(ids)
+}
+
+var (
+	certMappingMu  sync.Mutex
+	certMapping    *ClientCertMapping
+	certMappingKey string
+)
+
+// ClientCertMappingFromEnv returns a process-wide mapping, reloaded only when the environment changes.
+func ClientCertMappingFromEnv() (*ClientCertMapping, error) {
+	key := os.Getenv("AUTH_MTLS_IDENTITIES_FILE") + "\x00" + os.Getenv("AUTH_MTLS_IDENTITIES")
+	certMappingMu.Lock()
+	defer certMappingMu.Unlock()
+	if certMapping != nil && certMappingKey == key {
+		return certMapping, nil
+	}
+	m, err := LoadClientCertMappingFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	certMapping, certMappingKey = m, key
+	return certMapping, nil
+}
+
+// LoadServerTLSConfigFromEnv reads TLS_CERT_FILE / TLS_KEY_FILE (server
+// certificate), TLS_CLIENT_CA_FILE (CA bundle that signs client certificates)
+// and TLS_CLIENT_AUTH (optional|require, default optional). It returns nil
+// when TLS_CERT_FILE is not set, i.e. the server listens on plain HTTP.
+func LoadServerTLSConfigFromEnv() (*tls.Config, error) {
+	certFile, keyFile := os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE")
+	if certFile == "" {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, ergo.Wrap(err, "load TLS_CERT_FILE/TLS_KEY_FILE")
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{cert}}
+	caFile := os.Getenv("TLS_CLIENT_CA_FILE")
+	if caFile == "" {
+		return cfg, nil
+	}
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, ergo.Wrap(err, "read TLS_CLIENT_CA_FILE")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, ergo.New("TLS_CLIENT_CA_FILE has no PEM certificates")
+	}
+	cfg.ClientCAs = pool
+	switch strings.ToLower(os.Getenv("TLS_CLIENT_AUTH")) {
+	case "", "optional":
+		// Callers without a certificate fall back to the other authenticators.
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	case "require":
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, ergo.New("invalid TLS_CLIENT_AUTH (optional|require)")
+	}
+	return cfg, nil
+}
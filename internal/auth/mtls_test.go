@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestClientCertMapping_Principal(t *testing.T) {
+	spiffe, _ := url.Parse("spiffe://mesh/ns/billing/sa/api")
+	m, err := NewClientCertMapping([]ClientCertIdentity{
+		{Name: "billing-api", URI: "spiffe://mesh/ns/billing/sa/api", Roles: []string{"service"}, Scopes: []string{"article.read"}},
+		{Name: "batch", CommonName: "batch.internal", Roles: []string{"admin"}},
+	})
+	if err != nil {
+		t.Fatalf("NewClientCertMapping() failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		cert      *x509.Certificate
+		wantName  string
+		wantRoles []string
+	}{
+		{name: "正常系: URI SAN（SPIFFE ID）で対応付けられること", cert: &x509.Certificate{URIs: []*url.URL{spiffe}}, wantName: "billing-api", wantRoles: []string{"service"}},
+		{name: "正常系: CommonNameで対応付けられること", cert: &x509.Certificate{Subject: pkix.Name{CommonName: "batch.internal"}}, wantName: "batch", wantRoles: []string{"admin"}},
+		{name: "異常系: 対応表に無い証明書はプリンシパルにならないこと", cert: &x509.Certificate{Subject: pkix.Name{CommonName: "unknown"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := m.Principal(tt.cert)
+			if tt.wantName == "" {
+				if ok {
+					t.Fatalf("want no principal, got %+v", p)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("want principal")
+			}
+			if p.Subject != tt.wantName || !slices.Equal(p.Roles, tt.wantRoles) || !p.Machine || p.Provider != "mtls" {
+				t.Fatalf("principal = %+v", p)
+			}
+		})
+	}
+}
+
+func TestLoadClientCertMappingFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantLen int
+		wantErr bool
+	}{
+		{name: "正常系: 未設定なら空の対応表になること", raw: ""},
+		{name: "正常系: JSON配列を読み込めること", raw: `[{"name":"batch","dns":"batch.internal","roles":["admin"]}]`, wantLen: 1},
+		{name: "異常系: 照合キーが無いエントリはエラーになること", raw: `[{"name":"batch","roles":["admin"]}]`, wantErr: true},
+		{name: "異常系: 照合キーが複数あるエントリはエラーになること", raw: `[{"name":"batch","dns":"a","common_name":"b"}]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AUTH_MTLS_IDENTITIES_FILE", "")
+			t.Setenv("AUTH_MTLS_IDENTITIES", tt.raw)
+			m, err := LoadClientCertMappingFromEnv()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadClientCertMappingFromEnv() failed: %v", err)
+			}
+			if m.Len() != tt.wantLen {
+				t.Fatalf("Len() = %d, want %d", m.Len(), tt.wantLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// OIDCClientConfig configures the authorization code + PKCE login used in BFF mode.
+type OIDCClientConfig struct {
+	// Provider is recorded on Principal.Provider / user_identities.provider.
+	Provider     string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the absolute URL of /auth/callback registered at the IdP.
+	RedirectURL string
+	Scopes      []string
+	// PostLoginRedirect / PostLogoutRedirect are where the browser goes afterwards.
+	PostLoginRedirect  string
+	PostLogoutRedirect string
+}
+
+// LoadOIDCClientConfigFromEnv reads OIDC_ISSUER, OIDC_CLIENT_ID, OIDC_CLIENT_SECRET,
+// OIDC_REDIRECT_URL, OIDC_SCOPES, OIDC_PROVIDER_NAME, OIDC_POST_LOGIN_REDIRECT and
+// OIDC_POST_LOGOUT_REDIRECT. It returns nil when OIDC_ISSUER is not set.
+func LoadOIDCClientConfigFromEnv() (*OIDCClientConfig, error) {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		return nil, nil
+	}
+	c := &OIDCClientConfig{
+		Provider:           os.Getenv("OIDC_PROVIDER_NAME"),
+		Issuer:             issuer,
+		ClientID:           os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret:       os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:        os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:             strings.Fields(os.Getenv("OIDC_SCOPES")),
+		PostLoginRedirect:  os.Getenv("OIDC_POST_LOGIN_REDIRECT"),
+		PostLogoutRedirect: os.Getenv("OIDC_POST_LOGOUT_REDIRECT"),
+	}
+	if c.ClientID == "" || c.RedirectURL == "" {
+		return nil, ergo.New("OIDC_ISSUER requires OIDC_CLIENT_ID and OIDC_REDIRECT_URL")
+	}
+	if c.Provider == "" {
+		c.Provider = "oidc"
+	}
+	if len(c.Scopes) == 0 {
+		c.Scopes = []string{"openid", "email", "profile"}
+	}
+	if c.PostLoginRedirect == "" {
+		c.PostLoginRedirect = "/"
+	}
+	if c.PostLogoutRedirect == "" {
+		c.PostLogoutRedirect = "/"
+	}
+	return c, nil
+}
+
+type oidcMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint"`
+}
+
+// OIDCClient is a relying party for the authorization code + PKCE flow.
+// Endpoints come from the issuer's discovery document; ID tokens are verified
+// with an IssuerSet (JWKS) whose audience is the client ID.
+type OIDCClient struct {
+	cfg    OIDCClientConfig
+	claims ClaimMapping
+	client *http.Client
+
+	mu       sync.Mutex
+	meta     *oidcMetadata
+	verifier *IssuerSet
+}
+
+// NewOIDCClient returns a client; discovery happens lazily on first use.
+func NewOIDCClient(cfg OIDCClientConfig, claims ClaimMapping) *OIDCClient {
+	return &OIDCClient{cfg: cfg, claims: claims, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Config returns the client configuration.
+func (c *OIDCClient) Config() OIDCClientConfig { return c.cfg }
+
+func (c *OIDCClient) discover(ctx context.Context) (*oidcMetadata, *IssuerSet, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.meta != nil {
+		return c.meta, c.verifier, nil
+	}
+	u := strings.TrimSuffix(c.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, ergo.Wrap(err, "build discovery request")
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, ergo.Wrap(err, "fetch discovery document", slog.String("url", u))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, ergo.New("discovery document not available", slog.String("url", u), slog.Int("status", resp.StatusCode))
+	}
+	var meta oidcMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return nil, nil, ergo.Wrap(err, "parse discovery document")
+	}
+	if meta.Issuer != c.cfg.Issuer {
+		return nil, nil, ergo.New("discovery issuer mismatch", slog.String("issuer", meta.Issuer))
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, nil, ergo.New("discovery document lacks required endpoints")
+	}
+	c.meta = &meta
+	c.verifier = NewIssuerSet([]IssuerConfig{{
+		Name:     c.cfg.Provider,
+		Issuer:   c.cfg.Issuer,
+		JWKSURL:  meta.JWKSURI,
+		Audience: c.cfg.ClientID,
+	}}, c.claims)
+	return c.meta, c.verifier, nil
+}
+
+// AuthCodeURL returns the IdP authorization URL for a new login.
+func (c *OIDCClient) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	meta, _, err := c.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.cfg.ClientID},
+		"redirect_uri":          {c.cfg.RedirectURL},
+		"scope":                 {strings.Join(c.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {PKCEChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// Exchange redeems the authorization code and returns the raw ID token.
+func (c *OIDCClient) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	meta, _, err := c.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.cfg.RedirectURL},
+		"client_id":     {c.cfg.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", ergo.Wrap(err, "build token request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.cfg.ClientID), url.QueryEscape(c.cfg.ClientSecret))
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", ergo.Wrap(err, "token request")
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", ergo.Wrap(err, "parse token response", slog.Int("status", resp.StatusCode))
+	}
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return "", ergo.New("token request rejected", slog.Int("status", resp.StatusCode), slog.String("error", body.Error), slog.String("description", body.ErrorDescription))
+	}
+	if body.IDToken == "" {
+		return "", ergo.New("token response has no id_token")
+	}
+	return body.IDToken, nil
+}
+
+// VerifyIDToken verifies the ID token (signature, iss, aud = client ID, exp)
+// and its nonce, and returns the Principal it asserts.
+func (c *OIDCClient) VerifyIDToken(ctx context.Context, raw, nonce string) (*Principal, error) {
+	_, verifier, err := c.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p, err := verifier.Verify(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+	if got, _ := p.Claims["nonce"].(string); nonce == "" || got != nonce {
+		return nil, ergo.New("id_token nonce mismatch")
+	}
+	return p, nil
+}
+
+// LogoutURL returns the IdP end-session URL when advertised, otherwise the
+// post-logout redirect.
+func (c *OIDCClient) LogoutURL(ctx context.Context) string {
+	meta, _, err := c.discover(ctx)
+	if err != nil || meta.EndSessionEndpoint == "" {
+		return c.cfg.PostLogoutRedirect
+	}
+	q := url.Values{"client_id": {c.cfg.ClientID}}
+	if strings.HasPrefix(c.cfg.PostLogoutRedirect, "http") {
+		q.Set("post_logout_redirect_uri", c.cfg.PostLogoutRedirect)
+	}
+	return meta.EndSessionEndpoint + "?" + q.Encode()
+}
+
+// RandomString returns a URL-safe random string of n random bytes
+// (used for state, nonce and PKCE code verifiers).
+func RandomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", ergo.Wrap(err, "generate random string")
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// PKCEChallenge returns the S256 code challenge for the verifier.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
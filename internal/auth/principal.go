@@ -5,9 +5,28 @@ import (
 )
 
 type Principal struct {
+	// UserID is the internal user ID. It is set from the subject only when the subject is numeric.
 	UserID int64
 	Email  string
 	Roles  []string
+	// Subject is the raw sub claim (or the mapped subject claim) as issued by the IdP.
+	Subject  string
+	TenantID string
+	// Provider is the name of the trusted issuer that authenticated the caller.
+	Provider string
+	// Issuer is the iss claim of the verified token.
+	Issuer string
+	// Claims holds the raw verified claims.
+	Claims map[string]any
+	// SessionID is set when the caller authenticated with a session cookie.
+	SessionID string
+	// Machine marks non-interactive callers (API keys); UserID is 0 for them.
+	Machine bool
+	// Scopes are the permissions granted directly to a machine principal.
+	Scopes []string
+	// Actor is the admin who is acting as this user (impersonation); nil
+	// when the user is calling for themselves. Audit logs should record both.
+	Actor *Principal
 }
 
 type ctxKey int
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// SessionConfig configures cookie sessions (BFF mode).
+type SessionConfig struct {
+	// CookieName holds the session ID (HttpOnly). Defaults to "__Host-session"
+	// when Secure and no Domain is set, otherwise "session".
+	CookieName string
+	// CSRFCookieName holds the CSRF token readable by the front-end (not HttpOnly).
+	CSRFCookieName string
+	// CSRFHeader must echo the CSRF token on mutating RPCs.
+	CSRFHeader string
+	Domain     string
+	Secure     bool
+	SameSite   http.SameSite
+	// TTL is the idle timeout and MaxLifetime the absolute limit (zero: usecase defaults).
+	TTL         time.Duration
+	MaxLifetime time.Duration
+}
+
+// LoadSessionConfigFromEnv reads SESSION_COOKIE_NAME, SESSION_COOKIE_DOMAIN,
+// SESSION_COOKIE_SECURE (default on; "0" for local http), SESSION_COOKIE_SAMESITE
+// (lax|strict|none, default lax), SESSION_TTL and SESSION_MAX_LIFETIME.
+func LoadSessionConfigFromEnv() (*SessionConfig, error) {
+	c := &SessionConfig{
+		CookieName:     os.Getenv("SESSION_COOKIE_NAME"),
+		CSRFCookieName: "csrf_token",
+		CSRFHeader:     "X-CSRF-Token",
+		Domain:         os.Getenv("SESSION_COOKIE_DOMAIN"),
+		Secure:         os.Getenv("SESSION_COOKIE_SECURE") != "0",
+		SameSite:       http.SameSiteLaxMode,
+	}
+	switch strings.ToLower(os.Getenv("SESSION_COOKIE_SAMESITE")) {
+	case "", "lax":
+	case "strict":
+		c.SameSite = http.SameSiteStrictMode
+	case "none":
+		if !c.Secure {
+			return nil, ergo.New("SESSION_COOKIE_SAMESITE=none requires secure cookies")
+		}
+		c.SameSite = http.SameSiteNoneMode
+	default:
+		return nil, ergo.New("invalid SESSION_COOKIE_SAMESITE (lax|strict|none)")
+	}
+	if c.CookieName == "" {
+		c.CookieName = "session"
+		if c.Secure && c.Domain == "" {
+			c.CookieName = "__Host-session"
+		}
+	}
+	for key, dst := range map[string]*time.Duration{"SESSION_TTL": &c.TTL, "SESSION_MAX_LIFETIME": &c.MaxLifetime} {
+		if s := os.Getenv(key); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return nil, ergo.Wrap(err, "parse "+key)
+			}
+			*dst = d
+		}
+	}
+	return c, nil
+}
+
+var (
+	sessionCfgMu  sync.Mutex
+	sessionCfg    *SessionConfig
+	sessionCfgKey string
+)
+
+// SessionConfigFromEnv returns a process-wide SessionConfig, reloaded only when the environment changes.
+func SessionConfigFromEnv() (*SessionConfig, error) {
+	var key strings.Builder
+	for _, k := range []string{"SESSION_COOKIE_NAME", "SESSION_COOKIE_DOMAIN", "SESSION_COOKIE_SECURE", "SESSION_COOKIE_SAMESITE", "SESSION_TTL", "SESSION_MAX_LIFETIME"} {
+		key.WriteString(os.Getenv(k) + "\x00")
+	}
+	sessionCfgMu.Lock()
+	defer sessionCfgMu.Unlock()
+	if sessionCfg != nil && sessionCfgKey == key.String() {
+		return sessionCfg, nil
+	}
+	c, err := LoadSessionConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	sessionCfg, sessionCfgKey = c, key.String()
+	return sessionCfg, nil
+}
+
+// SessionCookie returns the HttpOnly cookie carrying the session ID.
+func (c *SessionConfig) SessionCookie(id string, expires time.Time) *http.Cookie {
+	return c.cookie(c.CookieName, id, expires, true)
+}
+
+// CSRFCookie returns the cookie carrying the CSRF token. It is readable by
+// scripts so that the front-end can copy it into the CSRF header.
+func (c *SessionConfig) CSRFCookie(token string, expires time.Time) *http.Cookie {
+	return c.cookie(c.CSRFCookieName, token, expires, false)
+}
+
+// ClearCookies returns cookies that delete the session and CSRF cookies (logout).
+func (c *SessionConfig) ClearCookies() []*http.Cookie {
+	out := []*http.Cookie{c.SessionCookie("", time.Time{}), c.CSRFCookie("", time.Time{})}
+	for _, ck := range out {
+		ck.MaxAge = -1
+	}
+	return out
+}
+
+func (c *SessionConfig) cookie(name, value string, expires time.Time, httpOnly bool) *http.Cookie {
+	ck := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		Secure:   c.Secure,
+		HttpOnly: httpOnly,
+		SameSite: c.SameSite,
+	}
+	// "__Host-" cookies must not carry a Domain attribute.
+	if !strings.HasPrefix(name, "__Host-") {
+		ck.Domain = c.Domain
+	}
+	return ck
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSessionConfig_Cookies(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		wantName string
+		secure   bool
+		domain   string
+		wantErr  bool
+	}{
+		{name: "正常系: 既定では__Host-プレフィックス付きのSecure Cookieになること", wantName: "__Host-session", secure: true},
+		{name: "正常系: ドメイン指定時はプレフィックス無しでDomainが付くこと", env: map[string]string{"SESSION_COOKIE_DOMAIN": "example.com"}, wantName: "session", secure: true, domain: "example.com"},
+		{name: "正常系: ローカルHTTP向けにSecureを外せること", env: map[string]string{"SESSION_COOKIE_SECURE": "0"}, wantName: "session"},
+		{name: "異常系: SameSite=NoneはSecure無しでは使えないこと", env: map[string]string{"SESSION_COOKIE_SECURE": "0", "SESSION_COOKIE_SAMESITE": "none"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, k := range []string{"SESSION_COOKIE_NAME", "SESSION_COOKIE_DOMAIN", "SESSION_COOKIE_SECURE", "SESSION_COOKIE_SAMESITE"} {
+				t.Setenv(k, tt.env[k])
+			}
+			c, err := LoadSessionConfigFromEnv()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadSessionConfigFromEnv() failed: %v", err)
+			}
+			sc := c.SessionCookie("sid", time.Now().Add(time.Hour))
+			if sc.Name != tt.wantName || sc.Secure != tt.secure || sc.Domain != tt.domain || !sc.HttpOnly || sc.Path != "/" || sc.SameSite != http.SameSiteLaxMode {
+				t.Fatalf("session cookie = %+v", sc)
+			}
+			if c.CSRFCookie("tok", time.Now()).HttpOnly {
+				t.Fatal("csrf cookie must be readable by scripts")
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"slices"
+	"time"
+)
+
+// Unmet step-up requirements reported by StepUpRule.Unmet.
+const (
+	StepUpACR    = "acr"
+	StepUpAMR    = "amr"
+	StepUpMaxAge = "max_age"
+)
+
+// StepUpRule requires a recent, strong login to call a procedure.
+type StepUpRule struct {
+	// ACR: the acr claim must be one of them (empty: any).
+	ACR []string `json:"acr"`
+	// AMR: the amr claim must contain all of them.
+	AMR []string `json:"amr"`
+	// MaxAgeSeconds: auth_time must be at most this old (0: no limit).
+	MaxAgeSeconds int64 `json:"max_age_seconds"`
+}
+
+// IsZero reports whether the rule has no requirement.
+func (r StepUpRule) IsZero() bool {
+	return len(r.ACR) == 0 && len(r.AMR) == 0 && r.MaxAgeSeconds <= 0
+}
+
+// Unmet returns the first requirement the principal does not meet (StepUpACR,
+// StepUpAMR or StepUpMaxAge), or "" when the rule is satisfied. Principals
+// without the claims (API keys, sessions) never meet a non-zero rule.
+func (r StepUpRule) Unmet(p *Principal, now time.Time) string {
+	var claims map[string]any
+	if p != nil {
+		claims = p.Claims
+	}
+	if len(r.ACR) > 0 {
+		acr, _ := claims["acr"].(string)
+		if !slices.Contains(r.ACR, acr) {
+			return StepUpACR
+		}
+	}
+	if len(r.AMR) > 0 {
+		amr := stringsClaim(claims["amr"])
+		for _, m := range r.AMR {
+			if !slices.Contains(amr, m) {
+				return StepUpAMR
+			}
+		}
+	}
+	if r.MaxAgeSeconds > 0 {
+		at, ok := claims["auth_time"].(float64)
+		if !ok || now.Sub(time.Unix(int64(at), 0)) > time.Duration(r.MaxAgeSeconds)*time.Second {
+			return StepUpMaxAge
+		}
+	}
+	return ""
+}
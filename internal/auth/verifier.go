@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/newmo-oss/ergo"
+)
+
+// ProviderHS256 is the Principal.Provider of tokens verified with AUTH_HS256_SECRET.
+const ProviderHS256 = "hs256"
+
+// ErrNotHandled is returned by a Verifier for tokens it is not responsible
+// for (e.g. an RS256 token given to the HS256 verifier), so that the next
+// verifier in the chain is tried.
+var ErrNotHandled = ergo.NewSentinel("token not handled by this verifier")
+
+// Sentinels wrapped by verification errors, so that callers can tell why a
+// token was rejected (see also the jwt.Err* errors of golang-jwt).
+var (
+	ErrUnknownKID      = ergo.NewSentinel("unknown kid")
+	ErrUntrustedIssuer = ergo.NewSentinel("untrusted issuer")
+)
+
+// Verifier verifies a bearer token and returns the Principal it asserts.
+type Verifier interface {
+	Verify(ctx context.Context, token string) (*Principal, error)
+}
+
+// Verifiers tries each Verifier in order until one handles the token.
+type Verifiers []Verifier
+
+// Verify implements Verifier.
+func (vs Verifiers) Verify(ctx context.Context, token string) (*Principal, error) {
+	for _, v := range vs {
+		p, err := v.Verify(ctx, token)
+		if errors.Is(err, ErrNotHandled) {
+			continue
+		}
+		return p, err
+	}
+	return nil, ergo.New("no verifier accepts the token")
+}
+
+// jwtRules are the claim checks shared by every JWT verifier.
+type jwtRules struct {
+	methods   []string
+	issuer    string
+	audience  string
+	clockSkew time.Duration
+}
+
+// parseJWT verifies the signature and the standard claims: exp (required),
+// nbf and iat with clock skew, and iss / aud when configured.
+func parseJWT(tokenString string, r jwtRules, key jwt.Keyfunc) (jwt.MapClaims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(r.methods),
+		jwt.WithLeeway(r.clockSkew),
+		jwt.WithExpirationRequired(),
+	}
+	if r.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(r.issuer))
+	}
+	if r.audience != "" {
+		opts = append(opts, jwt.WithAudience(r.audience))
+	}
+	var claims jwt.MapClaims
+	token, err := jwt.NewParser(opts...).ParseWithClaims(tokenString, &claims, key)
+	if err != nil {
+		return nil, ergo.Wrap(err, "invalid token")
+	}
+	if !token.Valid {
+		return nil, ergo.New("invalid token")
+	}
+	return claims, nil
+}
+
+// tokenAlg returns the (unverified) alg header of the token.
+func tokenAlg(tokenString string) (string, error) {
+	tok, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return "", ergo.Wrap(err, "malformed token")
+	}
+	alg, _ := tok.Header["alg"].(string)
+	return alg, nil
+}
+
+// HS256Config configures tokens signed with shared secrets.
+type HS256Config struct {
+	// Secret verifies tokens without a kid header (single-secret mode).
+	Secret string
+	// Keys is the keyring; tokens with a kid header are verified with the
+	// key of that ID, whether it is active or verify-only.
+	Keys      []HS256Key
+	Issuer    string
+	Audience  string
+	ClockSkew time.Duration
+}
+
+// HS256Verifier verifies tokens signed with a shared secret (self-issued
+// tokens whose sub is the internal user ID).
+type HS256Verifier struct {
+	cfg    HS256Config
+	claims ClaimMapping
+}
+
+// NewHS256Verifier returns a verifier; a zero ClockSkew uses the default (60s).
+func NewHS256Verifier(cfg HS256Config, claims ClaimMapping) *HS256Verifier {
+	if cfg.ClockSkew == 0 {
+		cfg.ClockSkew = defaultClockSkew
+	}
+	return &HS256Verifier{cfg: cfg, claims: claims}
+}
+
+// Verify implements Verifier. Tokens not signed with HMAC are ErrNotHandled.
+func (v *HS256Verifier) Verify(ctx context.Context, tokenString string) (*Principal, error) {
+	alg, err := tokenAlg(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(alg, "HS") {
+		return nil, ErrNotHandled
+	}
+	rules := jwtRules{methods: []string{"HS256", "HS384", "HS512"}, issuer: v.cfg.Issuer, audience: v.cfg.Audience, clockSkew: v.cfg.ClockSkew}
+	claims, err := parseJWT(tokenString, rules, v.secretFor)
+	if err != nil {
+		return nil, ergo.Wrap(err, "verify token", slog.String("provider", ProviderHS256))
+	}
+	p, err := v.claims.Principal(claims)
+	if err != nil {
+		return nil, ergo.Wrap(err, "map claims", slog.String("provider", ProviderHS256))
+	}
+	p.Provider = ProviderHS256
+	return p, nil
+}
+
+// secretFor selects the secret by the kid header.
+func (v *HS256Verifier) secretFor(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		if v.cfg.Secret == "" {
+			return nil, ergo.Wrap(ErrUnknownKID, "token has no kid")
+		}
+		return []byte(v.cfg.Secret), nil
+	}
+	for _, k := range v.cfg.Keys {
+		if k.ID == kid {
+			return []byte(k.Secret), nil
+		}
+	}
+	return nil, ergo.Wrap(ErrUnknownKID, "select HS256 key", slog.String("kid", kid))
+}
+
+// LoadHS256ConfigFromEnv reads the secret (AUTH_HS256_SECRET or
+// AUTH_HS256_SECRET_FILE), the keyring (see LoadHS256KeysFromEnv),
+// AUTH_HS256_ISSUER, AUTH_HS256_AUDIENCE and AUTH_CLOCK_SKEW. It returns nil
+// when neither a secret nor a keyring is set.
+func LoadHS256ConfigFromEnv() (*HS256Config, error) {
+	secret := os.Getenv("AUTH_HS256_SECRET")
+	if path := os.Getenv("AUTH_HS256_SECRET_FILE"); path != "" {
+		s, err := readSecretFile(path)
+		if err != nil {
+			return nil, ergo.Wrap(err, "read AUTH_HS256_SECRET_FILE")
+		}
+		secret = s
+	}
+	keys, err := LoadHS256KeysFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if secret == "" && len(keys) == 0 {
+		return nil, nil
+	}
+	c := &HS256Config{
+		Secret:   secret,
+		Keys:     keys,
+		Issuer:   os.Getenv("AUTH_HS256_ISSUER"),
+		Audience: os.Getenv("AUTH_HS256_AUDIENCE"),
+	}
+	if s := os.Getenv("AUTH_CLOCK_SKEW"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, ergo.Wrap(err, "parse AUTH_CLOCK_SKEW")
+		}
+		c.ClockSkew = d
+	}
+	return c, nil
+}
+
+var (
+	verifiersMu  sync.Mutex
+	verifiers    Verifiers
+	verifiersKey string
+	// verifiersFor is the IssuerSet the chain was built with.
+	verifiersFor *IssuerSet
+)
+
+// VerifiersFromEnv returns the bearer token verifiers configured in the
+// environment, in order: trusted issuers (JWKS), then HS256. The chain is
+// empty when neither is configured. It is rebuilt only when the environment
+// changes; secret files are read at that point, so edited files take effect
+// on restart.
+func VerifiersFromEnv() (Verifiers, error) {
+	issuers, err := IssuerSetFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	key := strings.Join([]string{
+		os.Getenv("AUTH_HS256_SECRET"), os.Getenv("AUTH_HS256_SECRET_FILE"),
+		os.Getenv("AUTH_HS256_KEYS"), os.Getenv("AUTH_HS256_KEYS_FILE"),
+		os.Getenv("AUTH_HS256_ISSUER"), os.Getenv("AUTH_HS256_AUDIENCE"),
+		os.Getenv("AUTH_CLOCK_SKEW"), os.Getenv("AUTH_CLAIM_MAPPING"),
+	}, "\x00")
+	verifiersMu.Lock()
+	defer verifiersMu.Unlock()
+	if verifiersFor == issuers && verifiersKey == key {
+		return verifiers, nil
+	}
+	var vs Verifiers
+	if issuers.Len() > 0 {
+		vs = append(vs, issuers)
+	}
+	hs, err := LoadHS256ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if hs != nil {
+		claims, err := LoadClaimMappingFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		vs = append(vs, NewHS256Verifier(*hs, claims))
+	}
+	verifiers, verifiersKey, verifiersFor = vs, key, issuers
+	return verifiers, nil
+}
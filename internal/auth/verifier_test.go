@@ -0,0 +1,205 @@
+package auth_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/xiao1203/go-onion-grpc-template/internal/auth"
+)
+
+func TestHS256Verifier_Verify(t *testing.T) {
+	v := auth.NewHS256Verifier(auth.HS256Config{Secret: "secret", Issuer: "https://app.example", Audience: "api"}, auth.ClaimMapping{})
+	sign := func(claims jwt.MapClaims) string {
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		return s
+	}
+	now := time.Now()
+	exp := now.Add(5 * time.Minute).Unix()
+
+	tests := []struct {
+		name    string
+		claims  jwt.MapClaims
+		wantErr bool
+	}{
+		{name: "正常系: iss/aud/expが正しいトークンを検証できること", claims: jwt.MapClaims{"iss": "https://app.example", "aud": "api", "sub": "1", "exp": exp}},
+		{name: "異常系: audが一致しない場合は拒否されること", claims: jwt.MapClaims{"iss": "https://app.example", "aud": "other", "sub": "1", "exp": exp}, wantErr: true},
+		{name: "異常系: issが一致しない場合は拒否されること", claims: jwt.MapClaims{"iss": "https://evil.example", "aud": "api", "sub": "1", "exp": exp}, wantErr: true},
+		{name: "異常系: nbfが未来の場合は拒否されること", claims: jwt.MapClaims{"iss": "https://app.example", "aud": "api", "sub": "1", "exp": exp, "nbf": now.Add(time.Hour).Unix()}, wantErr: true},
+		{name: "異常系: expが無いトークンは拒否されること", claims: jwt.MapClaims{"iss": "https://app.example", "aud": "api", "sub": "1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := v.Verify(context.Background(), sign(tt.claims))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Verify() succeeded unexpectedly")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() failed: %v", err)
+			}
+			if p.UserID != 1 || p.Provider != auth.ProviderHS256 {
+				t.Fatalf("principal = %+v", p)
+			}
+		})
+	}
+}
+
+func TestVerifiers_Chain(t *testing.T) {
+	staff := newTestIssuer(t, "staff-key")
+	chain := auth.Verifiers{
+		auth.NewIssuerSet([]auth.IssuerConfig{{Name: "keycloak", Issuer: "https://kc.example", JWKSURL: staff.srv.URL}}, auth.ClaimMapping{}),
+		auth.NewHS256Verifier(auth.HS256Config{Secret: "secret"}, auth.ClaimMapping{}),
+	}
+	exp := time.Now().Add(5 * time.Minute).Unix()
+	hs, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "1", "exp": exp}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		token        string
+		wantProvider string
+	}{
+		{name: "正常系: RS256トークンはJWKSの発行者で検証されること", token: staff.sign(t, jwt.MapClaims{"iss": "https://kc.example", "sub": "u-1", "exp": exp}), wantProvider: "keycloak"},
+		{name: "正常系: HS256トークンは次のHS256検証器で検証されること", token: hs, wantProvider: auth.ProviderHS256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := chain.Verify(context.Background(), tt.token)
+			if err != nil {
+				t.Fatalf("Verify() failed: %v", err)
+			}
+			if p.Provider != tt.wantProvider {
+				t.Fatalf("Provider = %q, want %q", p.Provider, tt.wantProvider)
+			}
+		})
+	}
+
+	t.Run("異常系: どの検証器も扱わないトークンは拒否されること", func(t *testing.T) {
+		if _, err := chain[:1].Verify(context.Background(), hs); err == nil {
+			t.Fatal("Verify() succeeded unexpectedly")
+		}
+	})
+}
+
+func TestHS256Verifier_Keyring(t *testing.T) {
+	dir := t.TempDir()
+	newFile := filepath.Join(dir, "new.key")
+	if err := os.WriteFile(newFile, []byte("new-secret\n"), 0o600); err != nil {
+		t.Fatalf("write secret: %v", err)
+	}
+	t.Setenv("AUTH_HS256_KEYS_FILE", "")
+	t.Setenv("AUTH_HS256_KEYS", `[{"kid":"2026-10","status":"active","secret_file":"`+newFile+`"},{"kid":"2026-09","status":"verify","secret":"old-secret"}]`)
+	keys, err := auth.LoadHS256KeysFromEnv()
+	if err != nil {
+		t.Fatalf("LoadHS256KeysFromEnv() failed: %v", err)
+	}
+	if k, ok := auth.ActiveHS256Key(keys); !ok || k.ID != "2026-10" || k.Secret != "new-secret" {
+		t.Fatalf("active key = %+v", k)
+	}
+	v := auth.NewHS256Verifier(auth.HS256Config{Keys: keys}, auth.ClaimMapping{})
+	sign := func(kid, secret string) string {
+		tok := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "1", "exp": time.Now().Add(time.Minute).Unix()})
+		if kid != "" {
+			tok.Header["kid"] = kid
+		}
+		s, err := tok.SignedString([]byte(secret))
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "正常系: activeキーで署名したトークンを検証できること", token: sign("2026-10", "new-secret")},
+		{name: "正常系: verify専用キーで署名した旧トークンも検証できること", token: sign("2026-09", "old-secret")},
+		{name: "異常系: 未知のkidは拒否されること", token: sign("2026-08", "old-secret"), wantErr: true},
+		{name: "異常系: kidと異なるキーで署名したトークンは拒否されること", token: sign("2026-10", "old-secret"), wantErr: true},
+		{name: "異常系: キーリングのみの場合kid無しトークンは拒否されること", token: sign("", "new-secret"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.Verify(context.Background(), tt.token)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Verify() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadHS256KeysFromEnv_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "異常系: activeキーが無い場合はエラーになること", raw: `[{"kid":"a","status":"verify","secret":"s"}]`},
+		{name: "異常系: activeキーが複数ある場合はエラーになること", raw: `[{"kid":"a","status":"active","secret":"s"},{"kid":"b","status":"active","secret":"t"}]`},
+		{name: "異常系: kidが重複している場合はエラーになること", raw: `[{"kid":"a","status":"active","secret":"s"},{"kid":"a","status":"verify","secret":"t"}]`},
+		{name: "異常系: 不明なstatusはエラーになること", raw: `[{"kid":"a","status":"disabled","secret":"s"}]`},
+		{name: "異常系: secretの無いキーはエラーになること", raw: `[{"kid":"a","status":"active"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AUTH_HS256_KEYS_FILE", "")
+			t.Setenv("AUTH_HS256_KEYS", tt.raw)
+			if _, err := auth.LoadHS256KeysFromEnv(); err == nil {
+				t.Fatal("want error")
+			}
+		})
+	}
+}
+
+func TestHS256Signer_RoundTrip(t *testing.T) {
+	mapping := auth.ClaimMapping{Roles: "realm_access.roles"}
+	tests := []struct {
+		name string
+		cfg  auth.HS256Config
+	}{
+		{"正常系: シークレットで署名したトークンを検証できること", auth.HS256Config{Secret: "secret", Issuer: "local", Audience: "api"}},
+		{"正常系: アクティブキーのkidで署名したトークンを検証できること", auth.HS256Config{Keys: []auth.HS256Key{
+			{ID: "old", Status: auth.HS256KeyVerifyOnly, Secret: "old-secret"},
+			{ID: "new", Status: auth.HS256KeyActive, Secret: "new-secret"},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := auth.NewHS256Signer(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewHS256Signer() failed: %v", err)
+			}
+			claims := mapping.Claims("42", "a@example.com", []string{"admin"})
+			claims["exp"] = time.Now().Add(time.Minute).Unix()
+			token, err := s.Sign(claims)
+			if err != nil {
+				t.Fatalf("Sign() failed: %v", err)
+			}
+			p, err := auth.NewHS256Verifier(tt.cfg, mapping).Verify(context.Background(), token)
+			if err != nil {
+				t.Fatalf("Verify() failed: %v", err)
+			}
+			if p.UserID != 42 || p.Email != "a@example.com" || len(p.Roles) != 1 || p.Roles[0] != "admin" {
+				t.Fatalf("principal = %+v", p)
+			}
+		})
+	}
+
+	t.Run("異常系: シークレットもアクティブキーも無い場合はエラーになること", func(t *testing.T) {
+		if _, err := auth.NewHS256Signer(auth.HS256Config{}); err == nil {
+			t.Fatal("want error")
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// APIKey is a credential for non-interactive (service-to-service) callers.
+// Only the SHA-256 hash of the secret is stored.
+type APIKey struct {
+	ID          int64
+	Name        string
+	Prefix      string
+	KeyHash     string
+	OwnerUserID int64
+	Scopes      []string
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+}
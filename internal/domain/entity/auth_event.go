@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// Outcomes of an AuthEvent.
+const (
+	AuthEventSuccess = "success"
+	AuthEventFailure = "failure"
+)
+
+// AuthEvent is one authentication attempt seen by the auth interceptor.
+// Issuer and Subject of failed attempts are taken from the unverified token
+// and must not be trusted.
+type AuthEvent struct {
+	ID      int64
+	Outcome string
+	// Reason explains a failure (e.g. "expired", "unknown_kid"); empty on success.
+	Reason     string
+	Method     string
+	Provider   string
+	Issuer     string
+	Subject    string
+	UserID     int64
+	Procedure  string
+	IP         string
+	UserAgent  string
+	OccurredAt time.Time
+}
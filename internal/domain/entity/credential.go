@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Credential is the local email/password login of a user. Only the argon2id
+// hash of the password is stored.
+type Credential struct {
+	UserID       int64
+	Email        string
+	PasswordHash string
+	// FailedAttempts counts consecutive failed logins since the last success or lock.
+	FailedAttempts    int
+	LockedUntil       *time.Time
+	PasswordChangedAt time.Time
+}
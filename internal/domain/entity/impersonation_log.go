@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// ImpersonationLog is the audit record of one call made by an admin acting as
+// another user. Denied attempts are recorded too.
+type ImpersonationLog struct {
+	ID           int64
+	ActorUserID  int64
+	ActorSubject string
+	TargetUserID int64
+	Procedure    string
+	Reason       string
+	Allowed      bool
+	DeniedReason string
+	CreatedAt    time.Time
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// Session is a server-side login session used in BFF + cookie mode.
+type Session struct {
+	// ID is the session ID sent in the cookie. Repositories receive and
+	// return its SHA-256 instead (see usecase.SessionUsecase).
+	ID        string
+	UserID    int64
+	UserAgent string
+	IPAddress string
+	// CSRFToken must be echoed in X-CSRF-Token on mutating RPCs.
+	CSRFToken string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
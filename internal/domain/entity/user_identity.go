@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// UserIdentity links an external IdP account (issuer, subject) to a User.
+type UserIdentity struct {
+	ID              int64
+	UserID          int64
+	Provider        string
+	Issuer          string
+	Subject         string
+	EmailAtProvider string
+	ConnectedAt     time.Time
+	LastLoginAt     *time.Time
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+)
+
+// APIKeyRepository はAPIキー（api_keys）を扱うポートです。
+type APIKeyRepository interface {
+	// Create はキーを保存し、採番されたIDを設定して返します。
+	Create(ctx context.Context, k *entity.APIKey) (*entity.APIKey, error)
+	// FindByHash はキー平文のハッシュに対応するキーを返します。存在しない場合は nil, nil。
+	FindByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+	// List はすべてのキー（失効済みを含む）を新しい順に返します。
+	List(ctx context.Context) ([]*entity.APIKey, error)
+	// Revoke はキーを失効させます。存在しない場合は apperr.NotFound を返します。
+	Revoke(ctx context.Context, id int64, at time.Time) error
+	// TouchLastUsed は最終利用時刻を更新します。
+	TouchLastUsed(ctx context.Context, id int64, at time.Time) error
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+)
+
+// AuthEventFilter は認証イベントの検索条件です。ゼロ値の項目は条件に含めません。
+type AuthEventFilter struct {
+	UserID  int64
+	Subject string
+	Issuer  string
+	Outcome string
+	Reason  string
+	IP      string
+	// Since 以降・Until より前に発生したイベントに絞り込みます。
+	Since time.Time
+	Until time.Time
+	// BeforeID より小さいIDのイベントのみを返します（ページング用）。
+	BeforeID int64
+	Limit    int
+}
+
+// AuthEventRepository は認証イベント（auth_events）を扱うポートです。
+type AuthEventRepository interface {
+	// CreateBatch はイベントをまとめて記録します。一部のイベントが記録できなくても
+	// 残りは記録し、失敗した件数をエラーで返します。
+	CreateBatch(ctx context.Context, events []*entity.AuthEvent) error
+	// Search は条件に一致するイベントを新しい順（ID降順）に最大 Limit 件返します。
+	Search(ctx context.Context, f AuthEventFilter) ([]*entity.AuthEvent, error)
+}
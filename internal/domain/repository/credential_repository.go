@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+)
+
+// CredentialRepository はローカル認証のパスワード（user_credentials）とリセットトークン（password_reset_tokens）を扱うポートです。
+type CredentialRepository interface {
+	// Create は users と user_credentials を同一トランザクションで作成します。
+	// メールアドレスが登録済みの場合は apperr.Conflict を返します。
+	Create(ctx context.Context, user *entity.User, passwordHash string, at time.Time) (*entity.Credential, error)
+	// FindByEmail は有効なユーザーの資格情報をメールアドレスで返します。存在しない場合は nil, nil。
+	FindByEmail(ctx context.Context, email string) (*entity.Credential, error)
+	// FindByUserID は有効なユーザーの資格情報を返します。存在しない場合は nil, nil。
+	FindByUserID(ctx context.Context, userID int64) (*entity.Credential, error)
+	// RecordFailure は失敗回数を原子的に1増やし、maxFailures に達したら lockUntil までロックして回数を0に戻します。
+	RecordFailure(ctx context.Context, userID int64, maxFailures int, lockUntil time.Time) error
+	// RecordSuccess は失敗回数とロックを解除し、users.last_login_at を更新します。
+	RecordSuccess(ctx context.Context, userID int64, at time.Time) error
+	// UpdatePassword はパスワードを変更し、失敗回数とロックを解除します。
+	UpdatePassword(ctx context.Context, userID int64, passwordHash string, at time.Time) error
+	// CreateResetToken はリセットトークンのハッシュを保存します。
+	CreateResetToken(ctx context.Context, userID int64, tokenHash string, expiresAt, at time.Time) error
+	// ConsumeResetToken は未使用・期限内のトークンを使用済みにし、そのユーザーIDを返します。該当しない場合は 0, nil。
+	ConsumeResetToken(ctx context.Context, tokenHash string, at time.Time) (int64, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+)
+
+// ImpersonationLogRepository はなりすまし呼び出しの監査ログ（impersonation_logs）を扱うポートです。
+type ImpersonationLogRepository interface {
+	// Create は監査ログを1件記録します。
+	Create(ctx context.Context, log *entity.ImpersonationLog) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// RoleRepository はユーザーへのロール付与（roles / user_roles）を扱うポートです。
+type RoleRepository interface {
+	// RolesForUser はユーザーに付与されたロール名を返します。付与がない場合は空です。
+	RolesForUser(ctx context.Context, userID int64) ([]string, error)
+	// Grant はロールを付与します。ロールが未定義の場合は NotFound、付与済みの場合は何もしません。
+	Grant(ctx context.Context, userID int64, role string, at time.Time) error
+	// Revoke はロールの付与を取り消します。付与されていない場合は何もしません。
+	Revoke(ctx context.Context, userID int64, role string) error
+}
@@ -8,6 +8,7 @@ import (
 
 // SampleRepository は Sample 集約の永続化境界を表すドメイン側のポートです。
 // アダプタ層（mysql/memory 等）はこのインターフェースを実装します。
+// Get/Update/Delete は対象の ID が存在しない場合 apperr.NotFound のエラー（ResourceInfo 付き）を返します（nil, nil は返しません）。
 type SampleRepository interface {
 	Create(ctx context.Context, in *entity.Sample) (*entity.Sample, error)
 	Get(ctx context.Context, id int64) (*entity.Sample, error)
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+)
+
+// SessionRepository はサーバサイドセッション（sessions）を扱うポートです。
+// id はCookieのセッションIDそのものではなく、その SHA-256（16進64文字）です。
+type SessionRepository interface {
+	// Create はセッションを保存します。
+	Create(ctx context.Context, s *entity.Session) error
+	// FindByID はセッションを返します。存在しない場合は nil, nil。
+	FindByID(ctx context.Context, id string) (*entity.Session, error)
+	// Extend は有効期限を延長します（スライディング）。
+	Extend(ctx context.Context, id string, expiresAt time.Time) error
+	// Revoke はセッションを失効させます。失効済み・存在しない場合も成功扱いです。
+	Revoke(ctx context.Context, id string, at time.Time) error
+	// RevokeAllForUser はユーザーの有効なセッションをすべて失効させます。
+	RevokeAllForUser(ctx context.Context, userID int64, at time.Time) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// TokenRevocationRepository はJWTの失効情報（revoked_tokens / user_token_revocations）を扱うポートです。
+type TokenRevocationRepository interface {
+	// RevokeJTI は jti を失効させます。expiresAt はトークン本来の有効期限です（掃除用）。
+	RevokeJTI(ctx context.Context, jti string, userID int64, expiresAt, at time.Time) error
+	// IsJTIRevoked は jti が失効済みかを返します。
+	IsJTIRevoked(ctx context.Context, jti string) (bool, error)
+	// RevokeUserBefore は before 以前に発行されたユーザーのトークンをすべて無効にします（既存値より過去には戻しません）。
+	RevokeUserBefore(ctx context.Context, userID int64, before time.Time) error
+	// RevokedBefore はユーザーの失効下限時刻を返します。未設定の場合は nil, nil。
+	RevokedBefore(ctx context.Context, userID int64) (*time.Time, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+)
+
+// UserIdentityRepository は外部IdPのID（issuer, subject）とユーザーのひも付けを扱うポートです。
+type UserIdentityRepository interface {
+	// FindByIssuerSubject は (issuer, subject) に対応するひも付けを返します。存在しない場合は nil, nil。
+	FindByIssuerSubject(ctx context.Context, issuer, subject string) (*entity.UserIdentity, error)
+	// Provision は users と user_identities を同一トランザクションで作成します。
+	// 一意制約に違反した場合は apperr.Conflict を返します。
+	Provision(ctx context.Context, user *entity.User, identity *entity.UserIdentity) (*entity.UserIdentity, error)
+	// TouchLogin はログイン時刻とプロバイダ側メールを更新します（users.last_login_at も更新）。
+	TouchLogin(ctx context.Context, identity *entity.UserIdentity, emailAtProvider string, at time.Time) error
+}
@@ -6,6 +6,7 @@ import (
 )
 
 // UserRepository は User 集約の永続化境界を表すドメイン側のポートです。
+// FindByID/UpdateProfile は対象のユーザーが存在しない場合 apperr.NotFound のエラーを返します。
 type UserRepository interface {
 	FindByID(ctx context.Context, id int64) (*entity.User, error)
 	UpdateProfile(ctx context.Context, id int64, displayName, pictureURL string) (*entity.User, error)
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+const (
+	apiKeyPrefix = "ak_"
+	// lastUsedResolution throttles last_used_at writes to one per key per minute.
+	lastUsedResolution = time.Minute
+)
+
+// IssueAPIKeyInput describes a key to issue.
+type IssueAPIKeyInput struct {
+	Name        string
+	OwnerUserID int64
+	Scopes      []string
+	// ExpiresAt nil means the key does not expire.
+	ExpiresAt *time.Time
+}
+
+// APIKeyUsecase issues, lists, revokes and authenticates API keys.
+type APIKeyUsecase struct {
+	repo domainrepo.APIKeyRepository
+	now  func() time.Time
+}
+
+func NewAPIKeyUsecase(repo domainrepo.APIKeyRepository) *APIKeyUsecase {
+	return &APIKeyUsecase{repo: repo, now: time.Now}
+}
+
+// Issue creates a key and returns it with its plaintext secret
+// ("ak_<8 hex>_<random>"). Only the SHA-256 of the secret is stored.
+func (u *APIKeyUsecase) Issue(ctx context.Context, in IssueAPIKeyInput) (*entity.APIKey, string, error) {
+	if strings.TrimSpace(in.Name) == "" {
+		return nil, "", ergo.WithCode(ergo.New("name is required", apperr.FieldViolation("name", "is required")), apperr.InvalidArgument)
+	}
+	if in.OwnerUserID <= 0 {
+		return nil, "", ergo.WithCode(ergo.New("owner is required", apperr.FieldViolation("owner_user_id", "is required")), apperr.InvalidArgument)
+	}
+	for _, s := range in.Scopes {
+		if s == "" || strings.ContainsAny(s, " \t\n") {
+			return nil, "", ergo.WithCode(ergo.New("scopes must be non-empty and contain no spaces", apperr.FieldViolation("scopes", "must be non-empty and contain no spaces")), apperr.InvalidArgument)
+		}
+	}
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(u.now()) {
+		return nil, "", ergo.WithCode(ergo.New("expires_at must be in the future", apperr.FieldViolation("expires_at", "must be in the future")), apperr.InvalidArgument)
+	}
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, "", ergo.WithCode(ergo.Wrap(err, "generate api key prefix"), apperr.Internal)
+	}
+	prefix := apiKeyPrefix + hex.EncodeToString(b[:])
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	plaintext := prefix + "_" + secret
+	k, err := u.repo.Create(ctx, &entity.APIKey{
+		Name:        in.Name,
+		Prefix:      prefix,
+		KeyHash:     hashAPIKey(plaintext),
+		OwnerUserID: in.OwnerUserID,
+		Scopes:      in.Scopes,
+		ExpiresAt:   in.ExpiresAt,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return k, plaintext, nil
+}
+
+// List returns every key (without secrets).
+func (u *APIKeyUsecase) List(ctx context.Context) ([]*entity.APIKey, error) {
+	return u.repo.List(ctx)
+}
+
+// Revoke disables a key immediately.
+func (u *APIKeyUsecase) Revoke(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return ergo.WithCode(ergo.New("id is required", apperr.FieldViolation("id", "is required")), apperr.InvalidArgument)
+	}
+	return u.repo.Revoke(ctx, id, u.now())
+}
+
+// Authenticate returns the key for a presented secret. Unknown, revoked and
+// expired keys are Unauthenticated.
+func (u *APIKeyUsecase) Authenticate(ctx context.Context, plaintext string) (*entity.APIKey, error) {
+	if !strings.HasPrefix(plaintext, apiKeyPrefix) {
+		return nil, ergo.WithCode(ergo.New("malformed api key"), apperr.Unauthenticated)
+	}
+	k, err := u.repo.FindByHash(ctx, hashAPIKey(plaintext))
+	if err != nil {
+		return nil, err
+	}
+	now := u.now()
+	switch {
+	case k == nil:
+		return nil, ergo.WithCode(ergo.New("unknown api key"), apperr.Unauthenticated)
+	case k.RevokedAt != nil:
+		return nil, ergo.WithCode(ergo.New("api key revoked"), apperr.Unauthenticated)
+	case k.ExpiresAt != nil && !now.Before(*k.ExpiresAt):
+		return nil, ergo.WithCode(ergo.New("api key expired"), apperr.Unauthenticated)
+	}
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= lastUsedResolution {
+		// Best effort: a failed bookkeeping write must not reject a valid key.
+		if err := u.repo.TouchLastUsed(ctx, k.ID, now); err == nil {
+			k.LastUsedAt = &now
+		}
+	}
+	return k, nil
+}
+
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,156 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// fakeAPIKeyRepo is an in-memory APIKeyRepository for unit tests.
+type fakeAPIKeyRepo struct {
+	mu      sync.Mutex
+	keys    []*entity.APIKey
+	touches int
+}
+
+func (r *fakeAPIKeyRepo) Create(ctx context.Context, k *entity.APIKey) (*entity.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *k
+	cp.ID = int64(len(r.keys) + 1)
+	cp.CreatedAt = time.Now()
+	r.keys = append(r.keys, &cp)
+	out := cp
+	return &out, nil
+}
+
+func (r *fakeAPIKeyRepo) FindByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.KeyHash == keyHash {
+			cp := *k
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeAPIKeyRepo) List(ctx context.Context) ([]*entity.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*entity.APIKey(nil), r.keys...), nil
+}
+
+func (r *fakeAPIKeyRepo) Revoke(ctx context.Context, id int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.ID == id {
+			k.RevokedAt = &at
+			return nil
+		}
+	}
+	return ergo.WithCode(ergo.New("api key not found"), apperr.NotFound)
+}
+
+func (r *fakeAPIKeyRepo) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.touches++
+	r.keys[id-1].LastUsedAt = &at
+	return nil
+}
+
+func TestAPIKeyUsecase(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeAPIKeyRepo{}
+	u := usecase.NewAPIKeyUsecase(repo)
+
+	k, secret, err := u.Issue(ctx, usecase.IssueAPIKeyInput{Name: "batch", OwnerUserID: 1, Scopes: []string{"sample.read"}})
+	if err != nil {
+		t.Fatalf("Issue() failed: %v", err)
+	}
+
+	t.Run("正常系: 平文は返却のみでハッシュだけが保存されること", func(t *testing.T) {
+		if !strings.HasPrefix(secret, k.Prefix+"_") {
+			t.Fatalf("secret %q must start with prefix %q", secret, k.Prefix)
+		}
+		if k.KeyHash == "" || strings.Contains(k.KeyHash, secret) {
+			t.Fatalf("key hash must not contain the secret: %q", k.KeyHash)
+		}
+	})
+
+	t.Run("正常系: 発行したキーで認証でき最終利用時刻は間引いて更新されること", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			got, err := u.Authenticate(ctx, secret)
+			if err != nil {
+				t.Fatalf("Authenticate() failed: %v", err)
+			}
+			if got.ID != k.ID || got.Scopes[0] != "sample.read" {
+				t.Fatalf("Authenticate() = %+v", got)
+			}
+		}
+		if repo.touches != 1 {
+			t.Fatalf("touches = %d, want 1", repo.touches)
+		}
+	})
+
+	t.Run("異常系: 未知のキーはUnauthenticatedになること", func(t *testing.T) {
+		if _, err := u.Authenticate(ctx, secret+"x"); ergo.CodeOf(err) != apperr.Unauthenticated {
+			t.Fatalf("want Unauthenticated, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 失効したキーはUnauthenticatedになること", func(t *testing.T) {
+		_, s2, err := u.Issue(ctx, usecase.IssueAPIKeyInput{Name: "partner", OwnerUserID: 1})
+		if err != nil {
+			t.Fatalf("Issue() failed: %v", err)
+		}
+		if err := u.Revoke(ctx, 2); err != nil {
+			t.Fatalf("Revoke() failed: %v", err)
+		}
+		if _, err := u.Authenticate(ctx, s2); ergo.CodeOf(err) != apperr.Unauthenticated {
+			t.Fatalf("want Unauthenticated, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 期限切れのキーはUnauthenticatedになること", func(t *testing.T) {
+		exp := time.Now().Add(50 * time.Millisecond)
+		_, s3, err := u.Issue(ctx, usecase.IssueAPIKeyInput{Name: "short", OwnerUserID: 1, ExpiresAt: &exp})
+		if err != nil {
+			t.Fatalf("Issue() failed: %v", err)
+		}
+		time.Sleep(60 * time.Millisecond)
+		if _, err := u.Authenticate(ctx, s3); ergo.CodeOf(err) != apperr.Unauthenticated {
+			t.Fatalf("want Unauthenticated, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 不正な入力はInvalidArgumentになること", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		for _, in := range []usecase.IssueAPIKeyInput{
+			{OwnerUserID: 1},
+			{Name: "x"},
+			{Name: "x", OwnerUserID: 1, Scopes: []string{"a b"}},
+			{Name: "x", OwnerUserID: 1, ExpiresAt: &past},
+		} {
+			if _, _, err := u.Issue(ctx, in); ergo.CodeOf(err) != apperr.InvalidArgument {
+				t.Fatalf("Issue(%+v): want InvalidArgument, got %v", in, err)
+			}
+		}
+	})
+
+	t.Run("異常系: 存在しないキーの失効はNotFoundになること", func(t *testing.T) {
+		if err := u.Revoke(ctx, 99); ergo.CodeOf(err) != apperr.NotFound {
+			t.Fatalf("want NotFound, got %v", err)
+		}
+	})
+}
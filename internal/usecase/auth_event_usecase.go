@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+const (
+	defaultAuthEventBuffer = 1024
+	authEventBatchSize     = 100
+	authEventWriteTimeout  = 5 * time.Second
+	defaultAuthEventLimit  = 50
+	maxAuthEventLimit      = 500
+)
+
+// AuthEventUsecase records authentication events asynchronously and lets
+// admins search them.
+//
+// Record never blocks the request: events are queued in memory and written in
+// batches by a background goroutine. When the queue is full (the database is
+// slow or down) events are dropped and counted (see Dropped); queued events
+// are lost if the process exits without Close.
+type AuthEventUsecase struct {
+	repo   domainrepo.AuthEventRepository
+	queue  chan *entity.AuthEvent
+	done   chan struct{}
+	closed sync.Once
+	// mu guards sends against a concurrent Close.
+	mu      sync.RWMutex
+	stopped bool
+	dropped atomic.Int64
+	now     func() time.Time
+}
+
+// NewAuthEventUsecase starts the writer; a zero buffer uses 1024 events.
+func NewAuthEventUsecase(repo domainrepo.AuthEventRepository, buffer int) *AuthEventUsecase {
+	if buffer <= 0 {
+		buffer = defaultAuthEventBuffer
+	}
+	u := &AuthEventUsecase{
+		repo:  repo,
+		queue: make(chan *entity.AuthEvent, buffer),
+		done:  make(chan struct{}),
+		now:   time.Now,
+	}
+	go u.run()
+	return u
+}
+
+// Record queues the event; OccurredAt defaults to now.
+func (u *AuthEventUsecase) Record(e *entity.AuthEvent) {
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = u.now()
+	}
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if u.stopped {
+		u.dropped.Add(1)
+		return
+	}
+	select {
+	case u.queue <- e:
+	default:
+		if u.dropped.Add(1)%authEventBatchSize == 1 {
+			slog.Warn("auth event queue is full; dropping events", slog.Int64("dropped", u.dropped.Load()))
+		}
+	}
+}
+
+// Dropped is the number of events discarded because the queue was full.
+func (u *AuthEventUsecase) Dropped() int64 { return u.dropped.Load() }
+
+// Close stops accepting events and waits until the queued ones are written
+// or ctx is done.
+func (u *AuthEventUsecase) Close(ctx context.Context) error {
+	u.closed.Do(func() {
+		u.mu.Lock()
+		u.stopped = true
+		close(u.queue)
+		u.mu.Unlock()
+	})
+	select {
+	case <-u.done:
+		return nil
+	case <-ctx.Done():
+		return ergo.Wrap(ctx.Err(), "flush auth events")
+	}
+}
+
+func (u *AuthEventUsecase) run() {
+	defer close(u.done)
+	batch := make([]*entity.AuthEvent, 0, authEventBatchSize)
+	for e := range u.queue {
+		batch = append(batch[:0], e)
+	fill:
+		for len(batch) < authEventBatchSize {
+			select {
+			case e, ok := <-u.queue:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, e)
+			default:
+				break fill
+			}
+		}
+		u.write(batch)
+	}
+}
+
+func (u *AuthEventUsecase) write(batch []*entity.AuthEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), authEventWriteTimeout)
+	defer cancel()
+	if err := u.repo.CreateBatch(ctx, batch); err != nil {
+		slog.Error("write auth events", slog.Int("count", len(batch)), slog.String("error", err.Error()))
+	}
+}
+
+// Search returns events matching the filter, newest first, and the BeforeID
+// of the next page (0 on the last page). Limit defaults to 50 and is capped
+// at 500.
+func (u *AuthEventUsecase) Search(ctx context.Context, f domainrepo.AuthEventFilter) ([]*entity.AuthEvent, int64, error) {
+	switch {
+	case f.Limit < 0:
+		return nil, 0, ergo.WithCode(ergo.New("limit must not be negative", slog.Int("limit", f.Limit), apperr.FieldViolation("page_size", "must not be negative")), apperr.InvalidArgument)
+	case f.Limit == 0:
+		f.Limit = defaultAuthEventLimit
+	case f.Limit > maxAuthEventLimit:
+		f.Limit = maxAuthEventLimit
+	}
+	if f.Outcome != "" && f.Outcome != entity.AuthEventSuccess && f.Outcome != entity.AuthEventFailure {
+		return nil, 0, ergo.WithCode(ergo.New("outcome must be success or failure", slog.String("outcome", f.Outcome), apperr.FieldViolation("outcome", "must be success or failure")), apperr.InvalidArgument)
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && !f.Since.Before(f.Until) {
+		return nil, 0, ergo.WithCode(ergo.New("since must be before until", apperr.FieldViolation("since", "must be before until")), apperr.InvalidArgument)
+	}
+	events, err := u.repo.Search(ctx, f)
+	if err != nil {
+		return nil, 0, err
+	}
+	var next int64
+	if len(events) == f.Limit {
+		next = events[len(events)-1].ID
+	}
+	return events, next, nil
+}
@@ -0,0 +1,141 @@
+package usecase_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// fakeAuthEventRepo stores events in memory. When block is set, writes wait
+// until it is closed.
+type fakeAuthEventRepo struct {
+	mu     sync.Mutex
+	events []*entity.AuthEvent
+	block  chan struct{}
+	filter domainrepo.AuthEventFilter
+}
+
+func (r *fakeAuthEventRepo) CreateBatch(ctx context.Context, events []*entity.AuthEvent) error {
+	if r.block != nil {
+		<-r.block
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range events {
+		e.ID = int64(len(r.events) + 1)
+		r.events = append(r.events, e)
+	}
+	return nil
+}
+
+func (r *fakeAuthEventRepo) Search(ctx context.Context, f domainrepo.AuthEventFilter) ([]*entity.AuthEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.filter = f
+	var out []*entity.AuthEvent
+	for i := len(r.events) - 1; i >= 0 && len(out) < f.Limit; i-- {
+		if e := r.events[i]; f.BeforeID == 0 || e.ID < f.BeforeID {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func TestAuthEventUsecase_Record(t *testing.T) {
+	t.Run("正常系: 記録したイベントがCloseまでに書き込まれること", func(t *testing.T) {
+		repo := &fakeAuthEventRepo{}
+		uc := usecase.NewAuthEventUsecase(repo, 0)
+		for range 250 {
+			uc.Record(&entity.AuthEvent{Outcome: entity.AuthEventFailure, Reason: "expired"})
+		}
+		if err := uc.Close(context.Background()); err != nil {
+			t.Fatalf("close err: %v", err)
+		}
+		if len(repo.events) != 250 || repo.events[0].OccurredAt.IsZero() {
+			t.Fatalf("events = %d", len(repo.events))
+		}
+		uc.Record(&entity.AuthEvent{Outcome: entity.AuthEventSuccess})
+		if uc.Dropped() != 1 {
+			t.Fatalf("events after Close must be dropped: %d", uc.Dropped())
+		}
+	})
+
+	t.Run("正常系: キューが満杯の場合はブロックせずに破棄すること", func(t *testing.T) {
+		repo := &fakeAuthEventRepo{block: make(chan struct{})}
+		uc := usecase.NewAuthEventUsecase(repo, 2)
+		for range 10 {
+			uc.Record(&entity.AuthEvent{Outcome: entity.AuthEventSuccess})
+		}
+		// The blocked writer holds at most one batch (the first event plus
+		// what it drained from the queue) and two more fit in the queue.
+		if uc.Dropped() < 5 {
+			t.Fatalf("dropped = %d, want >= 5", uc.Dropped())
+		}
+		close(repo.block)
+		if err := uc.Close(context.Background()); err != nil {
+			t.Fatalf("close err: %v", err)
+		}
+		if got := int64(len(repo.events)) + uc.Dropped(); got != 10 {
+			t.Fatalf("written + dropped = %d, want 10", got)
+		}
+	})
+}
+
+func TestAuthEventUsecase_Search(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeAuthEventRepo{}
+	uc := usecase.NewAuthEventUsecase(repo, 0)
+	for range 3 {
+		uc.Record(&entity.AuthEvent{Outcome: entity.AuthEventSuccess})
+	}
+	if err := uc.Close(ctx); err != nil {
+		t.Fatalf("close err: %v", err)
+	}
+
+	t.Run("正常系: 件数が上限に達した場合は次ページのIDを返すこと", func(t *testing.T) {
+		got, next, err := uc.Search(ctx, domainrepo.AuthEventFilter{Limit: 2})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if len(got) != 2 || got[0].ID != 3 || next != 2 {
+			t.Fatalf("got %d events, next = %d", len(got), next)
+		}
+		got, next, err = uc.Search(ctx, domainrepo.AuthEventFilter{Limit: 2, BeforeID: next})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if len(got) != 1 || next != 0 {
+			t.Fatalf("got %d events, next = %d", len(got), next)
+		}
+	})
+
+	t.Run("正常系: 件数は既定50件・最大500件であること", func(t *testing.T) {
+		if _, _, err := uc.Search(ctx, domainrepo.AuthEventFilter{}); err != nil || repo.filter.Limit != 50 {
+			t.Fatalf("limit = %d, err = %v", repo.filter.Limit, err)
+		}
+		if _, _, err := uc.Search(ctx, domainrepo.AuthEventFilter{Limit: 10000}); err != nil || repo.filter.Limit != 500 {
+			t.Fatalf("limit = %d, err = %v", repo.filter.Limit, err)
+		}
+	})
+
+	tests := []struct {
+		name string
+		f    domainrepo.AuthEventFilter
+	}{
+		{"異常系: outcomeが不正な場合はInvalidArgumentになること", domainrepo.AuthEventFilter{Outcome: "unknown"}},
+		{"異常系: 件数が負の場合はInvalidArgumentになること", domainrepo.AuthEventFilter{Limit: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := uc.Search(ctx, tt.f); ergo.CodeOf(err) != apperr.InvalidArgument {
+				t.Fatalf("want InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
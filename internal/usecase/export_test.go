@@ -0,0 +1,15 @@
+package usecase
+
+import "time"
+
+// SetSessionClock replaces the clock of a SessionUsecase in tests.
+func SetSessionClock(u *SessionUsecase, now func() time.Time) { u.now = now }
+
+// SetRevocationClock replaces the clock of a TokenRevocationUsecase in tests.
+func SetRevocationClock(u *TokenRevocationUsecase, now func() time.Time) { u.now = now }
+
+// SetRoleClock replaces the clock of a RoleUsecase in tests.
+func SetRoleClock(u *RoleUsecase, now func() time.Time) { u.now = now }
+
+// SetLocalAuthClock replaces the clock of a LocalAuthUsecase in tests.
+func SetLocalAuthClock(u *LocalAuthUsecase, now func() time.Time) { u.now = now }
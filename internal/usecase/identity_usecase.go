@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+// ExternalIdentity is an authenticated identity asserted by an external IdP.
+type ExternalIdentity struct {
+	Provider    string
+	Issuer      string
+	Subject     string
+	Email       string
+	DisplayName string
+	// LoginAt is when the IdP authenticated the user (iat/auth_time). Zero means now.
+	LoginAt time.Time
+}
+
+// IdentityUsecase resolves external identities to internal users, creating
+// users and user_identities rows on first login (just-in-time provisioning).
+type IdentityUsecase struct {
+	repo domainrepo.UserIdentityRepository
+	now  func() time.Time
+}
+
+func NewIdentityUsecase(repo domainrepo.UserIdentityRepository) *IdentityUsecase {
+	return &IdentityUsecase{repo: repo, now: time.Now}
+}
+
+// ResolveUserID returns the internal users.id for the identity.
+// last_login_at / email_at_provider are updated only when the IdP login is newer
+// than the recorded one, so repeated requests with the same token do not write.
+func (u *IdentityUsecase) ResolveUserID(ctx context.Context, in ExternalIdentity) (int64, error) {
+	if in.Issuer == "" || in.Subject == "" {
+		return 0, ergo.WithCode(ergo.New("identity requires issuer and subject"), apperr.Unauthenticated)
+	}
+	loginAt := in.LoginAt
+	if loginAt.IsZero() {
+		loginAt = u.now()
+	}
+	id, err := u.repo.FindByIssuerSubject(ctx, in.Issuer, in.Subject)
+	if err != nil {
+		return 0, err
+	}
+	if id != nil {
+		if id.LastLoginAt == nil || loginAt.After(*id.LastLoginAt) || (in.Email != "" && in.Email != id.EmailAtProvider) {
+			if err := u.repo.TouchLogin(ctx, id, in.Email, loginAt); err != nil {
+				return 0, err
+			}
+		}
+		return id.UserID, nil
+	}
+
+	user := &entity.User{Email: in.Email, DisplayName: in.DisplayName}
+	if user.Email == "" {
+		// users.email is NOT NULL + UNIQUE; IdPs such as Cognito omit email from access tokens.
+		user.Email = placeholderEmail(in.Issuer, in.Subject)
+	}
+	if user.DisplayName == "" {
+		user.DisplayName = user.Email
+	}
+	created, err := u.repo.Provision(ctx, user, &entity.UserIdentity{
+		Provider:        in.Provider,
+		Issuer:          in.Issuer,
+		Subject:         in.Subject,
+		EmailAtProvider: in.Email,
+	})
+	if err != nil {
+		if ergo.CodeOf(err) != apperr.Conflict {
+			return 0, err
+		}
+		// A concurrent first login may have provisioned the same identity.
+		id, ferr := u.repo.FindByIssuerSubject(ctx, in.Issuer, in.Subject)
+		if ferr != nil {
+			return 0, ferr
+		}
+		if id == nil {
+			// The email belongs to another user; identities are never linked implicitly.
+			return 0, ergo.WithCode(ergo.Wrap(err, "identity email belongs to another user",
+				slog.String("issuer", in.Issuer), slog.String("subject", in.Subject),
+				apperr.Reason(apperr.ReasonIdentityEmailInUse), apperr.ClientMessage("email is already used by another account")), apperr.Unauthenticated)
+		}
+		return id.UserID, nil
+	}
+	return created.UserID, nil
+}
+
+func placeholderEmail(issuer, subject string) string {
+	sum := sha256.Sum256([]byte(issuer + "\x00" + subject))
+	return "id-" + hex.EncodeToString(sum[:8]) + "@identity.invalid"
+}
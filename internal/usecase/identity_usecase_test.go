@@ -0,0 +1,123 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// fakeIdentityRepo is an in-memory UserIdentityRepository for unit tests.
+type fakeIdentityRepo struct {
+	mu         sync.Mutex
+	seq        int64
+	emails     map[string]int64
+	identities map[string]*entity.UserIdentity
+	touches    int
+}
+
+func newFakeIdentityRepo() *fakeIdentityRepo {
+	return &fakeIdentityRepo{emails: map[string]int64{}, identities: map[string]*entity.UserIdentity{}}
+}
+
+func (r *fakeIdentityRepo) FindByIssuerSubject(ctx context.Context, issuer, subject string) (*entity.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if v, ok := r.identities[issuer+"|"+subject]; ok {
+		cp := *v
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeIdentityRepo) Provision(ctx context.Context, user *entity.User, identity *entity.UserIdentity) (*entity.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.emails[user.Email]; ok {
+		return nil, ergo.WithCode(ergo.New("duplicate email"), apperr.Conflict)
+	}
+	r.seq++
+	r.emails[user.Email] = r.seq
+	now := time.Now()
+	cp := *identity
+	cp.ID = r.seq
+	cp.UserID = r.seq
+	cp.LastLoginAt = &now
+	r.identities[cp.Issuer+"|"+cp.Subject] = &cp
+	out := cp
+	return &out, nil
+}
+
+func (r *fakeIdentityRepo) TouchLogin(ctx context.Context, identity *entity.UserIdentity, emailAtProvider string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.touches++
+	v := r.identities[identity.Issuer+"|"+identity.Subject]
+	v.LastLoginAt = &at
+	if emailAtProvider != "" {
+		v.EmailAtProvider = emailAtProvider
+	}
+	return nil
+}
+
+func TestIdentityUsecase_ResolveUserID(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeIdentityRepo()
+	u := usecase.NewIdentityUsecase(repo)
+	in := usecase.ExternalIdentity{Provider: "keycloak", Issuer: "https://kc", Subject: "uuid-1", Email: "a@example.com", LoginAt: time.Now().Add(time.Minute)}
+
+	t.Run("正常系: 初回ログインでユーザーとひも付けが作成されること", func(t *testing.T) {
+		got, err := u.ResolveUserID(ctx, in)
+		if err != nil {
+			t.Fatalf("ResolveUserID() failed: %v", err)
+		}
+		if got != 1 {
+			t.Fatalf("ResolveUserID() = %d, want 1", got)
+		}
+	})
+
+	t.Run("正常系: 2回目以降は同じ内部IDを返し新しいログインでのみ更新されること", func(t *testing.T) {
+		got, err := u.ResolveUserID(ctx, in)
+		if err != nil {
+			t.Fatalf("ResolveUserID() failed: %v", err)
+		}
+		if got != 1 {
+			t.Fatalf("ResolveUserID() = %d, want 1", got)
+		}
+		if repo.touches != 1 {
+			t.Fatalf("touches = %d, want 1", repo.touches)
+		}
+		if _, err := u.ResolveUserID(ctx, in); err != nil {
+			t.Fatalf("ResolveUserID() failed: %v", err)
+		}
+		if repo.touches != 1 {
+			t.Fatalf("same login must not be written again: touches = %d", repo.touches)
+		}
+	})
+
+	t.Run("正常系: メールが無いIdPでもプレースホルダで作成されること", func(t *testing.T) {
+		got, err := u.ResolveUserID(ctx, usecase.ExternalIdentity{Provider: "cognito", Issuer: "https://cognito", Subject: "abc"})
+		if err != nil {
+			t.Fatalf("ResolveUserID() failed: %v", err)
+		}
+		if got != 2 {
+			t.Fatalf("ResolveUserID() = %d, want 2", got)
+		}
+	})
+
+	t.Run("異常系: 別IDのユーザーと同じメールの場合は認証エラーになりConflictが原因に残ること", func(t *testing.T) {
+		_, err := u.ResolveUserID(ctx, usecase.ExternalIdentity{Provider: "cognito", Issuer: "https://cognito", Subject: "other", Email: "a@example.com"})
+		if ergo.CodeOf(err) != apperr.Unauthenticated {
+			t.Fatalf("want Unauthenticated, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "duplicate email") {
+			t.Fatalf("cause is lost: %v", err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// fakeUserRepo is an in-memory UserRepository for unit tests.
+type fakeUserRepo map[int64]*entity.User
+
+func (r fakeUserRepo) FindByID(ctx context.Context, id int64) (*entity.User, error) {
+	if u, ok := r[id]; ok {
+		return u, nil
+	}
+	return nil, ergo.WithCode(ergo.New("user not found"), apperr.NotFound)
+}
+
+func (r fakeUserRepo) UpdateProfile(ctx context.Context, id int64, displayName, pictureURL string) (*entity.User, error) {
+	u, err := r.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	u.DisplayName, u.PictureURL = displayName, pictureURL
+	return u, nil
+}
+
+// fakeImpersonationLogRepo records the audit rows in memory.
+type fakeImpersonationLogRepo struct{ logs []*entity.ImpersonationLog }
+
+func (r *fakeImpersonationLogRepo) Create(ctx context.Context, l *entity.ImpersonationLog) error {
+	r.logs = append(r.logs, l)
+	return nil
+}
+
+func TestImpersonationUsecase_Begin(t *testing.T) {
+	ctx := context.Background()
+	users := fakeUserRepo{
+		1: {ID: 1, Email: "admin@example.com", Roles: []string{"admin"}},
+		2: {ID: 2, Email: "customer@example.com", Roles: []string{"user"}},
+		3: {ID: 3, Email: "other-admin@example.com", Roles: []string{"admin"}},
+	}
+	admin := usecase.ImpersonationRequest{ActorUserID: 1, ActorSubject: "1", ActorRoles: []string{"admin"}, TargetUserID: 2, Procedure: "/user.v1.UserService/GetMe", Reason: "ticket-123"}
+
+	t.Run("正常系: 管理者は対象ユーザーとして振る舞え、監査ログが記録されること", func(t *testing.T) {
+		logs := &fakeImpersonationLogRepo{}
+		uc := usecase.NewImpersonationUsecase(users, logs, "")
+		got, err := uc.Begin(ctx, admin)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if got.ID != 2 {
+			t.Fatalf("target = %d, want 2", got.ID)
+		}
+		if len(logs.logs) != 1 {
+			t.Fatalf("logs = %d, want 1", len(logs.logs))
+		}
+		l := logs.logs[0]
+		if !l.Allowed || l.ActorUserID != 1 || l.TargetUserID != 2 || l.Procedure != admin.Procedure || l.Reason != "ticket-123" || l.CreatedAt.IsZero() {
+			t.Fatalf("log = %+v", l)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		role   string
+		modify func(*usecase.ImpersonationRequest)
+		want   ergo.Code
+	}{
+		{"異常系: 管理者ロールが無い場合はPermissionDeniedになること", "", func(r *usecase.ImpersonationRequest) { r.ActorRoles = []string{"user"} }, apperr.PermissionDenied},
+		{"異常系: AUTH_IMPERSONATION_ROLEで指定したロールが必要であること", "support", func(r *usecase.ImpersonationRequest) {}, apperr.PermissionDenied},
+		{"異常系: 自分自身にはなりすませないこと", "", func(r *usecase.ImpersonationRequest) { r.TargetUserID = 1 }, apperr.PermissionDenied},
+		{"異常系: sensitiveなプロシージャではPermissionDeniedになること", "", func(r *usecase.ImpersonationRequest) { r.Sensitive = true }, apperr.PermissionDenied},
+		{"異常系: 存在しないユーザーはNotFoundになること", "", func(r *usecase.ImpersonationRequest) { r.TargetUserID = 99 }, apperr.NotFound},
+		{"異常系: 管理者にはなりすませないこと", "", func(r *usecase.ImpersonationRequest) { r.TargetUserID = 3 }, apperr.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := &fakeImpersonationLogRepo{}
+			uc := usecase.NewImpersonationUsecase(users, logs, tt.role)
+			in := admin
+			tt.modify(&in)
+			_, err := uc.Begin(ctx, in)
+			if ergo.CodeOf(err) != tt.want {
+				t.Fatalf("code = %v, want %v (err: %v)", ergo.CodeOf(err), tt.want, err)
+			}
+			if len(logs.logs) != 1 || logs.logs[0].Allowed || logs.logs[0].DeniedReason == "" {
+				t.Fatalf("denied attempt must be logged: %+v", logs.logs)
+			}
+		})
+	}
+}
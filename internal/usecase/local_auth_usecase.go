@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+// AccessToken is a token minted for a local login.
+type AccessToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// AccessTokenIssuer mints access tokens that the auth interceptor accepts
+// (e.g. HS256 tokens whose sub is the internal user ID).
+type AccessTokenIssuer interface {
+	// IssueAccessToken mints a token for user; amr is the authentication methods
+	// used (e.g. "pwd") and authTime the time of the login.
+	IssueAccessToken(ctx context.Context, user *entity.User, amr []string, authTime time.Time) (*AccessToken, error)
+}
+
+// PasswordResetNotifier delivers a password reset token to the user (e.g. by email).
+type PasswordResetNotifier interface {
+	NotifyPasswordReset(ctx context.Context, user *entity.User, token string, expiresAt time.Time) error
+}
+
+// LocalAuthConfig tunes LocalAuthUsecase. Zero values use the defaults.
+type LocalAuthConfig struct {
+	// MaxFailures is the number of consecutive failed logins that locks the account (default 5).
+	MaxFailures int
+	// Lockout is how long a locked account rejects logins (default 15m).
+	Lockout time.Duration
+	// ResetTokenTTL is the lifetime of password reset tokens (default 30m).
+	ResetTokenTTL time.Duration
+	// MinPasswordLength is the minimum number of characters (default 8).
+	MinPasswordLength int
+}
+
+const maxPasswordLength = 256
+
+// dummyPasswordHash is verified for unknown emails so that a login takes
+// about as long whether or not the account exists.
+const dummyPasswordHash = "$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHRzb21lc2FsdA$0gHnFBDFo4Ws1ARN8SX03C5Sh1pX1Aj2YeyTgMzwmbc"
+
+// LocalAuthUsecase implements email/password accounts stored in
+// user_credentials: sign-up, login with lockout, password change and reset.
+type LocalAuthUsecase struct {
+	creds    domainrepo.CredentialRepository
+	users    domainrepo.UserRepository
+	tokens   AccessTokenIssuer
+	notifier PasswordResetNotifier
+	cfg      LocalAuthConfig
+	now      func() time.Time
+}
+
+func NewLocalAuthUsecase(creds domainrepo.CredentialRepository, users domainrepo.UserRepository, tokens AccessTokenIssuer, notifier PasswordResetNotifier, cfg LocalAuthConfig) *LocalAuthUsecase {
+	if cfg.MaxFailures <= 0 {
+		cfg.MaxFailures = 5
+	}
+	if cfg.Lockout <= 0 {
+		cfg.Lockout = 15 * time.Minute
+	}
+	if cfg.ResetTokenTTL <= 0 {
+		cfg.ResetTokenTTL = 30 * time.Minute
+	}
+	if cfg.MinPasswordLength <= 0 {
+		cfg.MinPasswordLength = 8
+	}
+	return &LocalAuthUsecase{creds: creds, users: users, tokens: tokens, notifier: notifier, cfg: cfg, now: time.Now}
+}
+
+// SignUp creates a user with a password. A registered email is Conflict.
+func (u *LocalAuthUsecase) SignUp(ctx context.Context, email, password, displayName string) (*entity.User, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.validatePassword("password", password); err != nil {
+		return nil, err
+	}
+	displayName = strings.TrimSpace(displayName)
+	if displayName == "" {
+		displayName = email
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	c, err := u.creds.Create(ctx, &entity.User{Email: email, DisplayName: displayName}, hash, u.now())
+	if ergo.CodeOf(err) == apperr.Conflict {
+		return nil, ergo.WithCode(ergo.Wrap(err, "sign up", apperr.Reason(apperr.ReasonEmailAlreadyRegistered),
+			apperr.Resource("user", email)), apperr.Conflict)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entity.User{ID: c.UserID, Email: email, DisplayName: displayName}, nil
+}
+
+// Login checks the password and issues an access token. Unknown emails,
+// wrong passwords and locked accounts are indistinguishable; MaxFailures
+// consecutive failures lock the account for Lockout.
+func (u *LocalAuthUsecase) Login(ctx context.Context, email, password string) (*AccessToken, error) {
+	invalid := ergo.WithCode(ergo.New("invalid email or password", apperr.Reason(apperr.ReasonInvalidCredentials), apperr.ClientMessage("invalid email or password")), apperr.Unauthenticated)
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, invalid
+	}
+	c, err := u.creds.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		_, _ = verifyPassword(dummyPasswordHash, password)
+		return nil, invalid
+	}
+	now := u.now()
+	if c.LockedUntil != nil && now.Before(*c.LockedUntil) {
+		// Answer exactly like a wrong password so that the lock does not
+		// reveal which emails have accounts, nor act as a password oracle.
+		_, _ = verifyPassword(dummyPasswordHash, password)
+		return nil, invalid
+	}
+	ok, err := verifyPassword(c.PasswordHash, password)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := u.creds.RecordFailure(ctx, c.UserID, u.cfg.MaxFailures, now.Add(u.cfg.Lockout)); err != nil {
+			return nil, err
+		}
+		return nil, invalid
+	}
+	if err := u.creds.RecordSuccess(ctx, c.UserID, now); err != nil {
+		return nil, err
+	}
+	user, err := u.users.FindByID(ctx, c.UserID)
+	if ergo.CodeOf(err) == apperr.NotFound {
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u.tokens.IssueAccessToken(ctx, user, []string{"pwd"}, now)
+}
+
+// ChangePassword replaces the password of the user after checking the current
+// one. Wrong current passwords count towards the same lockout as Login.
+func (u *LocalAuthUsecase) ChangePassword(ctx context.Context, userID int64, current, next string) error {
+	c, err := u.creds.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return ergo.WithCode(ergo.New("user has no local password", slog.Int64("user_id", userID)), apperr.NotFound)
+	}
+	now := u.now()
+	if c.LockedUntil != nil && now.Before(*c.LockedUntil) {
+		return ergo.WithCode(ergo.New("account is locked", slog.Int64("user_id", userID),
+			apperr.Reason(apperr.ReasonAccountLocked), apperr.RetryAfter(c.LockedUntil.Sub(now)), apperr.ClientMessage("account is locked")), apperr.PermissionDenied)
+	}
+	ok, err := verifyPassword(c.PasswordHash, current)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if err := u.creds.RecordFailure(ctx, userID, u.cfg.MaxFailures, now.Add(u.cfg.Lockout)); err != nil {
+			return err
+		}
+		return ergo.WithCode(ergo.New("current password is incorrect", apperr.ClientMessage("current password is incorrect")), apperr.PermissionDenied)
+	}
+	return u.setPassword(ctx, userID, next)
+}
+
+// RequestPasswordReset sends a one-time reset token to the owner of email.
+// It returns nil for unknown emails so that callers cannot probe accounts.
+func (u *LocalAuthUsecase) RequestPasswordReset(ctx context.Context, email string) error {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return err
+	}
+	c, err := u.creds.FindByEmail(ctx, email)
+	if err != nil || c == nil {
+		return err
+	}
+	user, err := u.users.FindByID(ctx, c.UserID)
+	if ergo.CodeOf(err) == apperr.NotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	now := u.now()
+	expiresAt := now.Add(u.cfg.ResetTokenTTL)
+	if err := u.creds.CreateResetToken(ctx, c.UserID, hashResetToken(token), expiresAt, now); err != nil {
+		return err
+	}
+	return u.notifier.NotifyPasswordReset(ctx, user, token, expiresAt)
+}
+
+// ResetPassword sets a new password with a token from RequestPasswordReset
+// and returns the user ID. Tokens are single use.
+func (u *LocalAuthUsecase) ResetPassword(ctx context.Context, token, password string) (int64, error) {
+	if err := u.validatePassword("new_password", password); err != nil {
+		return 0, err
+	}
+	userID, err := u.creds.ConsumeResetToken(ctx, hashResetToken(strings.TrimSpace(token)), u.now())
+	if err != nil {
+		return 0, err
+	}
+	if userID == 0 {
+		return 0, ergo.WithCode(ergo.New("invalid or expired reset token", apperr.Reason(apperr.ReasonResetTokenInvalid),
+			apperr.FieldViolation("token", "is invalid or expired")), apperr.InvalidArgument)
+	}
+	if err := u.setPassword(ctx, userID, password); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+func (u *LocalAuthUsecase) setPassword(ctx context.Context, userID int64, password string) error {
+	if err := u.validatePassword("new_password", password); err != nil {
+		return err
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	return u.creds.UpdatePassword(ctx, userID, hash, u.now())
+}
+
+// validatePassword checks the length; field names the request field in the error details.
+func (u *LocalAuthUsecase) validatePassword(field, password string) error {
+	n := utf8.RuneCountInString(password)
+	if n < u.cfg.MinPasswordLength {
+		msg := fmt.Sprintf("must be at least %d characters", u.cfg.MinPasswordLength)
+		return ergo.WithCode(ergo.New("password is too short", slog.Int("min", u.cfg.MinPasswordLength),
+			apperr.Reason(apperr.ReasonPasswordTooWeak), apperr.FieldViolation(field, msg)), apperr.InvalidArgument)
+	}
+	if n > maxPasswordLength {
+		msg := fmt.Sprintf("must be at most %d characters", maxPasswordLength)
+		return ergo.WithCode(ergo.New("password is too long", slog.Int("max", maxPasswordLength),
+			apperr.FieldViolation(field, msg)), apperr.InvalidArgument)
+	}
+	return nil
+}
+
+// hashResetToken returns the SHA-256 (hex) stored for a reset token.
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeEmail validates a bare address and lower-cases it.
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	a, err := mail.ParseAddress(email)
+	if err != nil || a.Address != email {
+		return "", ergo.WithCode(ergo.New("invalid email", apperr.FieldViolation("email", "must be a valid email address")), apperr.InvalidArgument)
+	}
+	return email, nil
+}
@@ -0,0 +1,285 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// fakeCredentialRepo is an in-memory CredentialRepository that also serves
+// as the UserRepository of the created users.
+type fakeCredentialRepo struct {
+	mu     sync.Mutex
+	nextID int64
+	users  map[int64]*entity.User
+	creds  map[int64]*entity.Credential
+	resets map[string]fakeResetToken
+}
+
+type fakeResetToken struct {
+	userID    int64
+	expiresAt time.Time
+	used      bool
+}
+
+func newFakeCredentialRepo() *fakeCredentialRepo {
+	return &fakeCredentialRepo{users: map[int64]*entity.User{}, creds: map[int64]*entity.Credential{}, resets: map[string]fakeResetToken{}}
+}
+
+func (r *fakeCredentialRepo) Create(ctx context.Context, user *entity.User, passwordHash string, at time.Time) (*entity.Credential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Email == user.Email {
+			return nil, ergo.WithCode(ergo.New("duplicate email"), apperr.Conflict)
+		}
+	}
+	r.nextID++
+	u := *user
+	u.ID = r.nextID
+	r.users[u.ID] = &u
+	c := &entity.Credential{UserID: u.ID, Email: u.Email, PasswordHash: passwordHash, PasswordChangedAt: at}
+	r.creds[u.ID] = c
+	cp := *c
+	return &cp, nil
+}
+
+func (r *fakeCredentialRepo) FindByEmail(ctx context.Context, email string) (*entity.Credential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.creds {
+		if c.Email == email {
+			cp := *c
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeCredentialRepo) FindByUserID(ctx context.Context, userID int64) (*entity.Credential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.creds[userID]; ok {
+		cp := *c
+		return &cp, nil
+	}
+	return nil, ergo.WithCode(ergo.New("user not found"), apperr.NotFound)
+}
+
+func (r *fakeCredentialRepo) RecordFailure(ctx context.Context, userID int64, maxFailures int, lockUntil time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.creds[userID]
+	c.FailedAttempts++
+	if c.FailedAttempts >= maxFailures {
+		c.LockedUntil, c.FailedAttempts = &lockUntil, 0
+	}
+	return nil
+}
+
+func (r *fakeCredentialRepo) RecordSuccess(ctx context.Context, userID int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.creds[userID]
+	c.FailedAttempts, c.LockedUntil = 0, nil
+	return nil
+}
+
+func (r *fakeCredentialRepo) UpdatePassword(ctx context.Context, userID int64, passwordHash string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.creds[userID]
+	c.PasswordHash, c.PasswordChangedAt, c.FailedAttempts, c.LockedUntil = passwordHash, at, 0, nil
+	return nil
+}
+
+func (r *fakeCredentialRepo) CreateResetToken(ctx context.Context, userID int64, tokenHash string, expiresAt, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resets[tokenHash] = fakeResetToken{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+func (r *fakeCredentialRepo) ConsumeResetToken(ctx context.Context, tokenHash string, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.resets[tokenHash]
+	if !ok || t.used || !at.Before(t.expiresAt) {
+		return 0, nil
+	}
+	t.used = true
+	r.resets[tokenHash] = t
+	return t.userID, nil
+}
+
+// FindByID and UpdateProfile implement UserRepository.
+func (r *fakeCredentialRepo) FindByID(ctx context.Context, id int64) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if u, ok := r.users[id]; ok {
+		cp := *u
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeCredentialRepo) UpdateProfile(ctx context.Context, id int64, displayName, pictureURL string) (*entity.User, error) {
+	return nil, ergo.New("not implemented")
+}
+
+// fakeTokenIssuer records the tokens it issues.
+type fakeTokenIssuer struct{ issued []*entity.User }
+
+func (i *fakeTokenIssuer) IssueAccessToken(ctx context.Context, user *entity.User, amr []string, authTime time.Time) (*usecase.AccessToken, error) {
+	i.issued = append(i.issued, user)
+	return &usecase.AccessToken{Token: "token-for-" + user.Email + "-" + strings.Join(amr, ","), ExpiresAt: authTime.Add(time.Hour)}, nil
+}
+
+// fakeResetNotifier keeps the last reset token.
+type fakeResetNotifier struct{ token string }
+
+func (n *fakeResetNotifier) NotifyPasswordReset(ctx context.Context, user *entity.User, token string, expiresAt time.Time) error {
+	n.token = token
+	return nil
+}
+
+func TestLocalAuthUsecase(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeCredentialRepo()
+	notifier := &fakeResetNotifier{}
+	u := usecase.NewLocalAuthUsecase(repo, repo, &fakeTokenIssuer{}, notifier, usecase.LocalAuthConfig{MaxFailures: 3, Lockout: time.Minute})
+
+	var userID int64
+	t.Run("正常系: サインアップしてログインできること", func(t *testing.T) {
+		user, err := u.SignUp(ctx, " Alice@Example.com ", "correct horse", "")
+		if err != nil {
+			t.Fatalf("SignUp() failed: %v", err)
+		}
+		userID = user.ID
+		if user.Email != "alice@example.com" || user.DisplayName != "alice@example.com" {
+			t.Fatalf("user = %+v", user)
+		}
+		if h := repo.creds[userID].PasswordHash; !strings.HasPrefix(h, "$argon2id$v=19$") || strings.Contains(h, "correct horse") {
+			t.Fatalf("password hash = %q", h)
+		}
+		tok, err := u.Login(ctx, "alice@example.com", "correct horse")
+		if err != nil {
+			t.Fatalf("Login() failed: %v", err)
+		}
+		if tok.Token != "token-for-alice@example.com-pwd" {
+			t.Fatalf("token = %q", tok.Token)
+		}
+	})
+
+	t.Run("異常系: 登録済みのメールアドレスはConflictになること", func(t *testing.T) {
+		_, err := u.SignUp(ctx, "alice@example.com", "another password", "")
+		if ergo.CodeOf(err) != apperr.Conflict || apperr.ReasonOf(err) != apperr.ReasonEmailAlreadyRegistered {
+			t.Fatalf("want Conflict (EMAIL_ALREADY_REGISTERED), got %v", err)
+		}
+	})
+
+	t.Run("異常系: 不正なメールアドレスや短いパスワードはInvalidArgumentになること", func(t *testing.T) {
+		if _, err := u.SignUp(ctx, "Bob <bob@example.com>", "long enough", ""); ergo.CodeOf(err) != apperr.InvalidArgument {
+			t.Fatalf("want InvalidArgument, got %v", err)
+		}
+		if _, err := u.SignUp(ctx, "bob@example.com", "short", ""); ergo.CodeOf(err) != apperr.InvalidArgument || apperr.ReasonOf(err) != apperr.ReasonPasswordTooWeak {
+			t.Fatalf("want InvalidArgument (PASSWORD_TOO_WEAK), got %v", err)
+		}
+	})
+
+	t.Run("異常系: 未登録のメールアドレスと誤ったパスワードは同じエラーになること", func(t *testing.T) {
+		_, errUnknown := u.Login(ctx, "nobody@example.com", "correct horse")
+		_, errWrong := u.Login(ctx, "alice@example.com", "wrong password")
+		if ergo.CodeOf(errUnknown) != apperr.Unauthenticated || ergo.CodeOf(errWrong) != apperr.Unauthenticated {
+			t.Fatalf("want Unauthenticated, got %v / %v", errUnknown, errWrong)
+		}
+		if errUnknown.Error() != errWrong.Error() {
+			t.Fatalf("errors differ: %q / %q", errUnknown, errWrong)
+		}
+		// Reset the failure count for the lockout test.
+		if _, err := u.Login(ctx, "alice@example.com", "correct horse"); err != nil {
+			t.Fatalf("Login() failed: %v", err)
+		}
+	})
+
+	t.Run("異常系: 連続して失敗するとロックされ正しいパスワードでも拒否されること", func(t *testing.T) {
+		now := time.Now()
+		usecase.SetLocalAuthClock(u, func() time.Time { return now })
+		for range 3 {
+			if _, err := u.Login(ctx, "alice@example.com", "wrong password"); ergo.CodeOf(err) != apperr.Unauthenticated {
+				t.Fatalf("want Unauthenticated, got %v", err)
+			}
+		}
+		_, errLocked := u.Login(ctx, "alice@example.com", "correct horse")
+		_, errUnknown := u.Login(ctx, "nobody@example.com", "correct horse")
+		if ergo.CodeOf(errLocked) != apperr.Unauthenticated || apperr.ReasonOf(errLocked) != apperr.ReasonInvalidCredentials {
+			t.Fatalf("want Unauthenticated (INVALID_CREDENTIALS), got %v", errLocked)
+		}
+		if errLocked.Error() != errUnknown.Error() {
+			t.Fatalf("locked account is distinguishable: %q / %q", errLocked, errUnknown)
+		}
+		now = now.Add(2 * time.Minute)
+		if _, err := u.Login(ctx, "alice@example.com", "correct horse"); err != nil {
+			t.Fatalf("Login() after lockout failed: %v", err)
+		}
+	})
+
+	t.Run("正常系: パスワードを変更すると新しいパスワードでログインできること", func(t *testing.T) {
+		if err := u.ChangePassword(ctx, userID, "wrong password", "new password 1"); ergo.CodeOf(err) != apperr.PermissionDenied {
+			t.Fatalf("want PermissionDenied, got %v", err)
+		}
+		if err := u.ChangePassword(ctx, userID, "correct horse", "new password 1"); err != nil {
+			t.Fatalf("ChangePassword() failed: %v", err)
+		}
+		if _, err := u.Login(ctx, "alice@example.com", "new password 1"); err != nil {
+			t.Fatalf("Login() failed: %v", err)
+		}
+	})
+
+	t.Run("異常系: 現在のパスワードを連続して誤るとロックされること", func(t *testing.T) {
+		now := time.Now()
+		usecase.SetLocalAuthClock(u, func() time.Time { return now })
+		for range 3 {
+			if err := u.ChangePassword(ctx, userID, "wrong password", "new password 2"); ergo.CodeOf(err) != apperr.PermissionDenied {
+				t.Fatalf("want PermissionDenied, got %v", err)
+			}
+		}
+		err := u.ChangePassword(ctx, userID, "new password 1", "new password 2")
+		if ergo.CodeOf(err) != apperr.PermissionDenied || apperr.ReasonOf(err) != apperr.ReasonAccountLocked {
+			t.Fatalf("want PermissionDenied (ACCOUNT_LOCKED), got %v", err)
+		}
+		if _, err := u.Login(ctx, "alice@example.com", "new password 1"); ergo.CodeOf(err) != apperr.Unauthenticated {
+			t.Fatalf("Login() while locked: want Unauthenticated, got %v", err)
+		}
+		now = now.Add(2 * time.Minute)
+		if _, err := u.Login(ctx, "alice@example.com", "new password 1"); err != nil {
+			t.Fatalf("Login() after lockout failed: %v", err)
+		}
+	})
+
+	t.Run("正常系: リセットトークンでパスワードを再設定でき、トークンは1回限りであること", func(t *testing.T) {
+		if err := u.RequestPasswordReset(ctx, "nobody@example.com"); err != nil || notifier.token != "" {
+			t.Fatalf("unknown email: err=%v token=%q", err, notifier.token)
+		}
+		if err := u.RequestPasswordReset(ctx, "alice@example.com"); err != nil {
+			t.Fatalf("RequestPasswordReset() failed: %v", err)
+		}
+		got, err := u.ResetPassword(ctx, notifier.token, "reset password 1")
+		if err != nil || got != userID {
+			t.Fatalf("ResetPassword() = %d, %v", got, err)
+		}
+		if _, err := u.Login(ctx, "alice@example.com", "reset password 1"); err != nil {
+			t.Fatalf("Login() failed: %v", err)
+		}
+		if _, err := u.ResetPassword(ctx, notifier.token, "reset password 2"); ergo.CodeOf(err) != apperr.InvalidArgument {
+			t.Fatalf("reused token: want InvalidArgument, got %v", err)
+		}
+	})
+}
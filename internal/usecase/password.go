@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/newmo-oss/ergo"
+	"golang.org/x/crypto/argon2"
+
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+)
+
+// argon2id parameters for new hashes (RFC 9106, second recommended option).
+// Existing hashes keep the parameters encoded in them.
+const (
+	argon2Time    = 3
+	argon2Memory  = 64 * 1024
+	argon2Threads = 4
+	argon2KeyLen  = 32
+	argon2SaltLen = 16
+)
+
+// hashPassword returns the argon2id hash of password in the PHC string format:
+// $argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>.
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", ergo.WithCode(ergo.Wrap(err, "generate password salt"), apperr.Internal)
+	}
+	key := argon2.IDKey([]byte(password), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argon2Memory, argon2Time, argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// verifyPassword reports whether password matches the PHC encoded argon2id hash.
+func verifyPassword(encoded, password string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false, ergo.WithCode(ergo.New("unsupported password hash"), apperr.Internal)
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, ergo.WithCode(ergo.New("unsupported argon2 version"), apperr.Internal)
+	}
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, ergo.WithCode(ergo.Wrap(err, "parse argon2 parameters"), apperr.Internal)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, ergo.WithCode(ergo.Wrap(err, "decode argon2 salt"), apperr.Internal)
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, ergo.WithCode(ergo.Wrap(err, "decode argon2 hash"), apperr.Internal)
+	}
+	got := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(want)))
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+const defaultRoleCacheTTL = 30 * time.Second
+
+// RoleUsecase grants and revokes database roles (roles / user_roles) and
+// serves them to the auth interceptor.
+//
+// Lookups are cached in memory for cacheTTL. Changes made through this
+// instance invalidate the user's entry immediately; changes made on another
+// instance or directly in the database take effect within cacheTTL.
+type RoleUsecase struct {
+	repo     domainrepo.RoleRepository
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	users map[int64]cachedRoles
+	// gens counts the invalidations per user. A lookup that raced with an
+	// invalidation does not write its (possibly stale) result to the cache.
+	// Only users whose roles changed through this instance have an entry.
+	gens map[int64]uint64
+}
+
+type cachedRoles struct {
+	roles []string
+	until time.Time
+}
+
+// NewRoleUsecase returns the usecase. A zero cacheTTL uses 30s.
+func NewRoleUsecase(repo domainrepo.RoleRepository, cacheTTL time.Duration) *RoleUsecase {
+	if cacheTTL <= 0 {
+		cacheTTL = defaultRoleCacheTTL
+	}
+	return &RoleUsecase{
+		repo:     repo,
+		cacheTTL: cacheTTL,
+		now:      time.Now,
+		users:    map[int64]cachedRoles{},
+		gens:     map[int64]uint64{},
+	}
+}
+
+// RolesForUser returns the user's database roles. The result must not be modified.
+func (u *RoleUsecase) RolesForUser(ctx context.Context, userID int64) ([]string, error) {
+	now := u.now()
+	u.mu.Lock()
+	c, ok := u.users[userID]
+	gen := u.gens[userID]
+	u.mu.Unlock()
+	if ok && now.Before(c.until) {
+		return c.roles, nil
+	}
+	roles, err := u.repo.RolesForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	u.mu.Lock()
+	if u.gens[userID] == gen {
+		u.users[userID] = cachedRoles{roles: roles, until: now.Add(u.cacheTTL)}
+		u.evictLocked(now)
+	}
+	u.mu.Unlock()
+	return roles, nil
+}
+
+// GrantRole grants a role defined in the roles table to the user.
+func (u *RoleUsecase) GrantRole(ctx context.Context, userID int64, role string) error {
+	role, err := validateRoleChange(userID, role)
+	if err != nil {
+		return err
+	}
+	if err := u.repo.Grant(ctx, userID, role, u.now()); err != nil {
+		return err
+	}
+	u.Invalidate(userID)
+	return nil
+}
+
+// RevokeRole removes a role from the user.
+func (u *RoleUsecase) RevokeRole(ctx context.Context, userID int64, role string) error {
+	role, err := validateRoleChange(userID, role)
+	if err != nil {
+		return err
+	}
+	if err := u.repo.Revoke(ctx, userID, role); err != nil {
+		return err
+	}
+	u.Invalidate(userID)
+	return nil
+}
+
+// Invalidate drops the cached roles of the user. Call it after changing
+// user_roles outside this usecase.
+func (u *RoleUsecase) Invalidate(userID int64) {
+	u.mu.Lock()
+	delete(u.users, userID)
+	u.gens[userID]++
+	u.mu.Unlock()
+}
+
+func validateRoleChange(userID int64, role string) (string, error) {
+	if userID <= 0 {
+		return "", ergo.WithCode(ergo.New("user_id is required", apperr.FieldViolation("user_id", "is required")), apperr.InvalidArgument)
+	}
+	role = strings.TrimSpace(role)
+	if role == "" {
+		return "", ergo.WithCode(ergo.New("role is required", apperr.FieldViolation("role", "is required")), apperr.InvalidArgument)
+	}
+	return role, nil
+}
+
+// evictLocked drops stale entries once the cache grows large.
+func (u *RoleUsecase) evictLocked(now time.Time) {
+	const maxEntries = 10000
+	if len(u.users) > maxEntries {
+		for k, c := range u.users {
+			if !now.Before(c.until) {
+				delete(u.users, k)
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package usecase_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// fakeRoleRepo is an in-memory RoleRepository for unit tests.
+type fakeRoleRepo struct {
+	mu      sync.Mutex
+	defined map[string]bool
+	grants  map[int64][]string
+	lookups int
+	// afterLookup runs after RolesForUser read the grants, before it returns.
+	afterLookup func()
+}
+
+func newFakeRoleRepo(defined ...string) *fakeRoleRepo {
+	r := &fakeRoleRepo{defined: map[string]bool{}, grants: map[int64][]string{}}
+	for _, name := range defined {
+		r.defined[name] = true
+	}
+	return r
+}
+
+func (r *fakeRoleRepo) RolesForUser(ctx context.Context, userID int64) ([]string, error) {
+	r.mu.Lock()
+	r.lookups++
+	roles, hook := slices.Clone(r.grants[userID]), r.afterLookup
+	r.mu.Unlock()
+	if hook != nil {
+		hook()
+	}
+	return roles, nil
+}
+
+func (r *fakeRoleRepo) Grant(ctx context.Context, userID int64, role string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.defined[role] {
+		return ergo.WithCode(ergo.New("role not found"), apperr.NotFound)
+	}
+	if !slices.Contains(r.grants[userID], role) {
+		r.grants[userID] = append(r.grants[userID], role)
+	}
+	return nil
+}
+
+func (r *fakeRoleRepo) Revoke(ctx context.Context, userID int64, role string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.defined[role] {
+		return ergo.WithCode(ergo.New("role not found"), apperr.NotFound)
+	}
+	r.grants[userID] = slices.DeleteFunc(r.grants[userID], func(s string) bool { return s == role })
+	return nil
+}
+
+func TestRoleUsecase(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRoleRepo("admin", "editor")
+	u := usecase.NewRoleUsecase(repo, time.Minute)
+
+	t.Run("正常系: 付与したロールが返ること", func(t *testing.T) {
+		if err := u.GrantRole(ctx, 1, "editor"); err != nil {
+			t.Fatalf("GrantRole() failed: %v", err)
+		}
+		got, err := u.RolesForUser(ctx, 1)
+		if err != nil {
+			t.Fatalf("RolesForUser() failed: %v", err)
+		}
+		if !slices.Equal(got, []string{"editor"}) {
+			t.Fatalf("roles = %v", got)
+		}
+	})
+
+	t.Run("正常系: 結果はキャッシュされDBを再参照しないこと", func(t *testing.T) {
+		before := repo.lookups
+		if _, err := u.RolesForUser(ctx, 1); err != nil {
+			t.Fatalf("RolesForUser() failed: %v", err)
+		}
+		if repo.lookups != before {
+			t.Fatalf("lookups = %d, want %d", repo.lookups, before)
+		}
+	})
+
+	t.Run("正常系: 付与・剥奪でキャッシュが即座に無効化されること", func(t *testing.T) {
+		if err := u.GrantRole(ctx, 1, "admin"); err != nil {
+			t.Fatalf("GrantRole() failed: %v", err)
+		}
+		got, _ := u.RolesForUser(ctx, 1)
+		if !slices.Contains(got, "admin") {
+			t.Fatalf("roles after grant = %v", got)
+		}
+		if err := u.RevokeRole(ctx, 1, "admin"); err != nil {
+			t.Fatalf("RevokeRole() failed: %v", err)
+		}
+		got, _ = u.RolesForUser(ctx, 1)
+		if slices.Contains(got, "admin") {
+			t.Fatalf("roles after revoke = %v", got)
+		}
+	})
+
+	t.Run("正常系: 参照中に剥奪された場合は古いロールをキャッシュしないこと", func(t *testing.T) {
+		if err := u.GrantRole(ctx, 3, "admin"); err != nil {
+			t.Fatalf("GrantRole() failed: %v", err)
+		}
+		repo.afterLookup = func() {
+			repo.afterLookup = nil
+			if err := u.RevokeRole(ctx, 3, "admin"); err != nil {
+				t.Errorf("RevokeRole() failed: %v", err)
+			}
+		}
+		// The lookup read the roles before the revoke, so it may return them once.
+		if got, _ := u.RolesForUser(ctx, 3); !slices.Equal(got, []string{"admin"}) {
+			t.Fatalf("roles during revoke = %v", got)
+		}
+		if got, _ := u.RolesForUser(ctx, 3); len(got) != 0 {
+			t.Fatalf("roles after revoke = %v, want none", got)
+		}
+	})
+
+	t.Run("正常系: 他インスタンスでの変更はキャッシュ期限後に反映されること", func(t *testing.T) {
+		now := time.Now()
+		u2 := usecase.NewRoleUsecase(repo, time.Minute)
+		usecase.SetRoleClock(u2, func() time.Time { return now })
+		if got, _ := u2.RolesForUser(ctx, 2); len(got) != 0 {
+			t.Fatalf("roles = %v", got)
+		}
+		if err := u.GrantRole(ctx, 2, "admin"); err != nil {
+			t.Fatalf("GrantRole() failed: %v", err)
+		}
+		if got, _ := u2.RolesForUser(ctx, 2); len(got) != 0 {
+			t.Fatalf("cached roles = %v", got)
+		}
+		now = now.Add(2 * time.Minute)
+		if got, _ := u2.RolesForUser(ctx, 2); !slices.Equal(got, []string{"admin"}) {
+			t.Fatalf("roles after cache expiry = %v", got)
+		}
+	})
+
+	t.Run("異常系: 未定義のロールはNotFoundになること", func(t *testing.T) {
+		if err := u.GrantRole(ctx, 1, "unknown"); ergo.CodeOf(err) != apperr.NotFound {
+			t.Fatalf("want NotFound, got %v", err)
+		}
+	})
+
+	t.Run("異常系: user_idやroleが空の場合はInvalidArgumentになること", func(t *testing.T) {
+		if err := u.GrantRole(ctx, 0, "admin"); ergo.CodeOf(err) != apperr.InvalidArgument {
+			t.Fatalf("want InvalidArgument, got %v", err)
+		}
+		if err := u.RevokeRole(ctx, 1, " "); ergo.CodeOf(err) != apperr.InvalidArgument {
+			t.Fatalf("want InvalidArgument, got %v", err)
+		}
+	})
+}
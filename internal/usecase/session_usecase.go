@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+const (
+	defaultSessionTTL         = 12 * time.Hour
+	defaultSessionMaxLifetime = 7 * 24 * time.Hour
+)
+
+// SessionUsecase creates, validates, slides and revokes server-side sessions.
+// Session IDs are bearer credentials: only their SHA-256 is stored (see
+// hashSessionID), so entity.Session.ID is the plaintext ID sent in the cookie
+// while the repository is given the hash.
+type SessionUsecase struct {
+	repo domainrepo.SessionRepository
+	// ttl is the idle timeout; each validation in the second half of the window slides it.
+	ttl time.Duration
+	// maxLifetime caps sliding: a session never outlives CreatedAt+maxLifetime.
+	maxLifetime time.Duration
+	now         func() time.Time
+}
+
+// NewSessionUsecase returns a SessionUsecase. Zero durations use the defaults (12h / 7d).
+func NewSessionUsecase(repo domainrepo.SessionRepository, ttl, maxLifetime time.Duration) *SessionUsecase {
+	if ttl <= 0 {
+		ttl = defaultSessionTTL
+	}
+	if maxLifetime <= 0 {
+		maxLifetime = defaultSessionMaxLifetime
+	}
+	return &SessionUsecase{repo: repo, ttl: ttl, maxLifetime: maxLifetime, now: time.Now}
+}
+
+// Create starts a session for the user after a successful login.
+func (u *SessionUsecase) Create(ctx context.Context, userID int64, userAgent, ip string) (*entity.Session, error) {
+	if userID <= 0 {
+		return nil, ergo.WithCode(ergo.New("session requires a user"), apperr.InvalidArgument)
+	}
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+	csrf, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	now := u.now()
+	s := &entity.Session{
+		ID:        id,
+		UserID:    userID,
+		UserAgent: userAgent,
+		IPAddress: ip,
+		CSRFToken: csrf,
+		ExpiresAt: now.Add(u.ttl),
+		CreatedAt: now,
+	}
+	stored := *s
+	stored.ID = hashSessionID(id)
+	if err := u.repo.Create(ctx, &stored); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate returns the session if it exists, is not revoked and has not expired.
+// When less than half of the TTL remains the expiry slides forward (bounded by
+// the max lifetime); slid reports that so the caller can refresh the cookie.
+func (u *SessionUsecase) Validate(ctx context.Context, id string) (s *entity.Session, slid bool, err error) {
+	if id == "" {
+		return nil, false, ergo.WithCode(ergo.New("missing session"), apperr.Unauthenticated)
+	}
+	hash := hashSessionID(id)
+	s, err = u.repo.FindByID(ctx, hash)
+	if err != nil {
+		return nil, false, err
+	}
+	now := u.now()
+	switch {
+	case s == nil:
+		return nil, false, ergo.WithCode(ergo.New("session not found"), apperr.Unauthenticated)
+	case s.RevokedAt != nil:
+		return nil, false, ergo.WithCode(ergo.New("session revoked"), apperr.Unauthenticated)
+	case !now.Before(s.ExpiresAt):
+		return nil, false, ergo.WithCode(ergo.New("session expired"), apperr.Unauthenticated)
+	}
+	if s.ExpiresAt.Sub(now) < u.ttl/2 {
+		next := now.Add(u.ttl)
+		if limit := s.CreatedAt.Add(u.maxLifetime); next.After(limit) {
+			next = limit
+		}
+		if next.After(s.ExpiresAt) {
+			if err := u.repo.Extend(ctx, hash, next); err != nil {
+				return nil, false, err
+			}
+			s.ExpiresAt = next
+			slid = true
+		}
+	}
+	s.ID = id
+	return s, slid, nil
+}
+
+// Revoke ends a single session (logout).
+func (u *SessionUsecase) Revoke(ctx context.Context, id string) error {
+	return u.repo.Revoke(ctx, hashSessionID(id), u.now())
+}
+
+// RevokeAll ends every session of the user (e.g. after a password change).
+func (u *SessionUsecase) RevokeAll(ctx context.Context, userID int64) error {
+	return u.repo.RevokeAllForUser(ctx, userID, u.now())
+}
+
+// newSessionID returns a random (version 4) UUID used as the cookie value.
+// Only its SHA-256 (hashSessionID) is stored in sessions.id CHAR(64).
+func newSessionID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", ergo.WithCode(ergo.Wrap(err, "generate session id"), apperr.Internal)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// hashSessionID returns the SHA-256 (hex) stored in sessions.id for a session ID.
+func hashSessionID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", ergo.WithCode(ergo.Wrap(err, "generate token"), apperr.Internal)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
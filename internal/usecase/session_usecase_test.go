@@ -0,0 +1,156 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/domain/entity"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// fakeSessionRepo is an in-memory SessionRepository for unit tests.
+type fakeSessionRepo struct {
+	mu       sync.Mutex
+	sessions map[string]*entity.Session
+}
+
+func newFakeSessionRepo() *fakeSessionRepo {
+	return &fakeSessionRepo{sessions: map[string]*entity.Session{}}
+}
+
+func (r *fakeSessionRepo) Create(ctx context.Context, s *entity.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *s
+	r.sessions[s.ID] = &cp
+	return nil
+}
+
+func (r *fakeSessionRepo) FindByID(ctx context.Context, id string) (*entity.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if v, ok := r.sessions[id]; ok {
+		cp := *v
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeSessionRepo) Extend(ctx context.Context, id string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[id].ExpiresAt = expiresAt
+	return nil
+}
+
+func (r *fakeSessionRepo) Revoke(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if v, ok := r.sessions[id]; ok && v.RevokedAt == nil {
+		v.RevokedAt = &at
+	}
+	return nil
+}
+
+func (r *fakeSessionRepo) RevokeAllForUser(ctx context.Context, userID int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range r.sessions {
+		if v.UserID == userID && v.RevokedAt == nil {
+			v.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func TestSessionUsecase(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeSessionRepo()
+	u := usecase.NewSessionUsecase(repo, time.Hour, 2*time.Hour)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	usecase.SetSessionClock(u, func() time.Time { return now })
+
+	s, err := u.Create(ctx, 1, "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if len(s.ID) != 36 || s.CSRFToken == "" {
+		t.Fatalf("Create() = %+v, want uuid id and csrf token", s)
+	}
+
+	t.Run("正常系: セッションIDそのものではなくハッシュが保存されること", func(t *testing.T) {
+		if _, ok := repo.sessions[s.ID]; ok {
+			t.Fatal("plaintext session id must not be stored")
+		}
+		sum := sha256.Sum256([]byte(s.ID))
+		if _, ok := repo.sessions[hex.EncodeToString(sum[:])]; !ok {
+			t.Fatalf("stored ids = %v, want sha256 of %s", slices.Collect(maps.Keys(repo.sessions)), s.ID)
+		}
+	})
+
+	t.Run("正常系: 有効期限内のセッションが検証できること", func(t *testing.T) {
+		got, slid, err := u.Validate(ctx, s.ID)
+		if err != nil {
+			t.Fatalf("Validate() failed: %v", err)
+		}
+		if got.UserID != 1 || slid {
+			t.Fatalf("Validate() = %+v, slid=%v", got, slid)
+		}
+	})
+
+	t.Run("正常系: 残りがTTLの半分未満なら延長されること", func(t *testing.T) {
+		now = now.Add(40 * time.Minute)
+		got, slid, err := u.Validate(ctx, s.ID)
+		if err != nil {
+			t.Fatalf("Validate() failed: %v", err)
+		}
+		if !slid || !got.ExpiresAt.Equal(now.Add(time.Hour)) {
+			t.Fatalf("Validate() expires=%v slid=%v, want %v", got.ExpiresAt, slid, now.Add(time.Hour))
+		}
+	})
+
+	t.Run("正常系: 延長は最大有効期間を超えないこと", func(t *testing.T) {
+		now = now.Add(40 * time.Minute)
+		got, _, err := u.Validate(ctx, s.ID)
+		if err != nil {
+			t.Fatalf("Validate() failed: %v", err)
+		}
+		if want := s.CreatedAt.Add(2 * time.Hour); !got.ExpiresAt.Equal(want) {
+			t.Fatalf("ExpiresAt = %v, want %v", got.ExpiresAt, want)
+		}
+	})
+
+	t.Run("異常系: 期限切れのセッションはUnauthenticatedになること", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		if _, _, err := u.Validate(ctx, s.ID); ergo.CodeOf(err) != apperr.Unauthenticated {
+			t.Fatalf("want Unauthenticated, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 失効したセッションはUnauthenticatedになること", func(t *testing.T) {
+		s2, err := u.Create(ctx, 2, "", "")
+		if err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+		if err := u.Revoke(ctx, s2.ID); err != nil {
+			t.Fatalf("Revoke() failed: %v", err)
+		}
+		if _, _, err := u.Validate(ctx, s2.ID); ergo.CodeOf(err) != apperr.Unauthenticated {
+			t.Fatalf("want Unauthenticated, got %v", err)
+		}
+	})
+
+	t.Run("異常系: 存在しないセッションはUnauthenticatedになること", func(t *testing.T) {
+		if _, _, err := u.Validate(ctx, "00000000-0000-4000-8000-000000000000"); ergo.CodeOf(err) != apperr.Unauthenticated {
+			t.Fatalf("want Unauthenticated, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	domainrepo "github.com/xiao1203/go-onion-grpc-template/internal/domain/repository"
+)
+
+const defaultRevocationCacheTTL = 30 * time.Second
+
+// ErrTokenRevoked is wrapped by CheckToken errors for revoked tokens.
+var ErrTokenRevoked = ergo.NewSentinel("token revoked")
+
+// TokenRevocationUsecase revokes JWTs by jti or per user (issued-before
+// timestamp) and checks presented tokens against the revocations.
+//
+// Lookups are cached in memory for cacheTTL, so a revocation made on another
+// instance takes effect within that window; revocations made through this
+// instance take effect immediately.
+type TokenRevocationUsecase struct {
+	repo     domainrepo.TokenRevocationRepository
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	jtis  map[string]cachedRevocation
+	users map[int64]cachedRevocation
+}
+
+type cachedRevocation struct {
+	revoked bool
+	before  time.Time
+	until   time.Time
+}
+
+// NewTokenRevocationUsecase returns the usecase. A zero cacheTTL uses 30s.
+func NewTokenRevocationUsecase(repo domainrepo.TokenRevocationRepository, cacheTTL time.Duration) *TokenRevocationUsecase {
+	if cacheTTL <= 0 {
+		cacheTTL = defaultRevocationCacheTTL
+	}
+	return &TokenRevocationUsecase{
+		repo:     repo,
+		cacheTTL: cacheTTL,
+		now:      time.Now,
+		jtis:     map[string]cachedRevocation{},
+		users:    map[int64]cachedRevocation{},
+	}
+}
+
+// RevokeToken revokes a single token. expiresAt is its exp (zero: kept for 24h).
+func (u *TokenRevocationUsecase) RevokeToken(ctx context.Context, jti string, userID int64, expiresAt time.Time) error {
+	if jti == "" {
+		return ergo.WithCode(ergo.New("jti is required", apperr.FieldViolation("jti", "is required")), apperr.InvalidArgument)
+	}
+	now := u.now()
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(24 * time.Hour)
+	}
+	if err := u.repo.RevokeJTI(ctx, jti, userID, expiresAt, now); err != nil {
+		return err
+	}
+	u.mu.Lock()
+	u.jtis[jti] = cachedRevocation{revoked: true, until: expiresAt}
+	u.mu.Unlock()
+	return nil
+}
+
+// RevokeAllForUser invalidates every token of the user issued up to now.
+func (u *TokenRevocationUsecase) RevokeAllForUser(ctx context.Context, userID int64) error {
+	if userID <= 0 {
+		return ergo.WithCode(ergo.New("user_id is required", apperr.FieldViolation("user_id", "is required")), apperr.InvalidArgument)
+	}
+	// iat has second precision: tokens issued within the current second are revoked too.
+	before := u.now().Truncate(time.Second)
+	if err := u.repo.RevokeUserBefore(ctx, userID, before); err != nil {
+		return err
+	}
+	u.mu.Lock()
+	u.users[userID] = cachedRevocation{revoked: true, before: before, until: u.now().Add(u.cacheTTL)}
+	u.mu.Unlock()
+	return nil
+}
+
+// CheckToken returns Unauthenticated when the token's jti is revoked, or when
+// it was issued (iat) at or before the user's revocation timestamp.
+// A zero issuedAt is treated as revoked once the user has a revocation.
+func (u *TokenRevocationUsecase) CheckToken(ctx context.Context, jti string, userID int64, issuedAt time.Time) error {
+	if jti != "" {
+		revoked, err := u.jtiRevoked(ctx, jti)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return ergo.WithCode(ergo.Wrap(ErrTokenRevoked, "check jti", slog.String("jti", jti), apperr.Reason(apperr.ReasonTokenRevoked)), apperr.Unauthenticated)
+		}
+	}
+	if userID > 0 {
+		before, ok, err := u.userRevokedBefore(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if ok && (issuedAt.IsZero() || !issuedAt.After(before)) {
+			return ergo.WithCode(ergo.Wrap(ErrTokenRevoked, "check user revocation", slog.Int64("user_id", userID), apperr.Reason(apperr.ReasonTokenRevoked)), apperr.Unauthenticated)
+		}
+	}
+	return nil
+}
+
+func (u *TokenRevocationUsecase) jtiRevoked(ctx context.Context, jti string) (bool, error) {
+	now := u.now()
+	u.mu.Lock()
+	c, ok := u.jtis[jti]
+	u.mu.Unlock()
+	if ok && now.Before(c.until) {
+		return c.revoked, nil
+	}
+	revoked, err := u.repo.IsJTIRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+	u.mu.Lock()
+	u.jtis[jti] = cachedRevocation{revoked: revoked, until: now.Add(u.cacheTTL)}
+	u.evictLocked(now)
+	u.mu.Unlock()
+	return revoked, nil
+}
+
+func (u *TokenRevocationUsecase) userRevokedBefore(ctx context.Context, userID int64) (time.Time, bool, error) {
+	now := u.now()
+	u.mu.Lock()
+	c, ok := u.users[userID]
+	u.mu.Unlock()
+	if ok && now.Before(c.until) {
+		return c.before, c.revoked, nil
+	}
+	before, err := u.repo.RevokedBefore(ctx, userID)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	c = cachedRevocation{until: now.Add(u.cacheTTL)}
+	if before != nil {
+		c.revoked, c.before = true, *before
+	}
+	u.mu.Lock()
+	u.users[userID] = c
+	u.evictLocked(now)
+	u.mu.Unlock()
+	return c.before, c.revoked, nil
+}
+
+// evictLocked drops stale entries once the caches grow large.
+func (u *TokenRevocationUsecase) evictLocked(now time.Time) {
+	const maxEntries = 10000
+	if len(u.jtis) > maxEntries {
+		for k, c := range u.jtis {
+			if !now.Before(c.until) {
+				delete(u.jtis, k)
+			}
+		}
+	}
+	if len(u.users) > maxEntries {
+		for k, c := range u.users {
+			if !now.Before(c.until) {
+				delete(u.users, k)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package usecase_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/xiao1203/go-onion-grpc-template/internal/apperr"
+	"github.com/xiao1203/go-onion-grpc-template/internal/usecase"
+)
+
+// fakeRevocationRepo is an in-memory TokenRevocationRepository for unit tests.
+type fakeRevocationRepo struct {
+	mu      sync.Mutex
+	jtis    map[string]bool
+	users   map[int64]time.Time
+	lookups int
+}
+
+func newFakeRevocationRepo() *fakeRevocationRepo {
+	return &fakeRevocationRepo{jtis: map[string]bool{}, users: map[int64]time.Time{}}
+}
+
+func (r *fakeRevocationRepo) RevokeJTI(ctx context.Context, jti string, userID int64, expiresAt, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jtis[jti] = true
+	return nil
+}
+
+func (r *fakeRevocationRepo) IsJTIRevoked(ctx context.Context, jti string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	return r.jtis[jti], nil
+}
+
+func (r *fakeRevocationRepo) RevokeUserBefore(ctx context.Context, userID int64, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if before.After(r.users[userID]) {
+		r.users[userID] = before
+	}
+	return nil
+}
+
+func (r *fakeRevocationRepo) RevokedBefore(ctx context.Context, userID int64) (*time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	if v, ok := r.users[userID]; ok {
+		return &v, nil
+	}
+	return nil, nil
+}
+
+func TestTokenRevocationUsecase(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRevocationRepo()
+	u := usecase.NewTokenRevocationUsecase(repo, time.Minute)
+	issued := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	t.Run("正常系: 失効していないトークンは通ること", func(t *testing.T) {
+		if err := u.CheckToken(ctx, "jti-1", 1, issued); err != nil {
+			t.Fatalf("CheckToken() failed: %v", err)
+		}
+	})
+
+	t.Run("正常系: 結果はキャッシュされDBを再参照しないこと", func(t *testing.T) {
+		before := repo.lookups
+		if err := u.CheckToken(ctx, "jti-1", 1, issued); err != nil {
+			t.Fatalf("CheckToken() failed: %v", err)
+		}
+		if repo.lookups != before {
+			t.Fatalf("lookups = %d, want %d", repo.lookups, before)
+		}
+	})
+
+	t.Run("異常系: jtiを失効させると即座に拒否されること", func(t *testing.T) {
+		if err := u.RevokeToken(ctx, "jti-1", 1, time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("RevokeToken() failed: %v", err)
+		}
+		if err := u.CheckToken(ctx, "jti-1", 1, issued); ergo.CodeOf(err) != apperr.Unauthenticated {
+			t.Fatalf("want Unauthenticated, got %v", err)
+		}
+	})
+
+	t.Run("異常系: ユーザー単位の失効で失効前に発行されたトークンが拒否されること", func(t *testing.T) {
+		if err := u.RevokeAllForUser(ctx, 2); err != nil {
+			t.Fatalf("RevokeAllForUser() failed: %v", err)
+		}
+		if err := u.CheckToken(ctx, "jti-2", 2, issued); ergo.CodeOf(err) != apperr.Unauthenticated {
+			t.Fatalf("want Unauthenticated, got %v", err)
+		}
+		if err := u.CheckToken(ctx, "", 2, time.Time{}); ergo.CodeOf(err) != apperr.Unauthenticated {
+			t.Fatalf("token without iat: want Unauthenticated, got %v", err)
+		}
+	})
+
+	t.Run("正常系: ユーザー単位の失効後に発行されたトークンは通ること", func(t *testing.T) {
+		if err := u.CheckToken(ctx, "jti-3", 2, time.Now().Add(2*time.Second)); err != nil {
+			t.Fatalf("CheckToken() failed: %v", err)
+		}
+	})
+
+	t.Run("正常系: 他インスタンスでの失効はキャッシュ期限後に反映されること", func(t *testing.T) {
+		other := usecase.NewTokenRevocationUsecase(repo, time.Minute)
+		now := time.Now()
+		u2 := usecase.NewTokenRevocationUsecase(repo, time.Minute)
+		usecase.SetRevocationClock(u2, func() time.Time { return now })
+		if err := u2.CheckToken(ctx, "jti-4", 3, issued); err != nil {
+			t.Fatalf("CheckToken() failed: %v", err)
+		}
+		if err := other.RevokeAllForUser(ctx, 3); err != nil {
+			t.Fatalf("RevokeAllForUser() failed: %v", err)
+		}
+		if err := u2.CheckToken(ctx, "jti-4", 3, issued); err != nil {
+			t.Fatalf("cached result should still pass: %v", err)
+		}
+		now = now.Add(2 * time.Minute)
+		if err := u2.CheckToken(ctx, "jti-4", 3, issued); ergo.CodeOf(err) != apperr.Unauthenticated {
+			t.Fatalf("want Unauthenticated after cache expiry, got %v", err)
+		}
+	})
+}